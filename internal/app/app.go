@@ -18,9 +18,12 @@ import (
 	"github.com/pintoter/todo-list/internal/service"
 	"github.com/pintoter/todo-list/internal/transport"
 	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/blobstore"
 	"github.com/pintoter/todo-list/pkg/database/postgres"
+	"github.com/pintoter/todo-list/pkg/denylist"
 	"github.com/pintoter/todo-list/pkg/hash"
 	"github.com/pintoter/todo-list/pkg/logger"
+	"github.com/pintoter/todo-list/pkg/mailer"
 )
 
 // @title           			todo-list
@@ -51,15 +54,27 @@ func Run() {
 		logger.FatalKV(ctx, "Failed connect database", "err", err)
 	}
 
+	tokenManager := auth.NewManager(&cfg.Auth, initKeyRing(ctx, cfg))
+
 	deps := service.Deps{
-		Cfg:          &cfg.Auth,
-		Repo:         dbrepo.New(db),
-		Hasher:       hash.New(&cfg.Auth),
-		TokenManager: auth.NewManager(&cfg.Auth),
+		Cfg:            &cfg.Auth,
+		NotesCfg:       &cfg.Notes,
+		AttachmentsCfg: &cfg.Attachments,
+		Repo:           dbrepo.New(db),
+		Hasher:         hash.New(&cfg.Auth),
+		TokenManager:   tokenManager,
+		Denylist:       denylist.NewMemory(),
+		Mailer:         initMailer(ctx, cfg),
+		Blobs:          initBlobStore(ctx, cfg),
 	}
 
 	service := service.New(deps)
-	handler := transport.NewHandler(service, &cfg.Project)
+
+	purgerCtx, stopPurger := context.WithCancel(ctx)
+	defer stopPurger()
+	go service.RunPurger(purgerCtx)
+
+	handler := transport.NewHandler(service, tokenManager, &cfg.Project)
 	server := server.New(&cfg.HTTP, handler)
 
 	server.Run()
@@ -80,6 +95,55 @@ func Run() {
 	}
 }
 
+// initMailer picks the mail delivery, messages are sent through SMTP unless a development driver is set.
+func initMailer(ctx context.Context, cfg *config.Config) service.Mailer {
+	switch cfg.Mail.Driver {
+	case config.MailFile:
+		m, err := mailer.NewFile(cfg.Mail.Dir, cfg.Mail.GetFrom())
+		if err != nil {
+			logger.FatalKV(ctx, "Failed init mail directory", "err", err)
+		}
+		return m
+	case config.MailLog:
+		return mailer.NewLog()
+	}
+
+	return mailer.NewSMTP(&cfg.Mail)
+}
+
+// initBlobStore picks the storage of attachments, files are kept in the local directory by default.
+func initBlobStore(ctx context.Context, cfg *config.Config) service.BlobStore {
+	if cfg.Attachments.Storage == config.BlobS3 {
+		return blobstore.NewS3(&cfg.Attachments)
+	}
+
+	s, err := blobstore.NewLocal(cfg.Attachments.Dir)
+	if err != nil {
+		logger.FatalKV(ctx, "Failed init attachments directory", "err", err)
+	}
+	return s
+}
+
+// initKeyRing loads access token signing keys, tokens are signed with the shared secret while no keys are configured.
+func initKeyRing(ctx context.Context, cfg *config.Config) *auth.KeyRing {
+	keys := cfg.Auth.GetKeys()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	files := make([]auth.KeyFile, 0, len(keys))
+	for _, key := range keys {
+		files = append(files, auth.KeyFile{ID: key.ID, Path: key.File, NotBefore: key.NotBefore, NotAfter: key.NotAfter})
+	}
+
+	ring, err := auth.LoadKeyRing(files)
+	if err != nil {
+		logger.FatalKV(ctx, "Failed load signing keys", "err", err)
+	}
+
+	return ring
+}
+
 func initLogger(ctx context.Context, cfg *config.Config) (syncFn func()) {
 	loggingLevel := zap.InfoLevel
 	if cfg.Project.Level == logger.DebugLevel {
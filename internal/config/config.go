@@ -9,6 +9,7 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
@@ -18,6 +19,11 @@ const (
 	envFile    = "./.env"
 	Production = "production"
 	Debug      = "debug"
+	MailSMTP   = "smtp"
+	MailFile   = "file"
+	MailLog    = "log"
+	BlobLocal  = "local"
+	BlobS3     = "s3"
 )
 
 type HTTP struct {
@@ -78,16 +84,89 @@ func (db *DB) GetConnMaxLifetime() time.Duration {
 }
 
 type Auth struct {
-	Salt            string
-	Secret          string
-	AccessTokenTTL  time.Duration
-	RefreshTokenTTL time.Duration
+	Salt                       string
+	Secret                     string
+	AccessTokenTTL             time.Duration
+	RefreshTokenTTL            time.Duration
+	HashTime                   uint32
+	HashMemory                 uint32
+	HashThreads                uint8
+	AdminKey                   string
+	Keys                       []Key
+	PasswordResetTTL           time.Duration
+	VerificationURL            string
+	VerificationTTL            time.Duration
+	VerificationResendInterval time.Duration
+	RequireVerifiedEmail       string
+	TwoFactorIssuer            string
+	TwoFactorChallengeTTL      time.Duration
+	InvitationTTL              time.Duration
+}
+
+// Key is a PEM file of an access token signing key with its validity window.
+type Key struct {
+	ID        string
+	File      string
+	NotBefore time.Time
+	NotAfter  time.Time
 }
 
 func (a *Auth) GetSalt() string {
 	return a.Salt
 }
 
+func (a *Auth) GetHashTime() uint32 {
+	return a.HashTime
+}
+
+func (a *Auth) GetHashMemory() uint32 {
+	return a.HashMemory
+}
+
+func (a *Auth) GetHashThreads() uint8 {
+	return a.HashThreads
+}
+
+func (a *Auth) GetAdminKey() string {
+	return a.AdminKey
+}
+
+func (a *Auth) GetKeys() []Key {
+	return a.Keys
+}
+
+func (a *Auth) GetPasswordResetTTL() time.Duration {
+	return a.PasswordResetTTL
+}
+
+func (a *Auth) GetVerificationURL() string {
+	return a.VerificationURL
+}
+
+func (a *Auth) GetVerificationTTL() time.Duration {
+	return a.VerificationTTL
+}
+
+func (a *Auth) GetVerificationResendInterval() time.Duration {
+	return a.VerificationResendInterval
+}
+
+func (a *Auth) GetRequireVerifiedEmail() string {
+	return a.RequireVerifiedEmail
+}
+
+func (a *Auth) GetTwoFactorIssuer() string {
+	return a.TwoFactorIssuer
+}
+
+func (a *Auth) GetTwoFactorChallengeTTL() time.Duration {
+	return a.TwoFactorChallengeTTL
+}
+
+func (a *Auth) GetInvitationTTL() time.Duration {
+	return a.InvitationTTL
+}
+
 func (a *Auth) GetSecret() string {
 	return a.Secret
 }
@@ -99,6 +178,96 @@ func (a *Auth) GetRefreshTokenTTL() time.Duration {
 	return a.RefreshTokenTTL
 }
 
+// Mail configures delivery of emails, Driver is one of MailSMTP, MailFile or MailLog.
+type Mail struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Dir      string
+}
+
+func (m *Mail) GetHost() string {
+	return m.Host
+}
+
+func (m *Mail) GetPort() string {
+	return m.Port
+}
+
+func (m *Mail) GetUsername() string {
+	return m.Username
+}
+
+func (m *Mail) GetPassword() string {
+	return m.Password
+}
+
+func (m *Mail) GetFrom() string {
+	return m.From
+}
+
+type Notes struct {
+	AutoComplete   bool
+	TrashRetention time.Duration
+	PurgeInterval  time.Duration
+}
+
+func (n *Notes) GetAutoComplete() bool {
+	return n.AutoComplete
+}
+
+func (n *Notes) GetTrashRetention() time.Duration {
+	return n.TrashRetention
+}
+
+func (n *Notes) GetPurgeInterval() time.Duration {
+	return n.PurgeInterval
+}
+
+// Attachments configures uploads to notes, Storage is one of BlobLocal or BlobS3.
+type Attachments struct {
+	MaxSize      int64
+	AllowedTypes []string
+	Storage      string
+	Dir          string
+	Endpoint     string
+	Region       string
+	Bucket       string
+	AccessKey    string `envconfig:"access_key"`
+	SecretKey    string `envconfig:"secret_key"`
+}
+
+func (a *Attachments) GetMaxSize() int64 {
+	return a.MaxSize
+}
+
+func (a *Attachments) GetAllowedTypes() []string {
+	return a.AllowedTypes
+}
+
+func (a *Attachments) GetEndpoint() string {
+	return a.Endpoint
+}
+
+func (a *Attachments) GetRegion() string {
+	return a.Region
+}
+
+func (a *Attachments) GetBucket() string {
+	return a.Bucket
+}
+
+func (a *Attachments) GetAccessKey() string {
+	return a.AccessKey
+}
+
+func (a *Attachments) GetSecretKey() string {
+	return a.SecretKey
+}
+
 type Project struct {
 	Name  string
 	Level string
@@ -118,11 +287,14 @@ func (t *Telemetry) GetGraylogPath() string {
 }
 
 type Config struct {
-	HTTP      HTTP
-	DB        DB
-	Auth      Auth
-	Project   Project
-	Telemetry Telemetry
+	HTTP        HTTP
+	DB          DB
+	Auth        Auth
+	Notes       Notes
+	Mail        Mail
+	Attachments Attachments
+	Project     Project
+	Telemetry   Telemetry
 }
 
 var config = new(Config)
@@ -146,7 +318,11 @@ func Get() *Config {
 	once.Do(func() {
 		var err error
 
-		err = viper.Unmarshal(config)
+		err = viper.Unmarshal(config, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+			mapstructure.StringToTimeHookFunc(time.RFC3339),
+		)))
 		if err != nil {
 			log.Fatal("reading config")
 		}
@@ -161,6 +337,16 @@ func Get() *Config {
 			log.Fatal("error: get env for auth")
 		}
 
+		err = envconfig.Process("mail", &config.Mail)
+		if err != nil {
+			log.Fatal("error: get env for mail")
+		}
+
+		err = envconfig.Process("attachments", &config.Attachments)
+		if err != nil {
+			log.Fatal("error: get env for attachments")
+		}
+
 		config.Project.Mode = os.Getenv("MODE")
 		if config.Project.Mode == "" {
 			config.Project.Mode = Debug
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Attachment is a file uploaded to a note, its content is kept in the blob store under BlobKey.
+type Attachment struct {
+	ID          int       `json:"id"`
+	NoteId      int       `json:"note_id"`
+	UserId      int       `json:"user_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	BlobKey     string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Comment is a message in the thread of a note, Mentions are logins of mentioned users who can see the note.
+type Comment struct {
+	ID        int        `json:"id"`
+	NoteId    int        `json:"note_id"`
+	UserId    int        `json:"user_id"`
+	Login     string     `json:"login,omitempty"`
+	Body      string     `json:"body"`
+	Mentions  []string   `json:"mentions,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
+
+// CommentsPage is a page of the thread oldest first, HasMore reports whether there are comments
+// after the page which NextCursor points to.
+type CommentsPage struct {
+	Comments   []Comment
+	HasMore    bool
+	NextCursor string
+}
+
+// CommentCursor points at the last comment of a page, the next page starts right after it.
+type CommentCursor struct {
+	ID int `json:"id"`
+}
+
+// ParseCommentCursor decodes a cursor issued by CommentCursor.String.
+func ParseCommentCursor(s string) (CommentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return CommentCursor{}, ErrInvalidCursor
+	}
+
+	var cursor CommentCursor
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return CommentCursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// String encodes the cursor into an opaque URL-safe token.
+func (c CommentCursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// mentionRegexp matches @login not preceded by a word character, so emails aren't taken for mentions.
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]+)`)
+
+// ParseMentions returns logins mentioned in the body in order of appearance without duplicates,
+// trailing dots are punctuation rather than a part of the login.
+func ParseMentions(body string) []string {
+	var logins []string
+	seen := make(map[string]struct{})
+	for _, match := range mentionRegexp.FindAllStringSubmatch(body, -1) {
+		login := strings.TrimRight(match[1], ".")
+		if login == "" {
+			continue
+		}
+
+		if _, ok := seen[login]; !ok {
+			seen[login] = struct{}{}
+			logins = append(logins, login)
+		}
+	}
+
+	return logins
+}
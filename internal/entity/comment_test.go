@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"no mentions", nil},
+		{"@bob take a look", []string{"bob"}},
+		{"cc @alice.smith, @bob and @alice.smith again.", []string{"alice.smith", "bob"}},
+		{"ask @bob.", []string{"bob"}},
+		{"write to bob@example.com", nil},
+		{"(@carol_1) @@dave @", []string{"carol_1"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ParseMentions(tt.body), tt.body)
+	}
+}
+
+func TestCommentCursor(t *testing.T) {
+	cursor := CommentCursor{ID: 12}
+
+	parsed, err := ParseCommentCursor(cursor.String())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	for _, s := range []string{"", "not base64!", "e30"} {
+		_, err = ParseCommentCursor(s)
+		assert.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
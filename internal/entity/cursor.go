@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// NoteCursor points at a note of a sorted listing holding values of every field
+// notes can be sorted by. Next page starts right after the note, Backward cursor
+// selects the page right before it. Order is a fingerprint of the sort order
+// the cursor was issued for, the cursor is rejected when the order changes.
+type NoteCursor struct {
+	Order    string     `json:"o"`
+	Backward bool       `json:"b,omitempty"`
+	ID       int        `json:"id"`
+	Title    string     `json:"t,omitempty"`
+	Date     time.Time  `json:"d"`
+	Status   string     `json:"s,omitempty"`
+	Priority string     `json:"p,omitempty"`
+	DueAt    *time.Time `json:"due,omitempty"`
+	Rank     float32    `json:"r,omitempty"`
+}
+
+// NotesPage is a page of notes listing. Total counts notes matching the filter on all pages,
+// HasMore reports whether there are notes after the page which NextCursor points to.
+type NotesPage struct {
+	Notes      []Note
+	Total      int
+	HasMore    bool
+	NextCursor string
+	PrevCursor string
+}
+
+func NewNoteCursor(note Note, filter NoteFilter, backward bool) NoteCursor {
+	return NoteCursor{
+		Order:    filter.Order(),
+		Backward: backward,
+		ID:       note.ID,
+		Title:    note.Title,
+		Date:     note.Date,
+		Status:   note.Status,
+		Priority: note.Priority,
+		DueAt:    note.DueAt,
+		Rank:     note.Rank,
+	}
+}
+
+// ParseNoteCursor decodes a cursor issued by NoteCursor.String.
+func ParseNoteCursor(s string) (NoteCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return NoteCursor{}, ErrInvalidCursor
+	}
+
+	var cursor NoteCursor
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return NoteCursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// String encodes the cursor into an opaque URL-safe token.
+func (c NoteCursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Order describes the sort order of the listing, search results are ranked by relevance to the query first,
+// so the order names a hash of the query the ranks were computed for.
+func (f NoteFilter) Order() string {
+	parts := make([]string, 0, len(f.Sort)+1)
+	if f.Query != "" {
+		hash := sha256.Sum256([]byte(f.Query))
+		parts = append(parts, "rank "+hex.EncodeToString(hash[:8]))
+	}
+
+	for _, s := range f.Sort {
+		parts = append(parts, s.Field+" "+s.Direction)
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteCursor(t *testing.T) {
+	dueAt := time.Date(2024, 1, 5, 17, 0, 0, 0, time.UTC)
+	note := Note{
+		ID:       7,
+		Title:    "title",
+		Date:     time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		Status:   StatusNotDone,
+		Priority: PriorityHigh,
+		DueAt:    &dueAt,
+		Rank:     0.0607927,
+	}
+	filter := NoteFilter{Query: "milk", Sort: []NoteSort{{Field: "priority", Direction: SortDesc}}}
+
+	cursor := NewNoteCursor(note, filter, true)
+	assert.Equal(t, "rank 8825e76530f87ff5,priority desc", cursor.Order)
+	assert.NotEqual(t, cursor.Order, NoteFilter{Query: "bread", Sort: filter.Sort}.Order(), "cursor of another query must be rejected")
+
+	parsed, err := ParseNoteCursor(cursor.String())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	for _, s := range []string{"", "not base64!", "bnVsbA", "e30"} {
+		_, err = ParseNoteCursor(s)
+		assert.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
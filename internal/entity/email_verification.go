@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// EmailVerification is a token of the link sent by email to confirm the address, only its hash is stored.
+type EmailVerification struct {
+	ID        int
+	UserId    int
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -3,18 +3,95 @@ package entity
 import "errors"
 
 var (
-	ErrNoteExists    = errors.New("note already exists")
-	ErrNoteNotExists = errors.New("note doesn't exist")
-	ErrInvalidAuth   = errors.New("missing authorization header")
-	ErrInvalidDate   = errors.New("invalid date")
-	ErrInvalidEmail  = errors.New("invalid email")
-	ErrInvalidId     = errors.New("invalid id")
-	ErrInvalidInput  = errors.New("invalid input parameters")
-	ErrInvalidPage   = errors.New("invalid page")
-	ErrInvalidStatus = errors.New("invalid status")
+	ErrNoteExists      = errors.New("note already exists")
+	ErrNoteNotExists   = errors.New("note doesn't exist")
+	ErrNoteForbidden   = errors.New("not enough permissions on the note")
+	ErrInvalidAuth     = errors.New("missing authorization header")
+	ErrInvalidDate     = errors.New("invalid date")
+	ErrInvalidDueAt    = errors.New("invalid due_at, expected RFC 3339 date-time")
+	ErrInvalidTimeZone = errors.New("invalid time zone")
+	ErrInvalidEmail    = errors.New("invalid email")
+	ErrInvalidId       = errors.New("invalid id")
+	ErrInvalidInput    = errors.New("invalid input parameters")
+	ErrInvalidPage     = errors.New("invalid page")
+	ErrInvalidStatus   = errors.New("invalid status")
+	ErrInvalidPriority = errors.New("invalid priority")
+	ErrInvalidSort     = errors.New("invalid sort")
+	ErrInvalidQuery    = errors.New("invalid search query")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+
+	ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+	ErrNoRecurrence      = errors.New("note isn't recurring")
 
 	ErrUserExists   = errors.New("user with input parameters already exists")
 	ErrUserNotExist = errors.New("user doesn't exist")
 
+	ErrInvalidPassword   = errors.New("password must be from 8 to 64 characters long")
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+	ErrEmailNotVerified         = errors.New("email isn't verified")
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication isn't enabled")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication isn't enrolled")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrInvalidChallenge     = errors.New("invalid or expired two-factor challenge")
+	ErrTwoFactorLocked      = errors.New("too many invalid two-factor codes, try again later")
+
 	ErrSessionDoesntExist = errors.New("session doesn't exist")
+	ErrTokenRevoked       = errors.New("token revoked")
+	ErrRefreshTokenReused = errors.New("refresh token has already been used, the session is revoked")
+	ErrInvalidAdminKey    = errors.New("invalid admin key")
+
+	ErrTokenNotExists    = errors.New("personal access token doesn't exist")
+	ErrInvalidTokenName  = errors.New("token name must be from 1 to 64 characters long")
+	ErrInvalidScope      = errors.New("invalid scope")
+	ErrInvalidExpiresAt  = errors.New("invalid expires_at, expected RFC 3339 date-time in the future")
+	ErrInsufficientScope = errors.New("token doesn't have the scope required")
+	ErrSessionRequired   = errors.New("personal access tokens aren't accepted, sign in is required")
+
+	ErrTagExists       = errors.New("tag already exists")
+	ErrTagNotExists    = errors.New("tag doesn't exist")
+	ErrInvalidTag      = errors.New("invalid tag")
+	ErrInvalidTagMatch = errors.New("invalid tags match mode")
+
+	ErrListExists    = errors.New("list already exists")
+	ErrListNotExists = errors.New("list doesn't exist")
+	ErrInvalidList   = errors.New("invalid list")
+
+	ErrRevisionNotExists = errors.New("revision doesn't exist")
+
+	ErrShareNotExists    = errors.New("note isn't shared with the user")
+	ErrInvalidPermission = errors.New("invalid permission, expected viewer or editor")
+	ErrShareWithOwner    = errors.New("note can't be shared with its owner")
+
+	ErrInvalidAssignee       = errors.New("invalid assignee, expected me, unassigned or user id")
+	ErrAssigneeNoAccess      = errors.New("assignee must have access to the note")
+	ErrNotificationNotExists = errors.New("notification doesn't exist")
+
+	ErrCommentNotExists = errors.New("comment doesn't exist")
+	ErrInvalidComment   = errors.New("comment must be from 1 to 4096 characters long")
+	ErrCommentForbidden = errors.New("only the author can change the comment")
+
+	ErrAttachmentNotExists = errors.New("attachment doesn't exist")
+	ErrInvalidAttachment   = errors.New("expected a non-empty file in the file field of a multipart form")
+	ErrAttachmentTooLarge  = errors.New("attachment is too large")
+	ErrAttachmentType      = errors.New("type of the file isn't allowed")
+	ErrAttachmentForbidden = errors.New("only the uploader can delete the attachment")
+	ErrInvalidRange        = errors.New("requested range not satisfiable")
+
+	ErrWorkspaceNotExists       = errors.New("workspace doesn't exist")
+	ErrInvalidWorkspaceId       = errors.New("invalid X-Workspace-Id header, expected workspace id")
+	ErrInvalidWorkspaceName     = errors.New("workspace name must be from 1 to 64 characters long")
+	ErrInvalidWorkspaceRole     = errors.New("invalid role, expected admin, member or guest")
+	ErrWorkspaceForbidden       = errors.New("not enough permissions in the workspace")
+	ErrWorkspaceMemberNotExists = errors.New("user isn't a member of the workspace")
+	ErrWorkspaceOwner           = errors.New("owner of the workspace can't be removed or change role")
+	ErrInvitationNotExists      = errors.New("invitation doesn't exist or expired")
+	ErrWorkspaceSharing         = errors.New("notes of workspaces can't be shared, invite the user to the workspace")
+
+	ErrItemNotExists     = errors.New("item doesn't exist")
+	ErrInvalidItem       = errors.New("invalid item")
+	ErrInvalidItemsOrder = errors.New("items order must contain every item of the note exactly once")
 )
@@ -0,0 +1,32 @@
+package entity
+
+// Item is a checklist item of a note, items are ordered by Position.
+type Item struct {
+	ID       int    `json:"id,omitempty"`
+	NoteId   int    `json:"note_id"`
+	Title    string `json:"title"`
+	Checked  bool   `json:"checked"`
+	Position int    `json:"position"`
+}
+
+// ItemUpdate holds changed fields of an item, empty Title and nil Checked are left untouched.
+type ItemUpdate struct {
+	Title   string
+	Checked *bool
+}
+
+// ItemsProgress returns the percentage of checked items rounded down.
+func ItemsProgress(items []Item) int {
+	if len(items) == 0 {
+		return 0
+	}
+
+	var checked int
+	for _, item := range items {
+		if item.Checked {
+			checked++
+		}
+	}
+
+	return checked * 100 / len(items)
+}
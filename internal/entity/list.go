@@ -0,0 +1,9 @@
+package entity
+
+// List groups notes of a user, notes without a list belong to the inbox.
+type List struct {
+	ID         int    `json:"id,omitempty"`
+	UserId     int    `json:"user_id"`
+	Name       string `json:"name"`
+	NotesCount int    `json:"notes_count"`
+}
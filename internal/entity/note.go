@@ -7,11 +7,96 @@ const (
 	StatusNotDone = "not_done"
 )
 
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+const (
+	TagsMatchAny = "any"
+	TagsMatchAll = "all"
+)
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// NoteSortFields is a whitelist of fields notes can be sorted by.
+var NoteSortFields = map[string]struct{}{
+	"id":       {},
+	"title":    {},
+	"date":     {},
+	"status":   {},
+	"priority": {},
+	"due_at":   {},
+}
+
 type Note struct {
-	ID          int       `json:"id,omitempty"`
-	UserId      int       `json:"user_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description,omitempty"`
-	Date        time.Time `json:"date"`
-	Status      string    `json:"status"`
+	ID            int         `json:"id,omitempty"`
+	UserId        int         `json:"user_id"`
+	Title         string      `json:"title"`
+	Description   string      `json:"description,omitempty"`
+	Date          time.Time   `json:"date"`
+	Status        string      `json:"status"`
+	Priority      string      `json:"priority"`
+	DueAt         *time.Time  `json:"due_at,omitempty"`
+	Recurrence    *Recurrence `json:"recurrence,omitempty"`
+	ListId        *int        `json:"list_id,omitempty"`
+	AssigneeId    *int        `json:"assignee_id,omitempty"`
+	Tags          []string    `json:"tags,omitempty"`
+	CommentsCount int         `json:"comments_count,omitempty"`
+	DeletedAt     *time.Time  `json:"deleted_at,omitempty"`
+	Snippet       string      `json:"snippet,omitempty"`
+	Rank          float32     `json:"-"`
+}
+
+// NoteUpdate holds changed fields of a note. Empty strings are left untouched,
+// nil Tags keeps the current tags and an empty slice removes them.
+// ClearRecurrence stops the repetition of the note, ListId pointing to zero moves the note to the inbox.
+type NoteUpdate struct {
+	Title           string
+	Description     string
+	Status          string
+	Priority        string
+	Date            time.Time
+	DueAt           *time.Time
+	Recurrence      *Recurrence
+	ClearRecurrence bool
+	ListId          *int
+	Tags            []string
+}
+
+type NoteSort struct {
+	Field     string
+	Direction string
+}
+
+// NoteFilter narrows notes listing, ListId pointing to zero selects notes from the inbox
+// and AssigneeId pointing to zero selects unassigned notes.
+// Non-empty Query switches listing to full-text search ranked by relevance.
+// Cursor selects notes after (or before) the note it points at.
+type NoteFilter struct {
+	Query      string
+	Status     string
+	Date       time.Time
+	Tags       []string
+	TagsMatch  string
+	DueBefore  time.Time
+	DueAfter   time.Time
+	Overdue    bool
+	ListId     *int
+	AssigneeId *int
+	Sort       []NoteSort
+	Cursor     *NoteCursor
+}
+
+func IsValidPriority(priority string) bool {
+	switch priority {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
 }
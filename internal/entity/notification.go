@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+const (
+	NotificationAssigned  = "assigned"
+	NotificationMentioned = "mentioned"
+)
+
+// Notification is an in-app notice for the user about something another user did, ActorId is the user
+// who did it and ReadAt is set once the user has seen the notification.
+type Notification struct {
+	ID        int        `json:"id"`
+	UserId    int        `json:"-"`
+	Type      string     `json:"type" enums:"assigned,mentioned"`
+	NoteId    *int       `json:"note_id,omitempty"`
+	ActorId   *int       `json:"actor_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
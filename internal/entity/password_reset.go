@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// PasswordReset is a single use token sent by email to set a new password, only its hash is stored.
+type PasswordReset struct {
+	ID        int
+	UserId    int
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// Scopes of personal access tokens, write doesn't imply read.
+const (
+	ScopeNotesRead  = "notes:read"
+	ScopeNotesWrite = "notes:write"
+)
+
+var Scopes = []string{ScopeNotesRead, ScopeNotesWrite}
+
+// PersonalAccessToken is a long-lived token for scripts, it's limited to its scopes and never expires
+// unless ExpiresAt is set. Only the hash of the token is stored.
+type PersonalAccessToken struct {
+	ID         int        `json:"id"`
+	UserId     int        `json:"-"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+func (t PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
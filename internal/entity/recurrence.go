@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// Weekdays maps RRULE-style BYDAY names to time.Weekday.
+var Weekdays = map[string]time.Weekday{
+	"mo": time.Monday,
+	"tu": time.Tuesday,
+	"we": time.Wednesday,
+	"th": time.Thursday,
+	"fr": time.Friday,
+	"sa": time.Saturday,
+	"su": time.Sunday,
+}
+
+// Recurrence is a repetition rule of a note. Count is the number of occurrences
+// left in the series including the current one, zero means unlimited. AnchorDay is the day of month
+// monthly and yearly series stick to, it's taken from the first occurrence unless set.
+type Recurrence struct {
+	Frequency string     `json:"frequency" enums:"daily,weekly,monthly,yearly"`
+	Interval  int        `json:"interval,omitempty"`
+	Weekdays  []string   `json:"weekdays,omitempty" example:"mo,we,fr"`
+	Count     int        `json:"count,omitempty"`
+	Until     *time.Time `json:"until,omitempty"`
+	AnchorDay int        `json:"anchor_day,omitempty" example:"31"`
+}
+
+func (r Recurrence) Validate() error {
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyMonthly, FrequencyYearly:
+		if len(r.Weekdays) > 0 {
+			return ErrInvalidRecurrence
+		}
+	case FrequencyWeekly:
+		for _, day := range r.Weekdays {
+			if _, ok := Weekdays[day]; !ok {
+				return ErrInvalidRecurrence
+			}
+		}
+	default:
+		return ErrInvalidRecurrence
+	}
+
+	if r.Interval < 0 || r.Count < 0 || r.AnchorDay < 0 || r.AnchorDay > 31 {
+		return ErrInvalidRecurrence
+	}
+
+	if r.Count > 0 && r.Until != nil {
+		return ErrInvalidRecurrence
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"reflect"
+	"time"
+)
+
+const (
+	RevisionCreate = "create"
+	RevisionUpdate = "update"
+	RevisionRevert = "revert"
+	RevisionAssign = "assign"
+)
+
+// Revision is a recorded change of a note made by the user. Snapshot is the state
+// of the note right after the change, it's used to revert the note and to compare revisions.
+type Revision struct {
+	ID        int               `json:"id"`
+	NoteId    int               `json:"note_id"`
+	UserId    int               `json:"user_id"`
+	Action    string            `json:"action" enums:"create,update,revert,assign"`
+	Changes   map[string]Change `json:"changes"`
+	Snapshot  Note              `json:"-"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// DiffNotes returns changed fields of the note keyed by their JSON names.
+func DiffNotes(from, to Note) map[string]Change {
+	fields := []struct {
+		name     string
+		old, new any
+	}{
+		{"title", from.Title, to.Title},
+		{"description", from.Description, to.Description},
+		{"status", from.Status, to.Status},
+		{"priority", from.Priority, to.Priority},
+		{"date", formatDate(from.Date), formatDate(to.Date)},
+		{"due_at", from.DueAt, to.DueAt},
+		{"recurrence", from.Recurrence, to.Recurrence},
+		{"list_id", from.ListId, to.ListId},
+		{"assignee_id", from.AssigneeId, to.AssigneeId},
+		{"tags", from.Tags, to.Tags},
+	}
+
+	changes := make(map[string]Change)
+	for _, field := range fields {
+		if isEmptyValue(field.old) && isEmptyValue(field.new) {
+			continue
+		}
+
+		if !reflect.DeepEqual(field.old, field.new) {
+			changes[field.name] = Change{Old: field.old, New: field.new}
+		}
+	}
+
+	return changes
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format("2006-01-02")
+}
+
+func isEmptyValue(value any) bool {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer:
+		return v.IsNil()
+	case reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffNotes(t *testing.T) {
+	listId := 1
+	date := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+	note := Note{
+		ID:       1,
+		UserId:   1,
+		Title:    "title",
+		Date:     date,
+		Status:   StatusNotDone,
+		Priority: PriorityMedium,
+		Tags:     []string{"work"},
+	}
+
+	tests := []struct {
+		name string
+		from Note
+		to   Note
+		want map[string]Change
+	}{
+		{
+			name: "NoChanges",
+			from: note,
+			to:   note,
+			want: map[string]Change{},
+		},
+		{
+			name: "EmptyTagsAreEqual",
+			from: Note{Title: "title", Tags: nil},
+			to:   Note{Title: "title", Tags: []string{}},
+			want: map[string]Change{},
+		},
+		{
+			name: "Changed",
+			from: note,
+			to: func() Note {
+				changed := note
+				changed.Status = StatusDone
+				changed.Date = date.AddDate(0, 0, 1)
+				changed.ListId = &listId
+				changed.Tags = nil
+				return changed
+			}(),
+			want: map[string]Change{
+				"status":  {Old: StatusNotDone, New: StatusDone},
+				"date":    {Old: "2024-02-15", New: "2024-02-16"},
+				"list_id": {Old: (*int)(nil), New: &listId},
+				"tags":    {Old: []string{"work"}, New: []string(nil)},
+			},
+		},
+		{
+			name: "Created",
+			from: Note{},
+			to:   note,
+			want: map[string]Change{
+				"title":    {Old: "", New: "title"},
+				"status":   {Old: "", New: StatusNotDone},
+				"priority": {Old: "", New: PriorityMedium},
+				"date":     {Old: "", New: "2024-02-15"},
+				"tags":     {Old: []string(nil), New: []string{"work"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffNotes(tt.from, tt.to))
+		})
+	}
+}
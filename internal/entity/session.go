@@ -2,7 +2,23 @@ package entity
 
 import "time"
 
+// Session is a sign in of the user on a device, it lives until the refresh token expires or the user logs out.
+// RefreshToken holds SHA-256 of the token, the token itself is never stored. Current marks the session
+// the request was made from.
 type Session struct {
-	RefreshToken string    `json:"refresh_token"`
+	ID           int       `json:"id"`
+	UserId       int       `json:"-"`
+	RefreshToken string    `json:"-"`
+	Client       Client    `json:"client"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastUsedAt   time.Time `json:"last_used_at"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	Current      bool      `json:"current,omitempty"`
+}
+
+// Client describes the device a session was started or refreshed from.
+type Client struct {
+	Device    string `json:"device,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	IP        string `json:"ip,omitempty"`
 }
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// Permissions on a note, each one allows everything the previous ones do. Owner can't be granted,
+// it's the user who created the note.
+const (
+	PermissionViewer = "viewer"
+	PermissionEditor = "editor"
+	PermissionOwner  = "owner"
+)
+
+var permissionRanks = map[string]int{
+	PermissionViewer: 1,
+	PermissionEditor: 2,
+	PermissionOwner:  3,
+}
+
+// PermissionAllows reports whether the permission the user has is enough for the one required.
+func PermissionAllows(has, required string) bool {
+	return permissionRanks[has] >= permissionRanks[required] && permissionRanks[has] > 0
+}
+
+// NoteShare grants the user access to a note of another user.
+type NoteShare struct {
+	NoteId     int       `json:"note_id"`
+	UserId     int       `json:"user_id"`
+	Login      string    `json:"login,omitempty"`
+	OwnerId    int       `json:"-"`
+	Permission string    `json:"permission"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SharedNote is a note shared with the user along with the permission granted.
+type SharedNote struct {
+	Note
+	Permission string `json:"permission"`
+}
@@ -0,0 +1,8 @@
+package entity
+
+type Tag struct {
+	ID         int    `json:"id,omitempty"`
+	UserId     int    `json:"user_id"`
+	Name       string `json:"name"`
+	NotesCount int    `json:"notes_count"`
+}
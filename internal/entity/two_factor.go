@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// TwoFactor is the TOTP secret of the user, it's pending until EnabledAt is set by the first code.
+// LastStep is the period of the last accepted code, codes of it and earlier periods are rejected.
+// Codes aren't checked until LockedUntil once too many wrong ones were entered in a row.
+type TwoFactor struct {
+	UserId      int
+	Secret      string
+	LastStep    int64
+	CreatedAt   time.Time
+	EnabledAt   *time.Time
+	LockedUntil *time.Time
+}
+
+// TwoFactorChallenge is issued on sign in by the password when 2FA is enabled, it's exchanged
+// with a code for tokens. Only the hash of its token is stored.
+type TwoFactorChallenge struct {
+	ID        int
+	UserId    int
+	TokenHash string
+	Device    string
+	Attempts  int
+	ExpiresAt time.Time
+}
@@ -2,10 +2,15 @@ package entity
 
 import "time"
 
+const DefaultTimeZone = "UTC"
+
+// User is a registered account, EmailVerifiedAt is nil until the email is confirmed by the link sent on sign up.
 type User struct {
-	ID           int       `json:"id,omitempty"`
-	Email        string    `json:"email,omitempty"`
-	Login        string    `json:"login,omitempty"`
-	Password     string    `json:"password,omitempty"`
-	RegisteredAt time.Time `json:"registered_at,omitempty"`
+	ID              int        `json:"id,omitempty"`
+	Email           string     `json:"email,omitempty"`
+	Login           string     `json:"login,omitempty"`
+	Password        string     `json:"password,omitempty"`
+	RegisteredAt    time.Time  `json:"registered_at,omitempty"`
+	TimeZone        string     `json:"time_zone,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
 }
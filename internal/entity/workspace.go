@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// Roles of workspace members, each one allows everything the previous ones do. Guests only read notes and lists,
+// members change them, admins manage members and invitations and the owner who created the workspace can
+// also grant admin and delete the workspace.
+const (
+	WorkspaceRoleGuest  = "guest"
+	WorkspaceRoleMember = "member"
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleOwner  = "owner"
+)
+
+var workspaceRoleRanks = map[string]int{
+	WorkspaceRoleGuest:  1,
+	WorkspaceRoleMember: 2,
+	WorkspaceRoleAdmin:  3,
+	WorkspaceRoleOwner:  4,
+}
+
+// WorkspaceRoleAllows reports whether the role the member has is enough for the one required.
+func WorkspaceRoleAllows(has, required string) bool {
+	return workspaceRoleRanks[has] >= workspaceRoleRanks[required] && workspaceRoleRanks[has] > 0
+}
+
+// Workspace is a shared space of notes and lists, Role is the role of the user requesting it.
+type Workspace struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceId int       `json:"workspace_id"`
+	UserId      int       `json:"user_id"`
+	Login       string    `json:"login,omitempty"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WorkspaceInvitation is emailed to join the workspace with the role, only the hash of its token is stored.
+type WorkspaceInvitation struct {
+	ID          int       `json:"id"`
+	WorkspaceId int       `json:"workspace_id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	TokenHash   string    `json:"-"`
+	InvitedBy   int       `json:"invited_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type workspaceKey struct{}
+
+// ContextWithWorkspace makes the workspace of the member active for the request, notes and lists
+// are read and changed in it instead of the personal space of the user.
+func ContextWithWorkspace(ctx context.Context, member WorkspaceMember) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, member)
+}
+
+// WorkspaceFromContext returns the membership in the active workspace of the request.
+func WorkspaceFromContext(ctx context.Context) (WorkspaceMember, bool) {
+	member, ok := ctx.Value(workspaceKey{}).(WorkspaceMember)
+	return member, ok
+}
@@ -0,0 +1,154 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+var attachmentColumns = []string{"id", "note_id", "user_id", "name", "content_type", "size", "blob_key", "created_at"}
+
+func scanAttachment(row rowScanner, attachment *entity.Attachment) error {
+	return row.Scan(&attachment.ID, &attachment.NoteId, &attachment.UserId, &attachment.Name, &attachment.ContentType,
+		&attachment.Size, &attachment.BlobKey, &attachment.CreatedAt)
+}
+
+func createAttachmentBuilder(attachment entity.Attachment) (string, []interface{}, error) {
+	builder := sq.Insert(attachments).
+		Columns("note_id", "user_id", "name", "content_type", "size", "blob_key").
+		Values(attachment.NoteId, attachment.UserId, attachment.Name, attachment.ContentType, attachment.Size, attachment.BlobKey).
+		Suffix("RETURNING id, created_at").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateAttachment(ctx context.Context, attachment entity.Attachment) (entity.Attachment, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createAttachmentBuilder(attachment)
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&attachment.ID, &attachment.CreatedAt)
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	return attachment, tx.Commit()
+}
+
+func getAttachmentsBuilder(where sq.Eq) (string, []interface{}, error) {
+	builder := sq.Select(attachmentColumns...).
+		From(attachments).
+		Where(where).
+		OrderBy("id ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetAttachment(ctx context.Context, id, noteId int) (entity.Attachment, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getAttachmentsBuilder(sq.Eq{"id": id, "note_id": noteId})
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	var attachment entity.Attachment
+	err = scanAttachment(tx.QueryRowContext(ctx, query, args...), &attachment)
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	return attachment, tx.Commit()
+}
+
+// GetAttachments returns attachments of the note oldest first.
+func (r *DBRepo) GetAttachments(ctx context.Context, noteId int) ([]entity.Attachment, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getAttachmentsBuilder(sq.Eq{"note_id": noteId})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Attachment
+	for rows.Next() {
+		var attachment entity.Attachment
+		if err := scanAttachment(rows, &attachment); err != nil {
+			return nil, err
+		}
+		result = append(result, attachment)
+	}
+
+	return result, tx.Commit()
+}
+
+func deleteAttachmentBuilder(id, noteId int) (string, []interface{}, error) {
+	builder := sq.Delete(attachments).
+		Where(sq.Eq{"id": id, "note_id": noteId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) DeleteAttachment(ctx context.Context, id, noteId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteAttachmentBuilder(id, noteId)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,108 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now()
+	attachment := entity.Attachment{NoteId: 3, UserId: 1, Name: "plan.pdf", ContentType: "application/pdf", Size: 1024, BlobKey: "notes/3/abc"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO note_attachments (note_id,user_id,name,content_type,size,blob_key) "+
+		"VALUES ($1,$2,$3,$4,$5,$6) RETURNING id, created_at")).
+		WithArgs(3, 1, "plan.pdf", "application/pdf", int64(1024), "notes/3/abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+	mock.ExpectCommit()
+
+	got, err := r.CreateAttachment(context.Background(), attachment)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, got.ID)
+	assert.Equal(t, now, got.CreatedAt)
+	assert.Equal(t, "notes/3/abc", got.BlobKey)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAttachments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now()
+	columns := []string{"id", "note_id", "user_id", "name", "content_type", "size", "blob_key", "created_at"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, note_id, user_id, name, content_type, size, blob_key, created_at " +
+		"FROM note_attachments WHERE note_id = $1 ORDER BY id ASC")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(7, 3, 1, "plan.pdf", "application/pdf", 1024, "notes/3/abc", now).
+			AddRow(8, 3, 2, "screen.png", "image/png", 2048, "notes/3/def", now))
+	mock.ExpectCommit()
+
+	got, err := r.GetAttachments(context.Background(), 3)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "notes/3/abc", got[0].BlobKey)
+		assert.Equal(t, int64(2048), got[1].Size)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, note_id, user_id, name, content_type, size, blob_key, created_at "+
+		"FROM note_attachments WHERE id = $1 AND note_id = $2 ORDER BY id ASC")).
+		WithArgs(9, 3).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	_, err = r.GetAttachment(context.Background(), 9, 3)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM note_attachments WHERE id = $1 AND note_id = $2")).
+		WithArgs(7, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.DeleteAttachment(context.Background(), 7, 3))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM note_attachments WHERE id = $1 AND note_id = $2")).
+		WithArgs(7, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.DeleteAttachment(context.Background(), 7, 3), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
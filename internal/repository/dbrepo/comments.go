@@ -0,0 +1,262 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// mentions are stored space-separated like scopes of personal access tokens.
+var commentColumns = []string{"c.id", "c.note_id", "c.user_id", "u.login", "c.body", "c.mentions", "c.created_at", "c.edited_at"}
+
+func scanComment(row rowScanner, comment *entity.Comment) error {
+	var mentions string
+	err := row.Scan(&comment.ID, &comment.NoteId, &comment.UserId, &comment.Login, &comment.Body, &mentions,
+		&comment.CreatedAt, &comment.EditedAt)
+	if err != nil {
+		return err
+	}
+
+	comment.Mentions = strings.Fields(mentions)
+	return nil
+}
+
+func createCommentBuilder(comment entity.Comment) (string, []interface{}, error) {
+	builder := sq.Insert(comments).
+		Columns("note_id", "user_id", "body", "mentions").
+		Values(comment.NoteId, comment.UserId, comment.Body, strings.Join(comment.Mentions, " ")).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateComment(ctx context.Context, comment entity.Comment) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createCommentBuilder(comment)
+	if err != nil {
+		return 0, err
+	}
+
+	var commentId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&commentId)
+	if err != nil {
+		return 0, err
+	}
+
+	return commentId, tx.Commit()
+}
+
+func getCommentsBuilder(where sq.Eq, afterId, limit int) (string, []interface{}, error) {
+	builder := sq.Select(commentColumns...).
+		From(comments + " c").
+		Join(users + " u ON u.id = c.user_id").
+		Where(where).
+		OrderBy("c.id ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	if afterId != 0 {
+		builder = builder.Where(sq.Gt{"c.id": afterId})
+	}
+
+	if limit != 0 {
+		builder = builder.Limit(uint64(limit))
+	}
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetComment(ctx context.Context, id, noteId int) (entity.Comment, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Comment{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getCommentsBuilder(sq.Eq{"c.id": id, "c.note_id": noteId}, 0, 0)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	var comment entity.Comment
+	err = scanComment(tx.QueryRowContext(ctx, query, args...), &comment)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	return comment, tx.Commit()
+}
+
+// GetComments returns up to limit comments of the note oldest first, starting right after the comment afterId.
+func (r *DBRepo) GetComments(ctx context.Context, noteId, afterId, limit int) ([]entity.Comment, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getCommentsBuilder(sq.Eq{"c.note_id": noteId}, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Comment
+	for rows.Next() {
+		var comment entity.Comment
+		if err := scanComment(rows, &comment); err != nil {
+			return nil, err
+		}
+		result = append(result, comment)
+	}
+
+	return result, tx.Commit()
+}
+
+func updateCommentBuilder(comment entity.Comment) (string, []interface{}, error) {
+	builder := sq.Update(comments).
+		Set("body", comment.Body).
+		Set("mentions", strings.Join(comment.Mentions, " ")).
+		Set("edited_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": comment.ID, "note_id": comment.NoteId, "user_id": comment.UserId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// UpdateComment changes the body and mentions of the comment written by the user recording the time of the edit.
+func (r *DBRepo) UpdateComment(ctx context.Context, comment entity.Comment) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := updateCommentBuilder(comment)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func deleteCommentBuilder(id, noteId int) (string, []interface{}, error) {
+	builder := sq.Delete(comments).
+		Where(sq.Eq{"id": id, "note_id": noteId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) DeleteComment(ctx context.Context, id, noteId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteCommentBuilder(id, noteId)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func countCommentsBuilder(noteIds []int) (string, []interface{}, error) {
+	builder := sq.Select("note_id", "COUNT(*)").
+		From(comments).
+		Where(sq.Eq{"note_id": noteIds}).
+		GroupBy("note_id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// CountNotesComments returns numbers of comments keyed by note id, notes without comments are left out.
+func (r *DBRepo) CountNotesComments(ctx context.Context, noteIds []int) (map[int]int, error) {
+	result := make(map[int]int, len(noteIds))
+	if len(noteIds) == 0 {
+		return result, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := countCommentsBuilder(noteIds)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var noteId, count int
+		if err := rows.Scan(&noteId, &count); err != nil {
+			return nil, err
+		}
+		result[noteId] = count
+	}
+
+	return result, tx.Commit()
+}
@@ -0,0 +1,115 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	comment := entity.Comment{NoteId: 3, UserId: 1, Body: "@bob @alice take a look", Mentions: []string{"bob", "alice"}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO note_comments (note_id,user_id,body,mentions) VALUES ($1,$2,$3,$4) RETURNING id")).
+		WithArgs(comment.NoteId, comment.UserId, comment.Body, "bob alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+
+	id, err := r.CreateComment(context.Background(), comment)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	expectedQuery := "SELECT c.id, c.note_id, c.user_id, u.login, c.body, c.mentions, c.created_at, c.edited_at " +
+		"FROM note_comments c JOIN users u ON u.id = c.user_id WHERE c.note_id = $1 AND c.id > $2 ORDER BY c.id ASC LIMIT 3"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(3, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "note_id", "user_id", "login", "body", "mentions", "created_at", "edited_at"}).
+			AddRow(6, 3, 1, "owner", "@bob take a look", "bob", now, nil).
+			AddRow(7, 3, 2, "bob", "done", "", now, now))
+	mock.ExpectCommit()
+
+	got, err := r.GetComments(context.Background(), 3, 5, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, []string{"bob"}, got[0].Mentions)
+		assert.Nil(t, got[0].EditedAt)
+		assert.Empty(t, got[1].Mentions)
+		assert.Equal(t, "bob", got[1].Login)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	comment := entity.Comment{ID: 6, NoteId: 3, UserId: 2, Body: "edited"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE note_comments SET body = $1, mentions = $2, edited_at = NOW() WHERE id = $3 AND note_id = $4 AND user_id = $5")).
+		WithArgs(comment.Body, "", comment.ID, comment.NoteId, comment.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.UpdateComment(context.Background(), comment), sql.ErrNoRows, "comments of others must not be edited")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountNotesComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT note_id, COUNT(*) FROM note_comments WHERE note_id IN ($1,$2) GROUP BY note_id")).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"note_id", "count"}).AddRow(2, 4))
+	mock.ExpectCommit()
+
+	got, err := r.CountNotesComments(context.Background(), []int{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{2: 4}, got)
+
+	got, err = r.CountNotesComments(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,150 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func createEmailVerificationBuilder(verification entity.EmailVerification) (string, []interface{}, error) {
+	builder := sq.Insert(verifications).
+		Columns("user_id", "token_hash", "expires_at").
+		Values(verification.UserId, verification.TokenHash, verification.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateEmailVerification(ctx context.Context, verification entity.EmailVerification) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createEmailVerificationBuilder(verification)
+	if err != nil {
+		return 0, err
+	}
+
+	var verificationId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&verificationId)
+	if err != nil {
+		return 0, err
+	}
+
+	return verificationId, tx.Commit()
+}
+
+func getLatestEmailVerificationBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Select("id", "user_id", "token_hash", "created_at", "expires_at").
+		From(verifications).
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetLatestEmailVerification returns the verification sent to the user last.
+func (r *DBRepo) GetLatestEmailVerification(ctx context.Context, userId int) (entity.EmailVerification, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.EmailVerification{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getLatestEmailVerificationBuilder(userId)
+	if err != nil {
+		return entity.EmailVerification{}, err
+	}
+
+	var v entity.EmailVerification
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&v.ID, &v.UserId, &v.TokenHash, &v.CreatedAt, &v.ExpiresAt)
+	if err != nil {
+		return entity.EmailVerification{}, err
+	}
+
+	return v, tx.Commit()
+}
+
+func useEmailVerificationBuilder(tokenHash string) (string, []interface{}, error) {
+	builder := sq.Delete(verifications).
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Where(sq.Expr("expires_at > NOW()")).
+		Suffix("RETURNING user_id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func verifyUserEmailBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Update(users).
+		Set("email_verified_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": userId, "email_verified_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func deleteEmailVerificationsBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Delete(verifications).
+		Where(sq.Eq{"user_id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// VerifyEmail uses the verification token with the hash to mark the email of its user as verified,
+// other verifications of the user are deleted. Returns the id of the user or sql.ErrNoRows
+// when the token is unknown, expired or used.
+func (r *DBRepo) VerifyEmail(ctx context.Context, tokenHash string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := useEmailVerificationBuilder(tokenHash)
+	if err != nil {
+		return 0, err
+	}
+
+	var userId int
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(&userId); err != nil {
+		return 0, err
+	}
+
+	query, args, err = verifyUserEmailBuilder(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	query, args, err = deleteEmailVerificationsBuilder(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	return userId, tx.Commit()
+}
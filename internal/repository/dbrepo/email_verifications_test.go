@@ -0,0 +1,135 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateEmailVerification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	verification := entity.EmailVerification{UserId: 1, TokenHash: "hash", ExpiresAt: time.Now().Round(time.Second)}
+
+	mock.ExpectBegin()
+	expectedQuery := "INSERT INTO email_verifications (user_id,token_hash,expires_at) VALUES ($1,$2,$3) RETURNING id"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(verification.UserId, verification.TokenHash, verification.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	id, err := r.CreateEmailVerification(context.Background(), verification)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLatestEmailVerification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now().Round(time.Second)
+	verification := entity.EmailVerification{ID: 2, UserId: 1, TokenHash: "hash", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	mock.ExpectBegin()
+	expectedQuery := "SELECT id, user_id, token_hash, created_at, expires_at FROM email_verifications WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(verification.UserId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token_hash", "created_at", "expires_at"}).
+			AddRow(verification.ID, verification.UserId, verification.TokenHash, verification.CreatedAt, verification.ExpiresAt))
+	mock.ExpectCommit()
+
+	got, err := r.GetLatestEmailVerification(context.Background(), verification.UserId)
+	assert.NoError(t, err)
+	assert.Equal(t, verification, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVerifyEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type mockBehavior func(tokenHash string)
+
+	useQuery := "DELETE FROM email_verifications WHERE token_hash = $1 AND expires_at > NOW() RETURNING user_id"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantUserId   int
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(tokenHash string) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(useQuery)).
+					WithArgs(tokenHash).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET email_verified_at = NOW() WHERE email_verified_at IS NULL AND id = $1")).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM email_verifications WHERE user_id = $1")).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantUserId: 1,
+		},
+		{
+			name: "InvalidToken",
+			mockBehavior: func(tokenHash string) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(useQuery)).
+					WithArgs(tokenHash).
+					WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior("hash")
+
+			userId, err := r.VerifyEmail(context.Background(), "hash")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantUserId, userId)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
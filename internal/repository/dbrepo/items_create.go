@@ -0,0 +1,46 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// createItemBuilder appends the item to the end of the note's checklist.
+func createItemBuilder(item entity.Item) (string, []interface{}, error) {
+	position := sq.Expr("(SELECT COALESCE(MAX(position), 0) + 1 FROM "+items+" WHERE note_id = ?)", item.NoteId)
+
+	builder := sq.Insert(items).
+		Columns("note_id", "title", "checked", "position").
+		Values(item.NoteId, item.Title, item.Checked, position).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateItem(ctx context.Context, item entity.Item) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createItemBuilder(item)
+	if err != nil {
+		return 0, err
+	}
+
+	var itemId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&itemId)
+	if err != nil {
+		return 0, err
+	}
+
+	return itemId, tx.Commit()
+}
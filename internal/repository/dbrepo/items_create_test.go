@@ -0,0 +1,83 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		item entity.Item
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "INSERT INTO note_items (note_id,title,checked,position) VALUES ($1,$2,$3,(SELECT COALESCE(MAX(position), 0) + 1 FROM note_items WHERE note_id = $4)) RETURNING id"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		id           int
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.item.NoteId, args.item.Title, args.item.Checked, args.item.NoteId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			args: args{item: entity.Item{NoteId: 1, Title: "buy milk"}},
+			id:   1,
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.item.NoteId, args.item.Title, args.item.Checked, args.item.NoteId).
+					WillReturnError(errors.New("new error"))
+
+				mock.ExpectRollback()
+			},
+			args:    args{item: entity.Item{NoteId: 100, Title: "buy milk"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			got, err := r.CreateItem(context.Background(), tt.args.item)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
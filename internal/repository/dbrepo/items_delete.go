@@ -0,0 +1,39 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func getDeleteItemQuery(id, noteId int) (string, []interface{}, error) {
+	builder := sq.Delete(items).
+		Where(sq.Eq{"id": id, "note_id": noteId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) DeleteItem(ctx context.Context, id, noteId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getDeleteItemQuery(id, noteId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
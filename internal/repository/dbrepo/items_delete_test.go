@@ -0,0 +1,87 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		noteId int
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				id:     1,
+				noteId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "DELETE FROM note_items WHERE id = $1 AND note_id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.noteId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed",
+			args: args{
+				id:     100,
+				noteId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "DELETE FROM note_items WHERE id = $1 AND note_id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.noteId).
+					WillReturnError(errors.New("new error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.DeleteItem(context.Background(), tt.args.id, tt.args.noteId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
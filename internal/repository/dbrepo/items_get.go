@@ -0,0 +1,100 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+var itemColumns = []string{"id", "note_id", "title", "checked", "position"}
+
+func scanItem(row interface{ Scan(dest ...any) error }) (entity.Item, error) {
+	var item entity.Item
+	err := row.Scan(&item.ID, &item.NoteId, &item.Title, &item.Checked, &item.Position)
+	return item, err
+}
+
+/*-----------------------------
+					GET ITEM
+ ----------------------------- */
+
+func getItemBuilder(id, noteId int) (string, []interface{}, error) {
+	builder := sq.Select(itemColumns...).
+		From(items).
+		Where(sq.Eq{"id": id, "note_id": noteId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetItemById(ctx context.Context, id, noteId int) (entity.Item, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Item{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getItemBuilder(id, noteId)
+	if err != nil {
+		return entity.Item{}, err
+	}
+
+	item, err := scanItem(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return entity.Item{}, err
+	}
+
+	return item, tx.Commit()
+}
+
+/*-----------------------------
+					GET NOTE ITEMS
+ ----------------------------- */
+
+func getItemsBuilder(noteId int) (string, []interface{}, error) {
+	builder := sq.Select(itemColumns...).
+		From(items).
+		Where(sq.Eq{"note_id": noteId}).
+		OrderBy("position ASC", "id ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetItems(ctx context.Context, noteId int) ([]entity.Item, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getItemsBuilder(noteId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+
+	return result, tx.Commit()
+}
@@ -0,0 +1,117 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetItemById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		noteId int
+	}
+
+	type mockBehavior func(args args)
+
+	item := entity.Item{ID: 1, NoteId: 1, Title: "buy milk", Checked: true, Position: 1}
+	expectedQuery := "SELECT id, note_id, title, checked, position FROM note_items WHERE id = $1 AND note_id = $2"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantItem     entity.Item
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "note_id", "title", "checked", "position"}).
+					AddRow(item.ID, item.NoteId, item.Title, item.Checked, item.Position)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.noteId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			args:     args{id: item.ID, noteId: item.NoteId},
+			wantItem: item,
+		},
+		{
+			name: "Failed_NotFound",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.id, args.noteId).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			args:     args{id: 100, noteId: item.NoteId},
+			wantItem: entity.Item{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			got, err := r.GetItemById(context.Background(), tt.args.id, tt.args.noteId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantItem, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	noteId := 1
+	items := []entity.Item{
+		{ID: 2, NoteId: noteId, Title: "buy bread", Checked: false, Position: 1},
+		{ID: 1, NoteId: noteId, Title: "buy milk", Checked: true, Position: 2},
+	}
+
+	mock.ExpectBegin()
+
+	rows := sqlmock.NewRows([]string{"id", "note_id", "title", "checked", "position"}).
+		AddRow(items[0].ID, items[0].NoteId, items[0].Title, items[0].Checked, items[0].Position).
+		AddRow(items[1].ID, items[1].NoteId, items[1].Title, items[1].Checked, items[1].Position)
+
+	expectedQuery := "SELECT id, note_id, title, checked, position FROM note_items WHERE note_id = $1 ORDER BY position ASC, id ASC"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(noteId).WillReturnRows(rows)
+
+	mock.ExpectCommit()
+
+	got, err := r.GetItems(context.Background(), noteId)
+	assert.NoError(t, err)
+	assert.Equal(t, items, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
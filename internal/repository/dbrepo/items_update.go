@@ -0,0 +1,82 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func updateItemBuilder(id, noteId int, upd entity.ItemUpdate) (string, []interface{}, error) {
+	builder := sq.Update(items).
+		Where(sq.Eq{"id": id, "note_id": noteId}).
+		PlaceholderFormat(sq.Dollar)
+
+	if upd.Title != "" {
+		builder = builder.Set("title", upd.Title)
+	}
+
+	if upd.Checked != nil {
+		builder = builder.Set("checked", *upd.Checked)
+	}
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) UpdateItem(ctx context.Context, id, noteId int, upd entity.ItemUpdate) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := updateItemBuilder(id, noteId, upd)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setItemPositionBuilder(id, noteId, position int) (string, []interface{}, error) {
+	builder := sq.Update(items).
+		Set("position", position).
+		Where(sq.Eq{"id": id, "note_id": noteId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// ReorderItems sets positions of the note's items following the order of ids.
+func (r *DBRepo) ReorderItems(ctx context.Context, noteId int, ids []int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for i, id := range ids {
+		query, args, err := setItemPositionBuilder(id, noteId, i+1)
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
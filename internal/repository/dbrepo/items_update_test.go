@@ -0,0 +1,121 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		noteId int
+		upd    entity.ItemUpdate
+	}
+
+	type mockBehavior func(args args)
+
+	checked := true
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				id:     1,
+				noteId: 1,
+				upd:    entity.ItemUpdate{Title: "buy milk", Checked: &checked},
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE note_items SET title = $1, checked = $2 WHERE id = $3 AND note_id = $4"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.upd.Title, *args.upd.Checked, args.id, args.noteId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed",
+			args: args{
+				id:     100,
+				noteId: 1,
+				upd:    entity.ItemUpdate{Checked: &checked},
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE note_items SET checked = $1 WHERE id = $2 AND note_id = $3"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(*args.upd.Checked, args.id, args.noteId).
+					WillReturnError(errors.New("new error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.UpdateItem(context.Background(), tt.args.id, tt.args.noteId, tt.args.upd)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReorderItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	noteId := 1
+	ids := []int{3, 1, 2}
+
+	mock.ExpectBegin()
+
+	expectedQuery := "UPDATE note_items SET position = $1 WHERE id = $2 AND note_id = $3"
+	for i, id := range ids {
+		mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+			WithArgs(i+1, id, noteId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	mock.ExpectCommit()
+
+	err = r.ReorderItems(context.Background(), noteId, ids)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,43 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func createListBuilder(list entity.List, workspaceId any) (string, []interface{}, error) {
+	builder := sq.Insert(lists).
+		Columns("user_id", "name", "workspace_id").
+		Values(list.UserId, list.Name, workspaceId).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateList(ctx context.Context, list entity.List) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createListBuilder(list, workspaceValue(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	var listId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&listId)
+	if err != nil {
+		return 0, err
+	}
+
+	return listId, tx.Commit()
+}
@@ -2,18 +2,17 @@ package dbrepo
 
 import (
 	"context"
-	"database/sql/driver"
+	"errors"
 	"log"
 	"regexp"
 	"testing"
-	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/pintoter/todo-list/internal/entity"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestSetUserSession(t *testing.T) {
+func TestCreateList(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		log.Fatal(err)
@@ -23,17 +22,18 @@ func TestSetUserSession(t *testing.T) {
 	r := New(db)
 
 	type args struct {
-		id           int
-		refreshToken string
-		expiresAt    time.Time
+		list entity.List
 	}
 
 	type mockBehavior func(args args)
 
+	expectedQuery := "INSERT INTO lists (user_id,name,workspace_id) VALUES ($1,$2,$3) RETURNING id"
+
 	tests := []struct {
 		name         string
 		mockBehavior mockBehavior
 		args         args
+		id           int
 		wantErr      bool
 	}{
 		{
@@ -41,30 +41,27 @@ func TestSetUserSession(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectExec := "UPDATE users SET refresh_token = $1, expires_at = $2 WHERE id = $3"
-				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
-					WithArgs(args.refreshToken, args.expiresAt, args.id).
-					WillReturnResult(driver.RowsAffected(0))
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.list.UserId, args.list.Name, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				mock.ExpectCommit()
 			},
-			args: args{
-				id:           0,
-				refreshToken: "refresh_token_test",
-				expiresAt:    time.Time{},
-			},
+			args: args{list: entity.List{UserId: 1, Name: "work"}},
+			id:   1,
 		},
 		{
 			name: "Failed",
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectExec := "UPDATE users SET refresh_token = $1, expires_at = $2 WHERE id = $3"
-				mock.ExpectExec(regexp.QuoteMeta(expectExec)).WithArgs(args.refreshToken, args.expiresAt, args.id)
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.list.UserId, args.list.Name, nil).
+					WillReturnError(errors.New("new error"))
 
 				mock.ExpectRollback()
 			},
-			args:    args{},
+			args:    args{list: entity.List{UserId: 1, Name: "work"}},
 			wantErr: true,
 		},
 	}
@@ -73,17 +70,14 @@ func TestSetUserSession(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
 
-			err := r.SetSession(context.Background(), tt.args.id, entity.Session{
-				RefreshToken: tt.args.refreshToken,
-				ExpiresAt:    tt.args.expiresAt,
-			})
+			got, err := r.CreateList(context.Background(), tt.args.list)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.id, got)
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
-
 }
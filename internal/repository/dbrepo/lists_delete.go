@@ -0,0 +1,63 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func getDeleteListQuery(id int, owner sq.Eq) (string, []interface{}, error) {
+	builder := sq.Delete(lists).
+		Where(sq.Eq{"id": id}).
+		Where(owner).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// listNotesBuilder moves notes of the list to the inbox, with cascade they are moved to the trash as well.
+func listNotesBuilder(id int, owner sq.Eq, cascade bool) (string, []interface{}, error) {
+	builder := sq.Update(notes).
+		Set("list_id", nil).
+		Where(sq.Eq{"list_id": id}).
+		Where(owner).
+		PlaceholderFormat(sq.Dollar)
+
+	if cascade {
+		builder = builder.Set("deleted_at", sq.Expr("COALESCE(deleted_at, NOW())"))
+	}
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) DeleteList(ctx context.Context, id, userId int, cascade bool) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := listNotesBuilder(id, ownedBy(ctx, "", userId), cascade)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = getDeleteListQuery(id, ownedBy(ctx, "", userId))
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
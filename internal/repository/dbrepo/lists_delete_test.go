@@ -0,0 +1,117 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id      int
+		userId  int
+		cascade bool
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "SuccessMoveToInbox",
+			args: args{
+				id:     1,
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE notes SET list_id = $1 WHERE list_id = $2 AND user_id = $3 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				expectedQuery = "DELETE FROM lists WHERE id = $1 AND user_id = $2 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "SuccessCascade",
+			args: args{
+				id:      1,
+				userId:  1,
+				cascade: true,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE notes SET list_id = $1, deleted_at = COALESCE(deleted_at, NOW()) WHERE list_id = $2 AND user_id = $3 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				expectedQuery = "DELETE FROM lists WHERE id = $1 AND user_id = $2 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed",
+			args: args{
+				id:     100,
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE notes SET list_id = $1 WHERE list_id = $2 AND user_id = $3 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, args.id, args.userId).
+					WillReturnError(errors.New("new error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.DeleteList(context.Background(), tt.args.id, tt.args.userId, tt.args.cascade)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
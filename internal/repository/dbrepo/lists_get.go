@@ -0,0 +1,118 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+/*-----------------------------
+					GET LIST
+ ----------------------------- */
+
+func getListBuilder(data any, owner sq.Eq) (string, []interface{}, error) {
+	builder := sq.Select("l.id", "l.user_id", "l.name", "COUNT(n.id)").
+		From(lists + " l").
+		LeftJoin(notes + " n ON n.list_id = l.id AND n.deleted_at IS NULL").
+		Where(owner).
+		GroupBy("l.id").
+		PlaceholderFormat(sq.Dollar)
+
+	switch data.(type) {
+	case int:
+		builder = builder.Where(sq.Eq{"l.id": data})
+	case string:
+		builder = builder.Where(sq.Eq{"l.name": data})
+	default:
+		builder = builder.OrderBy("l.name ASC")
+	}
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetListById(ctx context.Context, id, userId int) (entity.List, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.List{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getListBuilder(id, ownedBy(ctx, "l", userId))
+	if err != nil {
+		return entity.List{}, err
+	}
+
+	var list entity.List
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&list.ID, &list.UserId, &list.Name, &list.NotesCount)
+	if err != nil {
+		return entity.List{}, err
+	}
+
+	return list, tx.Commit()
+}
+
+func (r *DBRepo) GetListByName(ctx context.Context, name string, userId int) (entity.List, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.List{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getListBuilder(name, ownedBy(ctx, "l", userId))
+	if err != nil {
+		return entity.List{}, err
+	}
+
+	var list entity.List
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&list.ID, &list.UserId, &list.Name, &list.NotesCount)
+	if err != nil {
+		return entity.List{}, err
+	}
+
+	return list, tx.Commit()
+}
+
+/*-----------------------------
+					GET ALL LISTS
+ ----------------------------- */
+
+func (r *DBRepo) GetLists(ctx context.Context, userId int) ([]entity.List, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getListBuilder(nil, ownedBy(ctx, "l", userId))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.List
+	for rows.Next() {
+		var list entity.List
+		if err := rows.Scan(&list.ID, &list.UserId, &list.Name, &list.NotesCount); err != nil {
+			return nil, err
+		}
+		result = append(result, list)
+	}
+
+	return result, tx.Commit()
+}
@@ -0,0 +1,118 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetListById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		userId int
+	}
+
+	type mockBehavior func(args args)
+
+	list := entity.List{ID: 1, UserId: 1, Name: "work", NotesCount: 3}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantList     entity.List
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "count"}).
+					AddRow(list.ID, list.UserId, list.Name, list.NotesCount)
+
+				expectedQuery := "SELECT l.id, l.user_id, l.name, COUNT(n.id) FROM lists l LEFT JOIN notes n ON n.list_id = l.id AND n.deleted_at IS NULL WHERE l.user_id = $1 AND l.workspace_id IS NULL AND l.id = $2 GROUP BY l.id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			args:     args{id: list.ID, userId: list.UserId},
+			wantList: list,
+		},
+		{
+			name: "Failed_NotFound",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT l.id, l.user_id, l.name, COUNT(n.id) FROM lists l LEFT JOIN notes n ON n.list_id = l.id AND n.deleted_at IS NULL WHERE l.user_id = $1 AND l.workspace_id IS NULL AND l.id = $2 GROUP BY l.id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnError(errors.New("failed test"))
+
+				mock.ExpectRollback()
+			},
+			args:     args{id: 100, userId: list.UserId},
+			wantList: entity.List{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			got, err := r.GetListById(context.Background(), tt.args.id, tt.args.userId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantList, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetLists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	userId := 1
+	lists := []entity.List{
+		{ID: 2, UserId: userId, Name: "home", NotesCount: 0},
+		{ID: 1, UserId: userId, Name: "work", NotesCount: 3},
+	}
+
+	mock.ExpectBegin()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "name", "count"}).
+		AddRow(lists[0].ID, lists[0].UserId, lists[0].Name, lists[0].NotesCount).
+		AddRow(lists[1].ID, lists[1].UserId, lists[1].Name, lists[1].NotesCount)
+
+	expectedQuery := "SELECT l.id, l.user_id, l.name, COUNT(n.id) FROM lists l LEFT JOIN notes n ON n.list_id = l.id AND n.deleted_at IS NULL WHERE l.user_id = $1 AND l.workspace_id IS NULL GROUP BY l.id ORDER BY l.name ASC"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(userId).WillReturnRows(rows)
+
+	mock.ExpectCommit()
+
+	got, err := r.GetLists(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.Equal(t, lists, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
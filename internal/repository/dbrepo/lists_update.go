@@ -0,0 +1,75 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func updateListBuilder(id int, owner sq.Eq, name string) (string, []interface{}, error) {
+	builder := sq.Update(lists).
+		Set("name", name).
+		Where(sq.Eq{"id": id}).
+		Where(owner).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) UpdateList(ctx context.Context, id, userId int, name string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := updateListBuilder(id, ownedBy(ctx, "", userId), name)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func moveNotesBuilder(noteIds []int, listId int, owner sq.Eq) (string, []interface{}, error) {
+	builder := sq.Update(notes).
+		Set("list_id", listIdValue(listId)).
+		Where(sq.Eq{"id": noteIds}).
+		Where(owner).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// MoveNotes moves notes of the user into the list, zero listId moves them to the inbox.
+func (r *DBRepo) MoveNotes(ctx context.Context, noteIds []int, listId, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := moveNotesBuilder(noteIds, listId, ownedBy(ctx, "", userId))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,168 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateList(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		userId int
+		name   string
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				id:     1,
+				userId: 1,
+				name:   "work",
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE lists SET name = $1 WHERE id = $2 AND user_id = $3 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.name, args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed",
+			args: args{
+				id:     100,
+				userId: 1,
+				name:   "work",
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE lists SET name = $1 WHERE id = $2 AND user_id = $3 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.name, args.id, args.userId).
+					WillReturnError(errors.New("new error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.UpdateList(context.Background(), tt.args.id, tt.args.userId, tt.args.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMoveNotes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		noteIds []int
+		listId  int
+		userId  int
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				noteIds: []int{1, 2},
+				listId:  3,
+				userId:  1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE notes SET list_id = $1 WHERE id IN ($2,$3) AND user_id = $4 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.listId, 1, 2, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "SuccessToInbox",
+			args: args{
+				noteIds: []int{1},
+				listId:  0,
+				userId:  1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE notes SET list_id = $1 WHERE id IN ($2) AND user_id = $3 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, 1, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.MoveNotes(context.Background(), tt.args.noteIds, tt.args.listId, tt.args.userId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -3,15 +3,44 @@ package dbrepo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pintoter/todo-list/internal/entity"
 )
 
-func createNoteBuilder(note entity.Note) (string, []interface{}, error) {
+// recurrenceValue converts recurrence rule into JSONB value, nil rule is stored as NULL.
+func recurrenceValue(recurrence *entity.Recurrence) (any, error) {
+	if recurrence == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// listIdValue converts list id into column value, zero stands for the inbox and is stored as NULL.
+func listIdValue(listId int) any {
+	if listId == 0 {
+		return nil
+	}
+
+	return listId
+}
+
+func createNoteBuilder(note entity.Note, workspaceId any) (string, []interface{}, error) {
+	recurrence, err := recurrenceValue(note.Recurrence)
+	if err != nil {
+		return "", nil, err
+	}
+
 	builder := sq.Insert(notes).
-		Columns("user_id", "title", "description", "date", "status").
-		Values(note.UserId, note.Title, note.Description, note.Date, note.Status).
+		Columns("user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "workspace_id").
+		Values(note.UserId, note.Title, note.Description, note.Date, note.Status, note.Priority, note.DueAt, recurrence, note.ListId, workspaceId).
 		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar)
 
@@ -28,7 +57,7 @@ func (r *DBRepo) CreateNote(ctx context.Context, note entity.Note) (int, error)
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query, args, err := createNoteBuilder(note)
+	query, args, err := createNoteBuilder(note, workspaceValue(ctx))
 	if err != nil {
 		return 0, err
 	}
@@ -39,5 +68,11 @@ func (r *DBRepo) CreateNote(ctx context.Context, note entity.Note) (int, error)
 		return 0, err
 	}
 
+	if len(note.Tags) > 0 {
+		if err = setNoteTags(ctx, tx, noteId, note.UserId, note.Tags); err != nil {
+			return 0, err
+		}
+	}
+
 	return noteId, tx.Commit()
 }
@@ -40,9 +40,9 @@ func TestNote_Create(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedExec := "INSERT INTO notes (user_id,title,description,date,status) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+				expectedExec := "INSERT INTO notes (user_id,title,description,date,status,priority,due_at,recurrence,list_id,workspace_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status, args.note.Priority, args.note.DueAt, nil, args.note.ListId, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				mock.ExpectCommit()
 			},
@@ -62,9 +62,9 @@ func TestNote_Create(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedExec := "INSERT INTO notes (user_id,title,description,date,status) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+				expectedExec := "INSERT INTO notes (user_id,title,description,date,status,priority,due_at,recurrence,list_id,workspace_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status, args.note.Priority, args.note.DueAt, nil, args.note.ListId, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				mock.ExpectCommit()
 			},
@@ -77,14 +77,47 @@ func TestNote_Create(t *testing.T) {
 			},
 			id: 1,
 		},
+		{
+			name: "Success_WithTags",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedExec := "INSERT INTO notes (user_id,title,description,date,status,priority,due_at,recurrence,list_id,workspace_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
+					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status, args.note.Priority, args.note.DueAt, nil, args.note.ListId, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM note_tags WHERE note_id = $1")).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tags (user_id,name) VALUES ($1,$2),($3,$4) ON CONFLICT (user_id, name) DO NOTHING")).
+					WithArgs(args.note.UserId, "work", args.note.UserId, "urgent").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO note_tags (note_id,tag_id) SELECT $1, id FROM tags WHERE name IN ($2,$3) AND user_id = $4")).
+					WithArgs(1, "work", "urgent", args.note.UserId).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+			args: args{
+				note: entity.Note{
+					UserId: 1,
+					Title:  "Test title",
+					Status: entity.StatusNotDone,
+					Tags:   []string{"work", "urgent"},
+				},
+			},
+			id: 1,
+		},
 		{
 			name: "Failed_EmptyTitle",
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedExec := "INSERT INTO notes (user_id,title,description,date,status) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+				expectedExec := "INSERT INTO notes (user_id,title,description,date,status,priority,due_at,recurrence,list_id,workspace_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status).WillReturnError(errors.New("empty title"))
+					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status, args.note.Priority, args.note.DueAt, nil, args.note.ListId, nil).WillReturnError(errors.New("empty title"))
 
 				mock.ExpectRollback()
 			},
@@ -104,9 +137,9 @@ func TestNote_Create(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedExec := "INSERT INTO notes (user_id,title,description,date,status) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+				expectedExec := "INSERT INTO notes (user_id,title,description,date,status,priority,due_at,recurrence,list_id,workspace_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status).WillReturnError(errors.New("empty id"))
+					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status, args.note.Priority, args.note.DueAt, nil, args.note.ListId, nil).WillReturnError(errors.New("empty id"))
 
 				mock.ExpectRollback()
 			},
@@ -125,9 +158,9 @@ func TestNote_Create(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedExec := "INSERT INTO notes (user_id,title,description,date,status) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+				expectedExec := "INSERT INTO notes (user_id,title,description,date,status,priority,due_at,recurrence,list_id,workspace_id) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING id"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedExec)).
-					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status).WillReturnError(errors.New("invalid status"))
+					WithArgs(args.note.UserId, args.note.Title, args.note.Description, args.note.Date, args.note.Status, args.note.Priority, args.note.DueAt, nil, args.note.ListId, nil).WillReturnError(errors.New("invalid status"))
 
 				mock.ExpectRollback()
 			},
@@ -7,9 +7,15 @@ import (
 	sq "github.com/Masterminds/squirrel"
 )
 
-func getDeleteByIdQuery(id, userId int) (string, []interface{}, error) {
-	builder := sq.Delete(notes).
-		Where(sq.Eq{"id": id, "user_id": userId}).
+// Notes are deleted softly: they are moved to the trash by setting deleted_at
+// and removed permanently by purge queries in notes_trash.go.
+
+func getDeleteByIdQuery(id int, owner sq.Eq) (string, []interface{}, error) {
+	builder := sq.Update(notes).
+		Set("deleted_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Where(owner).
+		Where(sq.Eq{"deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
 	return builder.ToSql()
@@ -25,7 +31,7 @@ func (r *DBRepo) DeleteNoteById(ctx context.Context, id, userId int) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query, args, err := getDeleteByIdQuery(id, userId)
+	query, args, err := getDeleteByIdQuery(id, ownedBy(ctx, "", userId))
 	if err != nil {
 		return err
 	}
@@ -38,9 +44,11 @@ func (r *DBRepo) DeleteNoteById(ctx context.Context, id, userId int) error {
 	return tx.Commit()
 }
 
-func getDeleteNotesQuery(userId int) (string, []interface{}, error) {
-	builder := sq.Delete(notes).
-		Where(sq.Eq{"user_id": userId}).
+func getDeleteNotesQuery(owner sq.Eq) (string, []interface{}, error) {
+	builder := sq.Update(notes).
+		Set("deleted_at", sq.Expr("NOW()")).
+		Where(owner).
+		Where(sq.Eq{"deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
 	return builder.ToSql()
@@ -56,7 +64,7 @@ func (r *DBRepo) DeleteNotes(ctx context.Context, userId int) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query, args, err := getDeleteNotesQuery(userId)
+	query, args, err := getDeleteNotesQuery(ownedBy(ctx, "", userId))
 	if err != nil {
 		return err
 	}
@@ -42,7 +42,7 @@ func TestDeleteById(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "DELETE FROM notes WHERE id = $1 AND user_id = $2"
+				expectedQuery := "UPDATE notes SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND workspace_id IS NULL AND deleted_at IS NULL"
 				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
 					WithArgs(args.id, args.userId).
 					WillReturnResult(sqlmock.NewResult(0, 1))
@@ -60,7 +60,7 @@ func TestDeleteById(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "DELETE FROM notes WHERE id = $1 AND user_id = $2"
+				expectedQuery := "UPDATE notes SET deleted_at = NOW() WHERE id = $1 AND user_id = $2 AND workspace_id IS NULL AND deleted_at IS NULL"
 				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
 					WithArgs(args.id, args.userId).
 					WillReturnError(errors.New("new error"))
@@ -115,7 +115,7 @@ func TestDeleteNotes(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "DELETE FROM notes WHERE user_id = $1"
+				expectedQuery := "UPDATE notes SET deleted_at = NOW() WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL"
 				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
 					WithArgs(args.userId).
 					WillReturnResult(sqlmock.NewResult(0, 5))
@@ -132,7 +132,7 @@ func TestDeleteNotes(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "DELETE FROM notes WHERE user_id = $1"
+				expectedQuery := "UPDATE notes SET deleted_at = NOW() WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL"
 				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
 					WithArgs(args.userId).
 					WillReturnError(errors.New("new error"))
@@ -3,20 +3,58 @@ package dbrepo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pintoter/todo-list/internal/entity"
 )
 
+var noteColumns = []string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}
+
+// noteSortColumns maps sort fields accepted from clients to table columns.
+var noteSortColumns = map[string]string{
+	"id":       "id",
+	"title":    "title",
+	"date":     "date",
+	"status":   "status",
+	"priority": "priority",
+	"due_at":   "due_at",
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanNote scans noteColumns into note followed by extra columns of the query.
+func scanNote(row rowScanner, note *entity.Note, extra ...any) error {
+	var recurrence []byte
+	dest := []any{&note.ID, &note.UserId, &note.Title, &note.Description, &note.Date, &note.Status, &note.Priority, &note.DueAt, &recurrence, &note.ListId, &note.DeletedAt, &note.AssigneeId}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return err
+	}
+
+	if len(recurrence) > 0 {
+		note.Recurrence = &entity.Recurrence{}
+		return json.Unmarshal(recurrence, note.Recurrence)
+	}
+
+	return nil
+}
+
+// notDeleted excludes notes moved to the trash, trashed notes are read only by queries in notes_trash.go.
+var notDeleted = sq.Eq{"deleted_at": nil}
+
 /*-----------------------------
 					GET NOTE
  ----------------------------- */
 
-func getNoteBuilder(data any, userId int) (string, []interface{}, error) {
-	builder := sq.Select("id", "user_id", "title", "description", "date", "status").
+func getNoteBuilder(data any, owner sq.Eq) (string, []interface{}, error) {
+	builder := sq.Select(noteColumns...).
 		From(notes).
-		Where(sq.Eq{"user_id": userId}).
+		Where(owner).
+		Where(notDeleted).
 		PlaceholderFormat(sq.Dollar)
 
 	switch data.(type) {
@@ -39,13 +77,13 @@ func (r *DBRepo) GetNoteById(ctx context.Context, id, userId int) (entity.Note,
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query, args, err := getNoteBuilder(id, userId)
+	query, args, err := getNoteBuilder(id, ownedBy(ctx, "", userId))
 	if err != nil {
 		return entity.Note{}, err
 	}
 
 	var note entity.Note
-	err = tx.QueryRowContext(ctx, query, args...).Scan(&note.ID, &note.UserId, &note.Title, &note.Description, &note.Date, &note.Status)
+	err = scanNote(tx.QueryRowContext(ctx, query, args...), &note)
 	if err != nil {
 		return entity.Note{}, err
 	}
@@ -63,13 +101,13 @@ func (r *DBRepo) GetNoteByTitle(ctx context.Context, title string, userId int) (
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query, args, err := getNoteBuilder(title, userId)
+	query, args, err := getNoteBuilder(title, ownedBy(ctx, "", userId))
 	if err != nil {
 		return entity.Note{}, err
 	}
 
 	var note entity.Note
-	err = tx.QueryRowContext(ctx, query, args...).Scan(&note.ID, &note.UserId, &note.Title, &note.Description, &note.Date, &note.Status)
+	err = scanNote(tx.QueryRowContext(ctx, query, args...), &note)
 	if err != nil {
 		return entity.Note{}, err
 	}
@@ -81,21 +119,85 @@ func (r *DBRepo) GetNoteByTitle(ctx context.Context, title string, userId int) (
 					GET ALL NOTES
  ----------------------------- */
 
-func getNotesBuilder(limit, offset int, status string, date time.Time, userId int) (string, []interface{}, error) {
-	builder := sq.Select("id", "user_id", "title", "description", "date", "status").
-		From(notes).
-		OrderBy("id ASC").
-		Where(sq.Eq{"user_id": userId}).
-		PlaceholderFormat(sq.Dollar)
+func noteTagsFilter(names []string, match string) sq.Sqlizer {
+	subquery := sq.Select("nt.note_id").
+		From(noteTags + " nt").
+		Join(tags + " t ON t.id = nt.tag_id").
+		Where(sq.Eq{"t.name": names})
+
+	if match == entity.TagsMatchAll {
+		subquery = subquery.
+			GroupBy("nt.note_id").
+			Having("COUNT(DISTINCT t.name) = ?", len(names))
+	}
+
+	return sq.Expr("id IN (?)", subquery)
+}
+
+// listedBy selects notes of listings. Notes shared with the user are listed along with owned ones
+// when personal notes assigned to the user are requested, since an assignee may be a collaborator.
+func listedBy(ctx context.Context, userId int, filter entity.NoteFilter) sq.Sqlizer {
+	owner := ownedBy(ctx, "", userId)
+
+	_, inWorkspace := entity.WorkspaceFromContext(ctx)
+	if inWorkspace || filter.AssigneeId == nil || *filter.AssigneeId != userId {
+		return owner
+	}
 
-	if status != "" {
-		builder = builder.Where(sq.Eq{"status": status})
+	shared := sq.Select("note_id").
+		From(shares).
+		Where(sq.Eq{"user_id": userId})
+
+	return sq.Or{owner, sq.Expr("id IN (?)", shared)}
+}
+
+// filterNotes narrows notes selection down by the filter.
+func filterNotes(builder sq.SelectBuilder, filter entity.NoteFilter) sq.SelectBuilder {
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	if !filter.Date.Equal(time.Time{}) {
+		builder = builder.Where(sq.Eq{"date": filter.Date})
+	}
+
+	if filter.ListId != nil {
+		builder = builder.Where(sq.Eq{"list_id": listIdValue(*filter.ListId)})
+	}
+
+	if filter.AssigneeId != nil {
+		builder = builder.Where(sq.Eq{"assignee_id": assigneeValue(*filter.AssigneeId)})
+	}
+
+	if len(filter.Tags) > 0 {
+		builder = builder.Where(noteTagsFilter(filter.Tags, filter.TagsMatch))
 	}
 
-	if !date.Equal(time.Time{}) {
-		builder = builder.Where(sq.Eq{"date": date})
+	if !filter.DueBefore.IsZero() {
+		builder = builder.Where(sq.Lt{"due_at": filter.DueBefore})
 	}
 
+	if !filter.DueAfter.IsZero() {
+		builder = builder.Where(sq.Gt{"due_at": filter.DueAfter})
+	}
+
+	if filter.Overdue {
+		builder = builder.Where(sq.Expr("due_at < NOW()")).
+			Where(sq.NotEq{"status": entity.StatusDone})
+	}
+
+	return builder
+}
+
+func getNotesBuilder(limit, offset int, filter entity.NoteFilter, owner sq.Sqlizer) (string, []interface{}, error) {
+	builder := sq.Select(noteColumns...).
+		From(notes).
+		Where(owner).
+		Where(notDeleted).
+		PlaceholderFormat(sq.Dollar)
+
+	builder = pageNotes(filterNotes(builder, filter), filter)
+
 	if limit != 0 || offset != 0 {
 		builder = builder.Limit(uint64(limit)).Offset(uint64(offset))
 	}
@@ -113,7 +215,7 @@ func (r *DBRepo) GetNotes(ctx context.Context, userId int) ([]entity.Note, error
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query, args, err := getNotesBuilder(0, 0, "", time.Time{}, userId)
+	query, args, err := getNotesBuilder(0, 0, entity.NoteFilter{}, ownedBy(ctx, "", userId))
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +229,7 @@ func (r *DBRepo) GetNotes(ctx context.Context, userId int) ([]entity.Note, error
 
 	for rows.Next() {
 		var note entity.Note
-		if err := rows.Scan(&note.ID, &note.UserId, &note.Title, &note.Description, &note.Date, &note.Status); err != nil {
+		if err := scanNote(rows, &note); err != nil {
 			return nil, err
 		}
 		notes = append(notes, note)
@@ -136,7 +238,9 @@ func (r *DBRepo) GetNotes(ctx context.Context, userId int) ([]entity.Note, error
 	return notes, tx.Commit()
 }
 
-func (r *DBRepo) GetNotesExtended(ctx context.Context, limit, offset int, status string, date time.Time, userId int) ([]entity.Note, error) {
+// GetNotesExtended returns a page of notes matching the filter, notes of a backward cursor page
+// are returned in the listing order as well.
+func (r *DBRepo) GetNotesExtended(ctx context.Context, limit, offset int, filter entity.NoteFilter, userId int) ([]entity.Note, error) {
 	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelReadCommitted,
 		ReadOnly:  false,
@@ -146,7 +250,7 @@ func (r *DBRepo) GetNotesExtended(ctx context.Context, limit, offset int, status
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query, args, err := getNotesBuilder(limit, offset, status, date, userId)
+	query, args, err := getNotesBuilder(limit, offset, filter, listedBy(ctx, userId, filter))
 	if err != nil {
 		return nil, err
 	}
@@ -160,11 +264,11 @@ func (r *DBRepo) GetNotesExtended(ctx context.Context, limit, offset int, status
 
 	for rows.Next() {
 		var note entity.Note
-		if err := rows.Scan(&note.ID, &note.UserId, &note.Title, &note.Description, &note.Date, &note.Status); err != nil {
+		if err := scanNote(rows, &note); err != nil {
 			return notes, err
 		}
 		notes = append(notes, note)
 	}
 
-	return notes, tx.Commit()
+	return restoreOrder(notes, filter), tx.Commit()
 }
@@ -39,6 +39,7 @@ func TestNote_GetNoteById(t *testing.T) {
 			Description: "Test description",
 			Date:        time.Now().Round(time.Second),
 			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
 		},
 	}
 
@@ -54,10 +55,10 @@ func TestNote_GetNoteById(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status"}).
-					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status)
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
 
-				expectedQuery := "SELECT id, user_id, title, description, date, status FROM notes WHERE user_id = $1 AND id = $2"
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND id = $2"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnRows(rows)
 
 				mock.ExpectCommit()
@@ -65,14 +66,40 @@ func TestNote_GetNoteById(t *testing.T) {
 			args:     args{id: id, userId: userId},
 			wantNote: notes[0],
 		},
+		{
+			name: "Success_WithRecurrence",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil,
+						[]byte(`{"frequency":"weekly","interval":2,"weekdays":["mo","fr"],"count":3}`), nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND id = $2"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			args: args{id: id, userId: userId},
+			wantNote: func() entity.Note {
+				note := notes[0]
+				note.Recurrence = &entity.Recurrence{
+					Frequency: entity.FrequencyWeekly,
+					Interval:  2,
+					Weekdays:  []string{"mo", "fr"},
+					Count:     3,
+				}
+				return note
+			}(),
+		},
 		{
 			name: "Failed_NotFound",
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status"})
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"})
 
-				expectedQuery := "SELECT id, user_id, title, description, date, status FROM notes WHERE user_id = $1 AND id = $2"
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND id = $2"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnError(errors.New("failed test")).WillReturnRows(rows)
 
 				mock.ExpectRollback()
@@ -126,6 +153,7 @@ func TestNote_GetNoteByTitle(t *testing.T) {
 			Description: "Test description",
 			Date:        time.Now().Round(time.Second),
 			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
 		},
 	}
 
@@ -141,10 +169,10 @@ func TestNote_GetNoteByTitle(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status"}).
-					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status)
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
 
-				expectedQuery := "SELECT id, user_id, title, description, date, status FROM notes WHERE user_id = $1 AND title = $2"
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND title = $2"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.title).WillReturnRows(rows)
 
 				mock.ExpectCommit()
@@ -157,9 +185,9 @@ func TestNote_GetNoteByTitle(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status"})
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"})
 
-				expectedQuery := "SELECT id, user_id, title, description, date, status FROM notes WHERE user_id = $1 AND title = $2"
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND title = $2"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.title).WillReturnError(errors.New("failed test")).WillReturnRows(rows)
 
 				mock.ExpectRollback()
@@ -209,6 +237,7 @@ func TestGetNotes(t *testing.T) {
 			Description: "Test description 1",
 			Date:        time.Now().Round(time.Second),
 			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
 		},
 		{
 			ID:          2,
@@ -217,6 +246,7 @@ func TestGetNotes(t *testing.T) {
 			Description: "Test description 2",
 			Date:        time.Now().Round(time.Second),
 			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
 		},
 		{
 			ID:          3,
@@ -225,6 +255,7 @@ func TestGetNotes(t *testing.T) {
 			Description: "Test description 3",
 			Date:        time.Now().Round(time.Second),
 			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
 		},
 		{
 			ID:          4,
@@ -233,6 +264,7 @@ func TestGetNotes(t *testing.T) {
 			Description: "Test description 4",
 			Date:        time.Now().Round(time.Second),
 			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
 		},
 	}
 
@@ -249,13 +281,13 @@ func TestGetNotes(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status"}).
-					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status).
-					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status).
-					AddRow(notes[2].ID, notes[2].UserId, notes[2].Title, notes[2].Description, notes[2].Date, notes[2].Status).
-					AddRow(notes[3].ID, notes[3].UserId, notes[3].Title, notes[3].Description, notes[3].Date, notes[3].Status)
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status, notes[1].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[2].ID, notes[2].UserId, notes[2].Title, notes[2].Description, notes[2].Date, notes[2].Status, notes[2].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[3].ID, notes[3].UserId, notes[3].Title, notes[3].Description, notes[3].Date, notes[3].Status, notes[3].Priority, nil, nil, nil, nil, nil)
 
-				expectedQuery := "SELECT id, user_id, title, description, date, status FROM notes WHERE user_id = $1 ORDER BY id ASC"
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL ORDER BY id ASC"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId).WillReturnRows(rows)
 
 				mock.ExpectCommit()
@@ -292,14 +324,14 @@ func TestGetNotesExtended(t *testing.T) {
 	type args struct {
 		limit  int
 		offset int
-		status string
-		date   time.Time
+		filter entity.NoteFilter
 		userId int
 	}
 
 	type mockBehavior func(args args)
 
 	dateFormatted, _ := time.Parse("2006-01-02", "2020-04-18")
+	listId, inboxId := 2, 0
 
 	notes := []entity.Note{
 		{
@@ -309,6 +341,7 @@ func TestGetNotesExtended(t *testing.T) {
 			Description: "Test description 1",
 			Date:        time.Time{},
 			Status:      entity.StatusNotDone,
+			Priority:    entity.PriorityHigh,
 		},
 		{
 			ID:          2,
@@ -317,6 +350,7 @@ func TestGetNotesExtended(t *testing.T) {
 			Description: "Test description 2",
 			Date:        time.Time{},
 			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
 		},
 		{
 			ID:          3,
@@ -325,6 +359,7 @@ func TestGetNotesExtended(t *testing.T) {
 			Description: "Test description 3",
 			Date:        time.Time{},
 			Status:      entity.StatusNotDone,
+			Priority:    entity.PriorityHigh,
 		},
 		{
 			ID:          4,
@@ -333,6 +368,7 @@ func TestGetNotesExtended(t *testing.T) {
 			Description: "Test description 4",
 			Date:        time.Time{},
 			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
 		},
 	}
 
@@ -353,13 +389,13 @@ func TestGetNotesExtended(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status"}).
-					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status).
-					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status).
-					AddRow(notes[2].ID, notes[2].UserId, notes[2].Title, notes[2].Description, notes[2].Date, notes[2].Status).
-					AddRow(notes[3].ID, notes[3].UserId, notes[3].Title, notes[3].Description, notes[3].Date, notes[3].Status)
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status, notes[1].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[2].ID, notes[2].UserId, notes[2].Title, notes[2].Description, notes[2].Date, notes[2].Status, notes[2].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[3].ID, notes[3].UserId, notes[3].Title, notes[3].Description, notes[3].Date, notes[3].Status, notes[3].Priority, nil, nil, nil, nil, nil)
 
-				expectedQuery := "SELECT id, user_id, title, description, date, status FROM notes WHERE user_id = $1 ORDER BY id ASC LIMIT 5 OFFSET 5"
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL ORDER BY id ASC LIMIT 5 OFFSET 5"
 				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId).WillReturnRows(rows)
 
 				mock.ExpectCommit()
@@ -372,18 +408,18 @@ func TestGetNotesExtended(t *testing.T) {
 			args: args{
 				limit:  5,
 				offset: 5,
-				status: entity.StatusDone,
+				filter: entity.NoteFilter{Status: entity.StatusDone},
 				userId: 1,
 			},
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status"}).
-					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status).
-					AddRow(notes[3].ID, notes[3].UserId, notes[3].Title, notes[3].Description, notes[3].Date, notes[3].Status)
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status, notes[1].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[3].ID, notes[3].UserId, notes[3].Title, notes[3].Description, notes[3].Date, notes[3].Status, notes[3].Priority, nil, nil, nil, nil, nil)
 
-				expectedQuery := "SELECT id, user_id, title, description, date, status FROM notes WHERE user_id = $1 AND status = $2 ORDER BY id ASC LIMIT 5 OFFSET 5"
-				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.status).WillReturnRows(rows)
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND status = $2 ORDER BY id ASC LIMIT 5 OFFSET 5"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.filter.Status).WillReturnRows(rows)
 
 				mock.ExpectCommit()
 			},
@@ -395,19 +431,18 @@ func TestGetNotesExtended(t *testing.T) {
 			args: args{
 				limit:  5,
 				offset: 5,
-				date:   dateFormatted,
-				status: entity.StatusDone,
+				filter: entity.NoteFilter{Status: entity.StatusDone, Date: dateFormatted},
 				userId: 1,
 			},
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status"}).
-					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, dateFormatted, notes[1].Status).
-					AddRow(notes[3].ID, notes[1].UserId, notes[3].Title, notes[3].Description, dateFormatted, notes[3].Status)
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, dateFormatted, notes[1].Status, notes[1].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[3].ID, notes[1].UserId, notes[3].Title, notes[3].Description, dateFormatted, notes[3].Status, notes[3].Priority, nil, nil, nil, nil, nil)
 
-				expectedQuery := "SELECT id, user_id, title, description, date, status FROM notes WHERE user_id = $1 AND status = $2 AND date = $3 ORDER BY id ASC LIMIT 5 OFFSET 5"
-				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.status, args.date).WillReturnRows(rows)
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND status = $2 AND date = $3 ORDER BY id ASC LIMIT 5 OFFSET 5"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.filter.Status, args.filter.Date).WillReturnRows(rows)
 
 				mock.ExpectCommit()
 			},
@@ -419,6 +454,7 @@ func TestGetNotesExtended(t *testing.T) {
 					Description: notes[1].Description,
 					Date:        dateFormatted,
 					Status:      notes[1].Status,
+					Priority:    notes[1].Priority,
 				},
 				{
 					ID:          notes[3].ID,
@@ -427,16 +463,182 @@ func TestGetNotesExtended(t *testing.T) {
 					Description: notes[3].Description,
 					Date:        dateFormatted,
 					Status:      notes[3].Status,
+					Priority:    notes[3].Priority,
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "SuccessWithSort",
+			args: args{
+				limit:  5,
+				offset: 0,
+				filter: entity.NoteFilter{Sort: []entity.NoteSort{
+					{Field: "priority", Direction: entity.SortDesc},
+					{Field: "date", Direction: entity.SortAsc},
+				}},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[2].ID, notes[2].UserId, notes[2].Title, notes[2].Description, notes[2].Date, notes[2].Status, notes[2].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status, notes[1].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL ORDER BY priority DESC, date ASC, id ASC LIMIT 5 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[2], notes[1]},
+			wantErr:   false,
+		},
+		{
+			name: "SuccessWithDueRange",
+			args: args{
+				limit:  5,
+				offset: 0,
+				filter: entity.NoteFilter{
+					DueAfter:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					DueBefore: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND due_at < $2 AND due_at > $3 ORDER BY id ASC LIMIT 5 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.filter.DueBefore, args.filter.DueAfter).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0]},
+			wantErr:   false,
+		},
+		{
+			name: "SuccessOverdue",
+			args: args{
+				limit:  5,
+				offset: 0,
+				filter: entity.NoteFilter{Overdue: true},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND due_at < NOW() AND status <> $2 ORDER BY id ASC LIMIT 5 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, entity.StatusDone).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0]},
+			wantErr:   false,
+		},
+		{
+			name: "SuccessWithList",
+			args: args{
+				limit:  5,
+				offset: 0,
+				filter: entity.NoteFilter{ListId: &listId},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, listId, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND list_id = $2 ORDER BY id ASC LIMIT 5 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, listId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: func() []entity.Note {
+				note := notes[0]
+				note.ListId = &listId
+				return []entity.Note{note}
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "SuccessWithInbox",
+			args: args{
+				limit:  5,
+				offset: 0,
+				filter: entity.NoteFilter{ListId: &inboxId},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND list_id IS NULL ORDER BY id ASC LIMIT 5 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0]},
+			wantErr:   false,
+		},
+		{
+			name: "SuccessWithAnyTags",
+			args: args{
+				limit:  5,
+				offset: 0,
+				filter: entity.NoteFilter{Tags: []string{"work", "home"}, TagsMatch: entity.TagsMatchAny},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND id IN (SELECT nt.note_id FROM note_tags nt JOIN tags t ON t.id = nt.tag_id WHERE t.name IN ($2,$3)) ORDER BY id ASC LIMIT 5 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, "work", "home").WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0]},
+			wantErr:   false,
+		},
+		{
+			name: "SuccessWithAllTags",
+			args: args{
+				limit:  5,
+				offset: 0,
+				filter: entity.NoteFilter{Tags: []string{"work", "home"}, TagsMatch: entity.TagsMatchAll},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND id IN (SELECT nt.note_id FROM note_tags nt JOIN tags t ON t.id = nt.tag_id WHERE t.name IN ($2,$3) GROUP BY nt.note_id HAVING COUNT(DISTINCT t.name) = $4) ORDER BY id ASC LIMIT 5 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, "work", "home", 2).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0]},
+			wantErr:   false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			got, err := r.GetNotesExtended(context.Background(), tt.args.limit, tt.args.offset, tt.args.status, tt.args.date, tt.args.userId)
+			got, err := r.GetNotesExtended(context.Background(), tt.args.limit, tt.args.offset, tt.args.filter, tt.args.userId)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -448,3 +650,59 @@ func TestGetNotesExtended(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNotesAssignedToMe(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	me, other := 2, 3
+	columns := []string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}
+	owner := "(user_id = $1 AND workspace_id IS NULL OR id IN (SELECT note_id FROM note_shares WHERE user_id = $2))"
+
+	t.Run("shared notes assigned to me are listed", func(t *testing.T) {
+		filter := entity.NoteFilter{AssigneeId: &me}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id "+
+			"FROM notes WHERE "+owner+" AND deleted_at IS NULL AND assignee_id = $3")).
+			WithArgs(me, me, me).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(7, 1, "Shared plan", "", time.Time{}, entity.StatusNotDone, entity.PriorityLow, nil, nil, nil, nil, me))
+		mock.ExpectCommit()
+
+		got, err := r.GetNotesExtended(context.Background(), 0, 0, filter, me)
+		assert.NoError(t, err)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, 1, got[0].UserId, "the note is owned by the collaborator")
+			assert.Equal(t, &me, got[0].AssigneeId)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM notes WHERE "+owner+" AND deleted_at IS NULL AND assignee_id = $3")).
+			WithArgs(me, me, me).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectCommit()
+
+		count, err := r.CountNotes(context.Background(), filter, me)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("notes assigned to others are only owned", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND assignee_id = $2")).
+			WithArgs(me, other).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectCommit()
+
+		_, err := r.CountNotes(context.Background(), entity.NoteFilter{AssigneeId: &other}, me)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,175 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// noteSortKey is a term of notes ordering, args are bound to placeholders of expr.
+type noteSortKey struct {
+	field    string
+	expr     string
+	args     []any
+	desc     bool
+	nullable bool
+}
+
+// noteSortKeys converts sort spec into ordering terms, search results are ranked
+// by relevance first and id is always used as a tiebreaker.
+func noteSortKeys(filter entity.NoteFilter) []noteSortKey {
+	keys := make([]noteSortKey, 0, len(filter.Sort)+2)
+	if filter.Query != "" {
+		keys = append(keys, searchRankKey(filter.Query))
+	}
+
+	for _, s := range filter.Sort {
+		column, ok := noteSortColumns[s.Field]
+		if !ok {
+			continue
+		}
+
+		keys = append(keys, noteSortKey{field: s.Field, expr: column, desc: s.Direction == entity.SortDesc, nullable: column == "due_at"})
+		if column == "id" {
+			return keys
+		}
+	}
+
+	return append(keys, noteSortKey{field: "id", expr: "id"})
+}
+
+// noteCursorValue returns the value of the sort field stored in the cursor.
+func noteCursorValue(cursor *entity.NoteCursor, field string) any {
+	switch field {
+	case "rank":
+		return cursor.Rank
+	case "title":
+		return cursor.Title
+	case "date":
+		return cursor.Date
+	case "status":
+		return cursor.Status
+	case "priority":
+		return cursor.Priority
+	case "due_at":
+		if cursor.DueAt == nil {
+			return nil
+		}
+		return *cursor.DueAt
+	}
+	return cursor.ID
+}
+
+// after matches rows following value in the order of the key. NULLs come last
+// in ascending order and first in descending one.
+func (k noteSortKey) after(value any, desc bool) sq.Sqlizer {
+	switch {
+	case value == nil && desc:
+		return sq.Expr(k.expr + " IS NOT NULL")
+	case value == nil:
+		return nil
+	case desc:
+		return sq.Expr(k.expr+" < ?", append(append([]any{}, k.args...), value)...)
+	case k.nullable:
+		return sq.Or{sq.Expr(k.expr+" > ?", append(append([]any{}, k.args...), value)...), sq.Expr(k.expr + " IS NULL")}
+	}
+	return sq.Expr(k.expr+" > ?", append(append([]any{}, k.args...), value)...)
+}
+
+func (k noteSortKey) equal(value any) sq.Sqlizer {
+	if value == nil {
+		return sq.Expr(k.expr + " IS NULL")
+	}
+	return sq.Expr(k.expr+" = ?", append(append([]any{}, k.args...), value)...)
+}
+
+// keysetFilter matches rows following the cursor in the order of keys:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func keysetFilter(keys []noteSortKey, cursor *entity.NoteCursor) sq.Sqlizer {
+	filter := make(sq.Or, 0, len(keys))
+	equal := make(sq.And, 0, len(keys))
+	for _, key := range keys {
+		value := noteCursorValue(cursor, key.field)
+		if after := key.after(value, key.desc != cursor.Backward); after != nil {
+			filter = append(filter, append(append(sq.And{}, equal...), after))
+		}
+		equal = append(equal, key.equal(value))
+	}
+
+	return filter
+}
+
+// pageNotes orders notes selection by the filter and narrows it down to the notes after the cursor.
+// Backward cursor reverses the order, so the notes right before the cursor come first.
+func pageNotes(builder sq.SelectBuilder, filter entity.NoteFilter) sq.SelectBuilder {
+	keys := noteSortKeys(filter)
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+
+	if filter.Cursor != nil {
+		builder = builder.Where(keysetFilter(keys, filter.Cursor))
+	}
+
+	for _, key := range keys {
+		direction := " ASC"
+		if key.desc != backward {
+			direction = " DESC"
+		}
+		builder = builder.OrderByClause(key.expr+direction, key.args...)
+	}
+
+	return builder
+}
+
+// restoreOrder puts notes selected by a backward cursor back in the listing order.
+func restoreOrder(notes []entity.Note, filter entity.NoteFilter) []entity.Note {
+	if filter.Cursor != nil && filter.Cursor.Backward {
+		for i, j := 0, len(notes)-1; i < j; i, j = i+1, j-1 {
+			notes[i], notes[j] = notes[j], notes[i]
+		}
+	}
+	return notes
+}
+
+func countNotesBuilder(filter entity.NoteFilter, owner sq.Sqlizer) (string, []interface{}, error) {
+	builder := sq.Select("COUNT(*)").
+		From(notes).
+		Where(owner).
+		Where(notDeleted).
+		PlaceholderFormat(sq.Dollar)
+
+	if filter.Query != "" {
+		builder = matchNotes(builder, filter.Query)
+	}
+
+	return filterNotes(builder, filter).ToSql()
+}
+
+// CountNotes counts notes matching the filter on all pages, the cursor of the filter is ignored.
+func (r *DBRepo) CountNotes(ctx context.Context, filter entity.NoteFilter, userId int) (int, error) {
+	if filter.Query != "" && isBlankSearch(filter.Query) {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := countNotesBuilder(filter, listedBy(ctx, userId, filter))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, tx.Commit()
+}
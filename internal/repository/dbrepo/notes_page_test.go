@@ -0,0 +1,233 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotesExtended_Cursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		limit  int
+		filter entity.NoteFilter
+		userId int
+	}
+
+	type mockBehavior func(args args)
+
+	dueAt := time.Date(2024, 1, 5, 17, 0, 0, 0, time.UTC)
+	notes := []entity.Note{
+		{ID: 4, UserId: 1, Title: "Test title 4", Status: entity.StatusNotDone, Priority: entity.PriorityUrgent},
+		{ID: 5, UserId: 1, Title: "Test title 5", Status: entity.StatusNotDone, Priority: entity.PriorityHigh},
+	}
+	columns := []string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantNotes    []entity.Note
+		wantErr      bool
+	}{
+		{
+			name: "Forward",
+			args: args{
+				limit:  3,
+				filter: entity.NoteFilter{Cursor: &entity.NoteCursor{ID: 3}},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(columns).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status, notes[1].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND ((id > $2)) ORDER BY id ASC LIMIT 3 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, 3).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0], notes[1]},
+		},
+		{
+			name: "Backward",
+			args: args{
+				limit: 3,
+				filter: entity.NoteFilter{
+					Status: entity.StatusNotDone,
+					Sort:   []entity.NoteSort{{Field: "priority", Direction: entity.SortDesc}, {Field: "due_at", Direction: entity.SortAsc}},
+					Cursor: &entity.NoteCursor{ID: 6, Priority: entity.PriorityHigh, DueAt: &dueAt, Backward: true},
+				},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(columns).
+					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status, notes[1].Priority, nil, nil, nil, nil, nil).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND status = $2 AND ((priority > $3) OR (priority = $4 AND due_at < $5) OR (priority = $6 AND due_at = $7 AND id < $8)) ORDER BY priority ASC, due_at DESC, id DESC LIMIT 3 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, entity.StatusNotDone, entity.PriorityHigh, entity.PriorityHigh, dueAt, entity.PriorityHigh, dueAt, 6).
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0], notes[1]},
+		},
+		{
+			name: "NullCursorValue",
+			args: args{
+				limit: 3,
+				filter: entity.NoteFilter{
+					Sort:   []entity.NoteSort{{Field: "due_at", Direction: entity.SortAsc}},
+					Cursor: &entity.NoteCursor{ID: 3},
+				},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(columns).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND ((due_at IS NULL AND id > $2)) ORDER BY due_at ASC, id ASC LIMIT 3 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, 3).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			got, err := r.GetNotesExtended(context.Background(), tt.args.limit, 0, tt.args.filter, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantNotes, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCountNotes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		filter entity.NoteFilter
+		userId int
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantCount    int
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				filter: entity.NoteFilter{Status: entity.StatusDone, Cursor: &entity.NoteCursor{ID: 3}},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT COUNT(*) FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND status = $2"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, entity.StatusDone).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+				mock.ExpectCommit()
+			},
+			wantCount: 12,
+		},
+		{
+			name: "SuccessWithQuery",
+			args: args{
+				filter: entity.NoteFilter{Query: "milk"},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT COUNT(*) FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NULL AND search_vector @@ to_tsquery('simple', $2)"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.userId, "milk:*").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+				mock.ExpectCommit()
+			},
+			wantCount: 2,
+		},
+		{
+			name: "EmptyQuery",
+			args: args{
+				filter: entity.NoteFilter{Query: "?!"},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {},
+		},
+		{
+			name: "Failed",
+			args: args{
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT COUNT(.+) FROM notes").WillReturnError(errors.New("some error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			got, err := r.CountNotes(context.Background(), tt.args.filter, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCount, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// Search is backed by the search_vector column holding weighted title and description lexemes.
+// The headline document is HTML escaped, so <mark> of matches is the only markup of snippets.
+const (
+	searchConfig     = "simple"
+	headlineDocument = "replace(replace(replace(title || ' ' || COALESCE(description, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+	headlineOptions  = "StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5"
+	searchRank       = "ts_rank(search_vector, to_tsquery('" + searchConfig + "', ?))"
+)
+
+// prefixTSQuery converts user's input into a tsquery matching notes containing
+// every word of the input, the words are matched by prefix for typeahead.
+func prefixTSQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i := range words {
+		words[i] += ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
+
+// isBlankSearch reports whether the search query has no words to match, nothing is found by such a query.
+func isBlankSearch(query string) bool {
+	return prefixTSQuery(query) == ""
+}
+
+// matchNotes narrows the selection down to notes matching the search query, listings
+// and their counts go through it so they can't disagree on what matches.
+func matchNotes(builder sq.SelectBuilder, query string) sq.SelectBuilder {
+	return builder.Where(sq.Expr("search_vector @@ to_tsquery('"+searchConfig+"', ?)", prefixTSQuery(query)))
+}
+
+// searchRankKey orders search results by relevance to the query.
+func searchRankKey(query string) noteSortKey {
+	return noteSortKey{field: "rank", expr: searchRank, args: []any{prefixTSQuery(query)}, desc: true}
+}
+
+func searchNotesBuilder(limit, offset int, filter entity.NoteFilter, owner sq.Sqlizer) (string, []interface{}, error) {
+	tsQuery := prefixTSQuery(filter.Query)
+
+	builder := sq.Select(noteColumns...).
+		Column(sq.Expr("ts_headline('"+searchConfig+"', "+headlineDocument+", to_tsquery('"+searchConfig+"', ?), ?)", tsQuery, headlineOptions)).
+		Column(sq.Expr(searchRank, tsQuery)).
+		From(notes).
+		Where(owner).
+		Where(notDeleted).
+		PlaceholderFormat(sq.Dollar)
+
+	builder = matchNotes(builder, filter.Query)
+
+	builder = pageNotes(filterNotes(builder, filter), filter)
+
+	if limit != 0 || offset != 0 {
+		builder = builder.Limit(uint64(limit)).Offset(uint64(offset))
+	}
+
+	return builder.ToSql()
+}
+
+// SearchNotes finds notes by words of filter.Query in their titles and descriptions,
+// the notes are ranked by relevance and have matches highlighted in Snippet.
+// Notes of a backward cursor page are returned in the listing order as well.
+func (r *DBRepo) SearchNotes(ctx context.Context, limit, offset int, filter entity.NoteFilter, userId int) ([]entity.Note, error) {
+	if isBlankSearch(filter.Query) {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := searchNotesBuilder(limit, offset, filter, listedBy(ctx, userId, filter))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Note
+	for rows.Next() {
+		var note entity.Note
+		if err := scanNote(rows, &note, &note.Snippet, &note.Rank); err != nil {
+			return nil, err
+		}
+		result = append(result, note)
+	}
+
+	return restoreOrder(result, filter), tx.Commit()
+}
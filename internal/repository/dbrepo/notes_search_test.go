@@ -0,0 +1,181 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "milk", want: "milk:*"},
+		{query: "  Buy   MILK ", want: "buy:* & milk:*"},
+		{query: "it's 5 o'clock!", want: "it:* & s:* & 5:* & o:* & clock:*"},
+		{query: "купить хлеб", want: "купить:* & хлеб:*"},
+		{query: "&|!():*", want: ""},
+		{query: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, prefixTSQuery(tt.query))
+		})
+	}
+}
+
+func TestSearchNotes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		limit  int
+		offset int
+		filter entity.NoteFilter
+		userId int
+	}
+
+	type mockBehavior func(args args)
+
+	notes := []entity.Note{
+		{
+			ID:          1,
+			UserId:      1,
+			Title:       "Buy milk",
+			Description: "Two bottles",
+			Date:        time.Time{},
+			Status:      entity.StatusNotDone,
+			Priority:    entity.PriorityHigh,
+			Snippet:     "Buy <mark>milk</mark> Two bottles",
+			Rank:        0.6079271,
+		},
+		{
+			ID:          2,
+			UserId:      1,
+			Title:       "Groceries",
+			Description: "Milkshake and bread",
+			Date:        time.Time{},
+			Status:      entity.StatusDone,
+			Priority:    entity.PriorityMedium,
+			Snippet:     "Groceries <mark>Milkshake</mark> and bread",
+			Rank:        0.0607927,
+		},
+	}
+
+	columns := []string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id", "ts_headline", "ts_rank"}
+	headline := "ts_headline('simple', replace(replace(replace(title || ' ' || COALESCE(description, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), to_tsquery('simple', $1), $2), ts_rank(search_vector, to_tsquery('simple', $3))"
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantNotes    []entity.Note
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{
+				limit:  5,
+				offset: 0,
+				filter: entity.NoteFilter{Query: "Milk"},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(columns).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil, notes[0].Snippet, notes[0].Rank).
+					AddRow(notes[1].ID, notes[1].UserId, notes[1].Title, notes[1].Description, notes[1].Date, notes[1].Status, notes[1].Priority, nil, nil, nil, nil, nil, notes[1].Snippet, notes[1].Rank)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id, " + headline + " FROM notes WHERE user_id = $4 AND workspace_id IS NULL AND deleted_at IS NULL AND search_vector @@ to_tsquery('simple', $5) ORDER BY ts_rank(search_vector, to_tsquery('simple', $6)) DESC, id ASC LIMIT 5 OFFSET 0"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("milk:*", headlineOptions, "milk:*", args.userId, "milk:*", "milk:*").
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0], notes[1]},
+		},
+		{
+			name: "SuccessWithStatusAndSort",
+			args: args{
+				limit:  5,
+				offset: 5,
+				filter: entity.NoteFilter{
+					Query:  "buy milk",
+					Status: entity.StatusNotDone,
+					Sort:   []entity.NoteSort{{Field: "priority", Direction: entity.SortDesc}},
+				},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(columns).
+					AddRow(notes[0].ID, notes[0].UserId, notes[0].Title, notes[0].Description, notes[0].Date, notes[0].Status, notes[0].Priority, nil, nil, nil, nil, nil, notes[0].Snippet, notes[0].Rank)
+
+				expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id, " + headline + " FROM notes WHERE user_id = $4 AND workspace_id IS NULL AND deleted_at IS NULL AND search_vector @@ to_tsquery('simple', $5) AND status = $6 ORDER BY ts_rank(search_vector, to_tsquery('simple', $7)) DESC, priority DESC, id ASC LIMIT 5 OFFSET 5"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs("buy:* & milk:*", headlineOptions, "buy:* & milk:*", args.userId, "buy:* & milk:*", args.filter.Status, "buy:* & milk:*").
+					WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantNotes: []entity.Note{notes[0]},
+		},
+		{
+			name: "EmptyQuery",
+			args: args{
+				limit:  5,
+				filter: entity.NoteFilter{Query: "?!"},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {},
+		},
+		{
+			name: "Failed",
+			args: args{
+				limit:  5,
+				filter: entity.NoteFilter{Query: "milk"},
+				userId: 1,
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT (.+) FROM notes").WillReturnError(errors.New("some error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+			got, err := r.SearchNotes(context.Background(), tt.args.limit, tt.args.offset, tt.args.filter, tt.args.userId)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantNotes, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+var deleted = sq.NotEq{"deleted_at": nil}
+
+/*-----------------------------
+					GET TRASHED NOTES
+ ----------------------------- */
+
+func getTrashBuilder(id int, owner sq.Eq) (string, []interface{}, error) {
+	builder := sq.Select(noteColumns...).
+		From(notes).
+		Where(owner).
+		Where(deleted).
+		PlaceholderFormat(sq.Dollar)
+
+	if id != 0 {
+		builder = builder.Where(sq.Eq{"id": id})
+	} else {
+		builder = builder.OrderBy("deleted_at DESC", "id ASC")
+	}
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetTrashedNoteById(ctx context.Context, id, userId int) (entity.Note, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Note{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getTrashBuilder(id, ownedBy(ctx, "", userId))
+	if err != nil {
+		return entity.Note{}, err
+	}
+
+	var note entity.Note
+	err = scanNote(tx.QueryRowContext(ctx, query, args...), &note)
+	if err != nil {
+		return entity.Note{}, err
+	}
+
+	return note, tx.Commit()
+}
+
+func (r *DBRepo) GetTrashedNotes(ctx context.Context, userId int) ([]entity.Note, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getTrashBuilder(0, ownedBy(ctx, "", userId))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Note
+	for rows.Next() {
+		var note entity.Note
+		if err := scanNote(rows, &note); err != nil {
+			return nil, err
+		}
+		result = append(result, note)
+	}
+
+	return result, tx.Commit()
+}
+
+/*-----------------------------
+					RESTORE NOTE
+ ----------------------------- */
+
+func restoreNoteBuilder(id int, owner sq.Eq) (string, []interface{}, error) {
+	builder := sq.Update(notes).
+		Set("deleted_at", nil).
+		Where(sq.Eq{"id": id}).
+		Where(owner).
+		Where(deleted).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) RestoreNote(ctx context.Context, id, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := restoreNoteBuilder(id, ownedBy(ctx, "", userId))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+/*-----------------------------
+					PURGE NOTES
+ ----------------------------- */
+
+func purgeNotesBuilder(where sq.Sqlizer) (string, []interface{}, error) {
+	builder := sq.Delete(notes).
+		Where(where).
+		Where(deleted).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// purgeAttachmentsBuilder deletes attachments of the notes about to be purged returning keys of their blobs.
+func purgeAttachmentsBuilder(where sq.Sqlizer) (string, []interface{}, error) {
+	subquery := sq.Select("id").
+		From(notes).
+		Where(where).
+		Where(deleted)
+
+	builder := sq.Delete(attachments).
+		Where(sq.Expr("note_id IN (?)", subquery)).
+		Suffix("RETURNING blob_key").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// purgeNotes deletes the trashed notes with their attachments, keys of blobs of the attachments are returned
+// for the caller to delete them once the notes are gone.
+func (r *DBRepo) purgeNotes(ctx context.Context, where sq.Sqlizer) (int64, []string, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := purgeAttachmentsBuilder(where)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return 0, nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	query, args, err = purgeNotesBuilder(where)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return purged, keys, tx.Commit()
+}
+
+// PurgeNote permanently deletes the trashed note returning keys of blobs of its attachments.
+func (r *DBRepo) PurgeNote(ctx context.Context, id, userId int) ([]string, error) {
+	where := ownedBy(ctx, "", userId)
+	where["id"] = id
+
+	_, keys, err := r.purgeNotes(ctx, where)
+	return keys, err
+}
+
+// PurgeNotes empties the trash of the user returning keys of blobs of attachments of the notes.
+func (r *DBRepo) PurgeNotes(ctx context.Context, userId int) ([]string, error) {
+	_, keys, err := r.purgeNotes(ctx, ownedBy(ctx, "", userId))
+	return keys, err
+}
+
+// PurgeTrashedBefore permanently deletes notes of all users trashed before the moment.
+func (r *DBRepo) PurgeTrashedBefore(ctx context.Context, before time.Time) (int64, []string, error) {
+	return r.purgeNotes(ctx, sq.Lt{"deleted_at": before})
+}
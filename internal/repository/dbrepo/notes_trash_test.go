@@ -0,0 +1,271 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTrashedNoteById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		userId int
+	}
+
+	type mockBehavior func(args args)
+
+	deletedAt := time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)
+	note := entity.Note{
+		ID:        1,
+		UserId:    1,
+		Title:     "title",
+		Date:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Status:    entity.StatusNotDone,
+		Priority:  entity.PriorityMedium,
+		DeletedAt: &deletedAt,
+	}
+	expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NOT NULL AND id = $2"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantNote     entity.Note
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"}).
+					AddRow(note.ID, note.UserId, note.Title, note.Description, note.Date, note.Status, note.Priority, nil, nil, nil, deletedAt, nil)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			args:     args{id: note.ID, userId: note.UserId},
+			wantNote: note,
+		},
+		{
+			name: "Failed_NotFound",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			args:     args{id: 100, userId: note.UserId},
+			wantNote: entity.Note{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			got, err := r.GetTrashedNoteById(context.Background(), tt.args.id, tt.args.userId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantNote, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetTrashedNotes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	userId := 1
+	deletedAt := time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)
+	notes := []entity.Note{
+		{ID: 2, UserId: userId, Title: "two", Date: deletedAt, Status: entity.StatusDone, Priority: entity.PriorityLow, DeletedAt: &deletedAt},
+		{ID: 1, UserId: userId, Title: "one", Date: deletedAt, Status: entity.StatusNotDone, Priority: entity.PriorityHigh, DeletedAt: &deletedAt},
+	}
+
+	mock.ExpectBegin()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "title", "description", "date", "status", "priority", "due_at", "recurrence", "list_id", "deleted_at", "assignee_id"})
+	for _, note := range notes {
+		rows.AddRow(note.ID, note.UserId, note.Title, note.Description, note.Date, note.Status, note.Priority, nil, nil, nil, deletedAt, nil)
+	}
+
+	expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id ASC"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(userId).WillReturnRows(rows)
+
+	mock.ExpectCommit()
+
+	got, err := r.GetTrashedNotes(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.Equal(t, notes, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreNote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		userId int
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "UPDATE notes SET deleted_at = $1 WHERE id = $2 AND user_id = $3 AND workspace_id IS NULL AND deleted_at IS NOT NULL"
+
+	tests := []struct {
+		name         string
+		args         args
+		mockBehavior mockBehavior
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			args: args{id: 1, userId: 1},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed",
+			args: args{id: 100, userId: 1},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(nil, args.id, args.userId).
+					WillReturnError(errors.New("new error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.RestoreNote(context.Background(), tt.args.id, tt.args.userId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPurgeNotes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	keyRows := func(keys ...string) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"blob_key"})
+		for _, key := range keys {
+			rows.AddRow(key)
+		}
+		return rows
+	}
+
+	t.Run("PurgeNote", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM note_attachments WHERE note_id IN (SELECT id FROM notes "+
+			"WHERE id = $1 AND user_id = $2 AND workspace_id IS NULL AND deleted_at IS NOT NULL) RETURNING blob_key")).
+			WithArgs(1, 1).
+			WillReturnRows(keyRows("notes/1/a", "notes/1/b"))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM notes WHERE id = $1 AND user_id = $2 AND workspace_id IS NULL AND deleted_at IS NOT NULL")).
+			WithArgs(1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		keys, err := r.PurgeNote(context.Background(), 1, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"notes/1/a", "notes/1/b"}, keys)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("PurgeNotes", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM note_attachments WHERE note_id IN (SELECT id FROM notes " +
+			"WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NOT NULL) RETURNING blob_key")).
+			WithArgs(1).
+			WillReturnRows(keyRows())
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM notes WHERE user_id = $1 AND workspace_id IS NULL AND deleted_at IS NOT NULL")).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		keys, err := r.PurgeNotes(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("PurgeTrashedBefore", func(t *testing.T) {
+		before := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM note_attachments WHERE note_id IN (SELECT id FROM notes " +
+			"WHERE deleted_at < $1 AND deleted_at IS NOT NULL) RETURNING blob_key")).
+			WithArgs(before).
+			WillReturnRows(keyRows("notes/4/a"))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM notes WHERE deleted_at < $1 AND deleted_at IS NOT NULL")).
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectCommit()
+
+		purged, keys, err := r.PurgeTrashedBefore(context.Background(), before)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), purged)
+		assert.Equal(t, []string{"notes/4/a"}, keys)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -5,29 +5,62 @@ import (
 	"database/sql"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
 )
 
-func updateBuilder(id, userId int, title, description, status string) (string, []interface{}, error) {
+func updateBuilder(id int, owner sq.Eq, upd entity.NoteUpdate) (string, []interface{}, error) {
 	builder := sq.Update(notes).
-		Where(sq.Eq{"id": id, "user_id": userId}).
+		Where(sq.Eq{"id": id}).
+		Where(owner).
 		PlaceholderFormat(sq.Dollar)
 
-	if title != "" {
-		builder = builder.Set("title", title)
+	if upd.Title != "" {
+		builder = builder.Set("title", upd.Title)
 	}
 
-	if description != "" {
-		builder = builder.Set("description", description)
+	if upd.Description != "" {
+		builder = builder.Set("description", upd.Description)
 	}
 
-	if status != "" {
-		builder = builder.Set("status", status)
+	if upd.Status != "" {
+		builder = builder.Set("status", upd.Status)
+	}
+
+	if upd.Priority != "" {
+		builder = builder.Set("priority", upd.Priority)
+	}
+
+	if !upd.Date.IsZero() {
+		builder = builder.Set("date", upd.Date)
+	}
+
+	if upd.DueAt != nil {
+		builder = builder.Set("due_at", *upd.DueAt)
+	}
+
+	if upd.ClearRecurrence {
+		builder = builder.Set("recurrence", nil)
+	} else if upd.Recurrence != nil {
+		recurrence, err := recurrenceValue(upd.Recurrence)
+		if err != nil {
+			return "", nil, err
+		}
+		builder = builder.Set("recurrence", recurrence)
+	}
+
+	if upd.ListId != nil {
+		builder = builder.Set("list_id", listIdValue(*upd.ListId))
 	}
 
 	return builder.ToSql()
 }
 
-func (r *DBRepo) UpdateNote(ctx context.Context, id, userId int, title, description, status string) error {
+func hasNoteChanges(upd entity.NoteUpdate) bool {
+	return upd.Title != "" || upd.Description != "" || upd.Status != "" || upd.Priority != "" ||
+		!upd.Date.IsZero() || upd.DueAt != nil || upd.Recurrence != nil || upd.ClearRecurrence || upd.ListId != nil
+}
+
+func (r *DBRepo) UpdateNote(ctx context.Context, id, userId int, upd entity.NoteUpdate) error {
 	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelReadCommitted,
 		ReadOnly:  false,
@@ -37,15 +70,137 @@ func (r *DBRepo) UpdateNote(ctx context.Context, id, userId int, title, descript
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	query, args, err := updateBuilder(id, userId, title, description, status)
+	if hasNoteChanges(upd) {
+		query, args, err := updateBuilder(id, ownedBy(ctx, "", userId), upd)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if upd.Tags != nil {
+		if err = setNoteTags(ctx, tx, id, userId, upd.Tags); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func replaceNoteBuilder(note entity.Note, owner sq.Eq) (string, []interface{}, error) {
+	recurrence, err := recurrenceValue(note.Recurrence)
+	if err != nil {
+		return "", nil, err
+	}
+
+	listId := 0
+	if note.ListId != nil {
+		listId = *note.ListId
+	}
+
+	builder := sq.Update(notes).
+		Set("title", note.Title).
+		Set("description", note.Description).
+		Set("date", note.Date).
+		Set("status", note.Status).
+		Set("priority", note.Priority).
+		Set("due_at", note.DueAt).
+		Set("recurrence", recurrence).
+		Set("list_id", listIdValue(listId)).
+		Where(sq.Eq{"id": note.ID}).
+		Where(owner).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// ReplaceNote overwrites all editable fields and tags of the note, it's used to revert the note to a revision.
+func (r *DBRepo) ReplaceNote(ctx context.Context, note entity.Note) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
 	if err != nil {
 		return err
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	_, err = tx.ExecContext(ctx, query, args...)
+	query, args, err := replaceNoteBuilder(note, ownedBy(ctx, "", note.UserId))
 	if err != nil {
 		return err
 	}
 
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	if err = setNoteTags(ctx, tx, note.ID, note.UserId, note.Tags); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// assigneeValue converts assignee id into column value, zero stands for an unassigned note and is stored as NULL.
+func assigneeValue(assigneeId int) any {
+	if assigneeId == 0 {
+		return nil
+	}
+
+	return assigneeId
+}
+
+func assignNoteBuilder(id int, owner sq.Eq, assigneeId int) (string, []interface{}, error) {
+	builder := sq.Update(notes).
+		Set("assignee_id", assigneeValue(assigneeId)).
+		Where(sq.Eq{"id": id}).
+		Where(owner).
+		Where(notDeleted).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// AssignNote sets the assignee of the note, zero assignee unassigns the note.
+func (r *DBRepo) AssignNote(ctx context.Context, id, userId, assigneeId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := assignNoteBuilder(id, ownedBy(ctx, "", userId), assigneeId)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return sql.ErrNoRows
+	}
+
 	return tx.Commit()
 }
+
+// unassignBuilder clears the assignee of notes matching where, it's used when the assignee loses access to them.
+func unassignBuilder(where sq.Eq) (string, []interface{}, error) {
+	builder := sq.Update(notes).
+		Set("assignee_id", nil).
+		Where(where).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
@@ -2,9 +2,11 @@ package dbrepo
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/pintoter/todo-list/internal/entity"
@@ -21,11 +23,9 @@ func TestUpdateNote(t *testing.T) {
 	r := New(db)
 
 	type args struct {
-		id          int
-		userId      int
-		title       string
-		description string
-		status      string
+		id     int
+		userId int
+		upd    entity.NoteUpdate
 	}
 
 	type mockBehavior func(args args)
@@ -39,18 +39,68 @@ func TestUpdateNote(t *testing.T) {
 		{
 			name: "Success",
 			args: args{
-				id:          1,
-				userId:      1,
-				title:       "Test title NEW",
-				description: "Test description NEW",
-				status:      entity.StatusDone,
+				id:     1,
+				userId: 1,
+				upd: entity.NoteUpdate{
+					Title:       "Test title NEW",
+					Description: "Test description NEW",
+					Status:      entity.StatusDone,
+					Priority:    entity.PriorityUrgent,
+				},
 			},
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "UPDATE notes SET title = $1, description = $2, status = $3 WHERE id = $4 AND user_id = $5"
+				expectedQuery := "UPDATE notes SET title = $1, description = $2, status = $3, priority = $4 WHERE id = $5 AND user_id = $6 AND workspace_id IS NULL"
 				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.title, args.description, args.status, args.id, args.userId).
+					WithArgs(args.upd.Title, args.upd.Description, args.upd.Status, args.upd.Priority, args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "SuccessWithTags",
+			args: args{
+				id:     1,
+				userId: 1,
+				upd:    entity.NoteUpdate{Tags: []string{"work"}},
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM note_tags WHERE note_id = $1")).
+					WithArgs(args.id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tags (user_id,name) VALUES ($1,$2) ON CONFLICT (user_id, name) DO NOTHING")).
+					WithArgs(args.userId, "work").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO note_tags (note_id,tag_id) SELECT $1, id FROM tags WHERE name IN ($2) AND user_id = $3")).
+					WithArgs(args.id, "work", args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "SuccessWithRecurrence",
+			args: args{
+				id:     1,
+				userId: 1,
+				upd: entity.NoteUpdate{
+					Recurrence: &entity.Recurrence{Frequency: entity.FrequencyDaily, Interval: 3},
+				},
+			},
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "UPDATE notes SET recurrence = $1 WHERE id = $2 AND user_id = $3 AND workspace_id IS NULL"
+				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(`{"frequency":"daily","interval":3}`, args.id, args.userId).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
 				mock.ExpectCommit()
@@ -60,18 +110,20 @@ func TestUpdateNote(t *testing.T) {
 		{
 			name: "Failed",
 			args: args{
-				id:          100,
-				userId:      1,
-				title:       "Test title NEW",
-				description: "Test description NEW",
-				status:      entity.StatusDone,
+				id:     100,
+				userId: 1,
+				upd: entity.NoteUpdate{
+					Title:       "Test title NEW",
+					Description: "Test description NEW",
+					Status:      entity.StatusDone,
+				},
 			},
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectedQuery := "UPDATE notes SET title = $1, description = $2, status = $3 WHERE id = $4 AND user_id = $5"
+				expectedQuery := "UPDATE notes SET title = $1, description = $2, status = $3 WHERE id = $4 AND user_id = $5 AND workspace_id IS NULL"
 				mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-					WithArgs(args.title, args.description, args.status, args.id, args.userId).
+					WithArgs(args.upd.Title, args.upd.Description, args.upd.Status, args.id, args.userId).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
 				mock.ExpectRollback()
@@ -83,7 +135,7 @@ func TestUpdateNote(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(tt.args)
-			err := r.UpdateNote(context.Background(), tt.args.id, tt.args.userId, tt.args.title, tt.args.description, tt.args.status)
+			err := r.UpdateNote(context.Background(), tt.args.id, tt.args.userId, tt.args.upd)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -93,3 +145,80 @@ func TestUpdateNote(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceNote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	listId := 2
+	note := entity.Note{
+		ID:          1,
+		UserId:      1,
+		Title:       "title",
+		Description: "description",
+		Date:        time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC),
+		Status:      entity.StatusNotDone,
+		Priority:    entity.PriorityHigh,
+		ListId:      &listId,
+		Tags:        []string{"work"},
+	}
+
+	mock.ExpectBegin()
+
+	expectedQuery := "UPDATE notes SET title = $1, description = $2, date = $3, status = $4, priority = $5, due_at = $6, recurrence = $7, list_id = $8 WHERE id = $9 AND user_id = $10 AND workspace_id IS NULL"
+	mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(note.Title, note.Description, note.Date, note.Status, note.Priority, nil, nil, listId, note.ID, note.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM note_tags WHERE note_id = $1")).
+		WithArgs(note.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tags (user_id,name) VALUES ($1,$2) ON CONFLICT (user_id, name) DO NOTHING")).
+		WithArgs(note.UserId, "work").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO note_tags (note_id,tag_id) SELECT $1, id FROM tags WHERE name IN ($2) AND user_id = $3")).
+		WithArgs(note.ID, "work", note.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	err = r.ReplaceNote(context.Background(), note)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignNote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	expectedQuery := "UPDATE notes SET assignee_id = $1 WHERE id = $2 AND user_id = $3 AND workspace_id IS NULL AND deleted_at IS NULL"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(2, 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.AssignNote(context.Background(), 1, 1, 2))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(nil, 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.AssignNote(context.Background(), 1, 1, 0), sql.ErrNoRows, "missing note must not be unassigned")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
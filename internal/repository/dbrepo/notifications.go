@@ -0,0 +1,161 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+var notificationColumns = []string{"id", "user_id", "type", "note_id", "actor_id", "created_at", "read_at"}
+
+func scanNotification(row rowScanner, notification *entity.Notification) error {
+	return row.Scan(&notification.ID, &notification.UserId, &notification.Type, &notification.NoteId,
+		&notification.ActorId, &notification.CreatedAt, &notification.ReadAt)
+}
+
+func createNotificationBuilder(notification entity.Notification) (string, []interface{}, error) {
+	builder := sq.Insert(notifications).
+		Columns("user_id", "type", "note_id", "actor_id").
+		Values(notification.UserId, notification.Type, notification.NoteId, notification.ActorId).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateNotification(ctx context.Context, notification entity.Notification) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createNotificationBuilder(notification)
+	if err != nil {
+		return 0, err
+	}
+
+	var notificationId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&notificationId)
+	if err != nil {
+		return 0, err
+	}
+
+	return notificationId, tx.Commit()
+}
+
+func getNotificationsBuilder(userId int, unread bool, limit int) (string, []interface{}, error) {
+	builder := sq.Select(notificationColumns...).
+		From(notifications).
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar)
+
+	if unread {
+		builder = builder.Where(sq.Eq{"read_at": nil})
+	}
+
+	return builder.ToSql()
+}
+
+// GetNotifications returns the latest notifications of the user, only unread ones when unread is set.
+func (r *DBRepo) GetNotifications(ctx context.Context, userId int, unread bool, limit int) ([]entity.Notification, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getNotificationsBuilder(userId, unread, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Notification
+	for rows.Next() {
+		var notification entity.Notification
+		if err := scanNotification(rows, &notification); err != nil {
+			return nil, err
+		}
+		result = append(result, notification)
+	}
+
+	return result, tx.Commit()
+}
+
+func readNotificationsBuilder(where sq.Eq) (string, []interface{}, error) {
+	builder := sq.Update(notifications).
+		Set("read_at", sq.Expr("COALESCE(read_at, NOW())")).
+		Where(where).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// ReadNotification marks the notification of the user as read, reading it again keeps the time it was first read.
+func (r *DBRepo) ReadNotification(ctx context.Context, id, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := readNotificationsBuilder(sq.Eq{"id": id, "user_id": userId})
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// ReadNotifications marks all notifications of the user as read.
+func (r *DBRepo) ReadNotifications(ctx context.Context, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := readNotificationsBuilder(sq.Eq{"user_id": userId, "read_at": nil})
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,73 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNotifications(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	expectedQuery := "SELECT id, user_id, type, note_id, actor_id, created_at, read_at FROM notifications " +
+		"WHERE user_id = $1 AND read_at IS NULL ORDER BY id DESC LIMIT 20"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(notificationColumns).
+			AddRow(5, 2, entity.NotificationAssigned, 3, 1, now, nil))
+	mock.ExpectCommit()
+
+	got, err := r.GetNotifications(context.Background(), 2, true, 20)
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, entity.NotificationAssigned, got[0].Type)
+		assert.Equal(t, 3, *got[0].NoteId)
+		assert.Nil(t, got[0].ReadAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadNotification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	expectedQuery := "UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(5, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.ReadNotification(context.Background(), 5, 2))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(5, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.ReadNotification(context.Background(), 5, 3), sql.ErrNoRows, "notifications of others must be hidden")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
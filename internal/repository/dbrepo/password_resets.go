@@ -0,0 +1,124 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func createPasswordResetBuilder(reset entity.PasswordReset) (string, []interface{}, error) {
+	builder := sq.Insert(resets).
+		Columns("user_id", "token_hash", "expires_at").
+		Values(reset.UserId, reset.TokenHash, reset.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreatePasswordReset(ctx context.Context, reset entity.PasswordReset) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createPasswordResetBuilder(reset)
+	if err != nil {
+		return 0, err
+	}
+
+	var resetId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&resetId)
+	if err != nil {
+		return 0, err
+	}
+
+	return resetId, tx.Commit()
+}
+
+func usePasswordResetBuilder(tokenHash string) (string, []interface{}, error) {
+	builder := sq.Update(resets).
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"token_hash": tokenHash, "used_at": nil}).
+		Where(sq.Expr("expires_at > NOW()")).
+		Suffix("RETURNING user_id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func deletePendingResetsBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Delete(resets).
+		Where(sq.Eq{"user_id": userId, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// ResetPassword uses the reset token with the hash to replace the password of its user, other reset tokens,
+// sessions and personal access tokens of the user are deleted. Returns the id of the user or sql.ErrNoRows when the token
+// is unknown, expired or used.
+func (r *DBRepo) ResetPassword(ctx context.Context, tokenHash, password string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := usePasswordResetBuilder(tokenHash)
+	if err != nil {
+		return 0, err
+	}
+
+	var userId int
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(&userId); err != nil {
+		return 0, err
+	}
+
+	query, args, err = updateUserPasswordBuilder(userId, password)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	query, args, err = deleteSessionsBuilder(sq.Eq{"user_id": userId})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	query, args, err = deleteAccessTokensBuilder(sq.Eq{"user_id": userId})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	query, args, err = deletePendingResetsBuilder(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	return userId, tx.Commit()
+}
@@ -0,0 +1,117 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePasswordReset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	reset := entity.PasswordReset{UserId: 1, TokenHash: "hash", ExpiresAt: time.Now().Round(time.Second)}
+
+	mock.ExpectBegin()
+	expectedQuery := "INSERT INTO password_resets (user_id,token_hash,expires_at) VALUES ($1,$2,$3) RETURNING id"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(reset.UserId, reset.TokenHash, reset.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+
+	id, err := r.CreatePasswordReset(context.Background(), reset)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResetPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type mockBehavior func(tokenHash, password string)
+
+	useQuery := "UPDATE password_resets SET used_at = NOW() WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() RETURNING user_id"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantUserId   int
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(tokenHash, password string) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(useQuery)).
+					WithArgs(tokenHash).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password = $1 WHERE id = $2")).
+					WithArgs(password, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM sessions WHERE user_id = $1")).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM personal_access_tokens WHERE user_id = $1")).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM password_resets WHERE used_at IS NULL AND user_id = $1")).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectCommit()
+			},
+			wantUserId: 1,
+		},
+		{
+			name: "InvalidToken",
+			mockBehavior: func(tokenHash, password string) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(useQuery)).
+					WithArgs(tokenHash).
+					WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior("hash", "password_hash")
+
+			userId, err := r.ResetPassword(context.Background(), "hash", "password_hash")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantUserId, userId)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
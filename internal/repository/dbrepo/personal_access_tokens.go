@@ -0,0 +1,201 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// scopes are stored space-separated like OAuth scopes.
+var accessTokenColumns = []string{"id", "user_id", "name", "token_hash", "scopes", "created_at", "last_used_at", "expires_at"}
+
+func scanAccessToken(row rowScanner, token *entity.PersonalAccessToken) error {
+	var scopes string
+	err := row.Scan(&token.ID, &token.UserId, &token.Name, &token.TokenHash, &scopes, &token.CreatedAt,
+		&token.LastUsedAt, &token.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	token.Scopes = strings.Fields(scopes)
+	return nil
+}
+
+func createAccessTokenBuilder(token entity.PersonalAccessToken) (string, []interface{}, error) {
+	builder := sq.Insert(accessTokens).
+		Columns("user_id", "name", "token_hash", "scopes", "expires_at").
+		Values(token.UserId, token.Name, token.TokenHash, strings.Join(token.Scopes, " "), token.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreatePersonalAccessToken(ctx context.Context, token entity.PersonalAccessToken) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createAccessTokenBuilder(token)
+	if err != nil {
+		return 0, err
+	}
+
+	var tokenId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&tokenId)
+	if err != nil {
+		return 0, err
+	}
+
+	return tokenId, tx.Commit()
+}
+
+func getAccessTokensBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Select(accessTokenColumns...).
+		From(accessTokens).
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("created_at DESC", "id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetPersonalAccessTokens returns tokens of the user including expired ones, so they can be told apart
+// from deleted ones.
+func (r *DBRepo) GetPersonalAccessTokens(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getAccessTokensBuilder(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.PersonalAccessToken
+	for rows.Next() {
+		var token entity.PersonalAccessToken
+		if err := scanAccessToken(rows, &token); err != nil {
+			return nil, err
+		}
+		result = append(result, token)
+	}
+
+	return result, tx.Commit()
+}
+
+func useAccessTokenBuilder(tokenHash string) (string, []interface{}, error) {
+	builder := sq.Update(accessTokens).
+		Set("last_used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Where(sq.Or{sq.Eq{"expires_at": nil}, sq.Expr("expires_at > NOW()")}).
+		Suffix("RETURNING " + strings.Join(accessTokenColumns, ", ")).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// UsePersonalAccessToken returns the token with the hash recording it as used now, sql.ErrNoRows
+// is returned when the token is unknown or expired.
+func (r *DBRepo) UsePersonalAccessToken(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.PersonalAccessToken{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := useAccessTokenBuilder(tokenHash)
+	if err != nil {
+		return entity.PersonalAccessToken{}, err
+	}
+
+	var token entity.PersonalAccessToken
+	err = scanAccessToken(tx.QueryRowContext(ctx, query, args...), &token)
+	if err != nil {
+		return entity.PersonalAccessToken{}, err
+	}
+
+	return token, tx.Commit()
+}
+
+func deleteAccessTokensBuilder(where sq.Eq) (string, []interface{}, error) {
+	builder := sq.Delete(accessTokens).
+		Where(where).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) DeletePersonalAccessToken(ctx context.Context, id, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteAccessTokensBuilder(sq.Eq{"id": id, "user_id": userId})
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// DeletePersonalAccessTokens revokes all personal access tokens of the user.
+func (r *DBRepo) DeletePersonalAccessTokens(ctx context.Context, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteAccessTokensBuilder(sq.Eq{"user_id": userId})
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
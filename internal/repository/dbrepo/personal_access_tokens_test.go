@@ -0,0 +1,156 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePersonalAccessToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	token := entity.PersonalAccessToken{
+		UserId:    1,
+		Name:      "ci",
+		TokenHash: "hash",
+		Scopes:    []string{entity.ScopeNotesRead, entity.ScopeNotesWrite},
+	}
+
+	mock.ExpectBegin()
+	expectedQuery := "INSERT INTO personal_access_tokens (user_id,name,token_hash,scopes,expires_at) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(token.UserId, token.Name, token.TokenHash, "notes:read notes:write", token.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	id, err := r.CreatePersonalAccessToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUsePersonalAccessToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type mockBehavior func(tokenHash string)
+
+	useQuery := "UPDATE personal_access_tokens SET last_used_at = NOW() WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > NOW()) " +
+		"RETURNING id, user_id, name, token_hash, scopes, created_at, last_used_at, expires_at"
+
+	now := time.Now().Round(time.Second)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		want         entity.PersonalAccessToken
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(tokenHash string) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(useQuery)).
+					WithArgs(tokenHash).
+					WillReturnRows(sqlmock.NewRows(accessTokenColumns).
+						AddRow(2, 1, "ci", tokenHash, "notes:read", now, now, nil))
+
+				mock.ExpectCommit()
+			},
+			want: entity.PersonalAccessToken{
+				ID:         2,
+				UserId:     1,
+				Name:       "ci",
+				TokenHash:  "hash",
+				Scopes:     []string{entity.ScopeNotesRead},
+				CreatedAt:  now,
+				LastUsedAt: &now,
+			},
+		},
+		{
+			name: "Expired",
+			mockBehavior: func(tokenHash string) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(useQuery)).
+					WithArgs(tokenHash).
+					WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior("hash")
+
+			got, err := r.UsePersonalAccessToken(context.Background(), "hash")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeletePersonalAccessToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2")).
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, r.DeletePersonalAccessToken(context.Background(), 2, 1), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeletePersonalAccessTokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM personal_access_tokens WHERE user_id = $1")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.DeletePersonalAccessTokens(context.Background(), 1), "user without tokens isn't an error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
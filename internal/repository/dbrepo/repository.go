@@ -3,8 +3,28 @@ package dbrepo
 import "database/sql"
 
 const (
-	notes = "notes"
-	users = "users"
+	notes         = "notes"
+	users         = "users"
+	tags          = "tags"
+	noteTags      = "note_tags"
+	items         = "note_items"
+	lists         = "lists"
+	revisions     = "note_revisions"
+	sessions      = "sessions"
+	usedTokens    = "used_refresh_tokens"
+	resets        = "password_resets"
+	verifications = "email_verifications"
+	twoFactor     = "two_factor"
+	recoveryCodes = "recovery_codes"
+	challenges    = "two_factor_challenges"
+	accessTokens  = "personal_access_tokens"
+	shares        = "note_shares"
+	workspaces    = "workspaces"
+	members       = "workspace_members"
+	invitations   = "workspace_invitations"
+	notifications = "notifications"
+	comments      = "note_comments"
+	attachments   = "note_attachments"
 )
 
 type DBRepo struct {
@@ -0,0 +1,54 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func createRevisionBuilder(revision entity.Revision) (string, []interface{}, error) {
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	snapshot, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return "", nil, err
+	}
+
+	builder := sq.Insert(revisions).
+		Columns("note_id", "user_id", "action", "changes", "snapshot").
+		Values(revision.NoteId, revision.UserId, revision.Action, string(changes), string(snapshot)).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateRevision(ctx context.Context, revision entity.Revision) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createRevisionBuilder(revision)
+	if err != nil {
+		return 0, err
+	}
+
+	var revisionId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&revisionId)
+	if err != nil {
+		return 0, err
+	}
+
+	return revisionId, tx.Commit()
+}
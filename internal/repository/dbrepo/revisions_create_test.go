@@ -0,0 +1,97 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		revision entity.Revision
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "INSERT INTO note_revisions (note_id,user_id,action,changes,snapshot) VALUES ($1,$2,$3,$4,$5) RETURNING id"
+	revision := entity.Revision{
+		NoteId:  1,
+		UserId:  1,
+		Action:  entity.RevisionUpdate,
+		Changes: map[string]entity.Change{"status": {Old: entity.StatusNotDone, New: entity.StatusDone}},
+		Snapshot: entity.Note{
+			ID:     1,
+			UserId: 1,
+			Title:  "title",
+			Status: entity.StatusDone,
+		},
+	}
+	changes := `{"status":{"old":"not_done","new":"done"}}`
+	snapshot := `{"id":1,"user_id":1,"title":"title","date":"0001-01-01T00:00:00Z","status":"done","priority":""}`
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		id           int
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.revision.NoteId, args.revision.UserId, args.revision.Action, changes, snapshot).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			args: args{revision: revision},
+			id:   1,
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.revision.NoteId, args.revision.UserId, args.revision.Action, changes, snapshot).
+					WillReturnError(errors.New("new error"))
+
+				mock.ExpectRollback()
+			},
+			args:    args{revision: revision},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			got, err := r.CreateRevision(context.Background(), tt.args.revision)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+var revisionColumns = []string{"id", "note_id", "user_id", "action", "changes", "snapshot", "created_at"}
+
+func scanRevision(row rowScanner) (entity.Revision, error) {
+	var (
+		revision          entity.Revision
+		changes, snapshot []byte
+	)
+
+	err := row.Scan(&revision.ID, &revision.NoteId, &revision.UserId, &revision.Action, &changes, &snapshot, &revision.CreatedAt)
+	if err != nil {
+		return entity.Revision{}, err
+	}
+
+	if err = json.Unmarshal(changes, &revision.Changes); err != nil {
+		return entity.Revision{}, err
+	}
+
+	if err = json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+		return entity.Revision{}, err
+	}
+
+	return revision, nil
+}
+
+/*-----------------------------
+					GET REVISION
+ ----------------------------- */
+
+func getRevisionBuilder(id, noteId int) (string, []interface{}, error) {
+	builder := sq.Select(revisionColumns...).
+		From(revisions).
+		Where(sq.Eq{"note_id": noteId}).
+		PlaceholderFormat(sq.Dollar)
+
+	if id != 0 {
+		builder = builder.Where(sq.Eq{"id": id})
+	} else {
+		builder = builder.OrderBy("id DESC")
+	}
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetRevisionById(ctx context.Context, id, noteId int) (entity.Revision, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Revision{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getRevisionBuilder(id, noteId)
+	if err != nil {
+		return entity.Revision{}, err
+	}
+
+	revision, err := scanRevision(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return entity.Revision{}, err
+	}
+
+	return revision, tx.Commit()
+}
+
+/*-----------------------------
+					GET NOTE HISTORY
+ ----------------------------- */
+
+// GetRevisions returns revisions of the note, the latest first.
+func (r *DBRepo) GetRevisions(ctx context.Context, noteId int) ([]entity.Revision, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getRevisionBuilder(0, noteId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Revision
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, revision)
+	}
+
+	return result, tx.Commit()
+}
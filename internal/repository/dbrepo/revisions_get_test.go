@@ -0,0 +1,130 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRevisionById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		noteId int
+	}
+
+	type mockBehavior func(args args)
+
+	createdAt := time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)
+	revision := entity.Revision{
+		ID:        2,
+		NoteId:    1,
+		UserId:    1,
+		Action:    entity.RevisionUpdate,
+		Changes:   map[string]entity.Change{"title": {Old: "old", New: "new"}},
+		Snapshot:  entity.Note{ID: 1, UserId: 1, Title: "new", Date: createdAt, Status: entity.StatusNotDone, Priority: entity.PriorityMedium},
+		CreatedAt: createdAt,
+	}
+	expectedQuery := "SELECT id, note_id, user_id, action, changes, snapshot, created_at FROM note_revisions WHERE note_id = $1 AND id = $2"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantRevision entity.Revision
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(revisionColumns).
+					AddRow(revision.ID, revision.NoteId, revision.UserId, revision.Action,
+						[]byte(`{"title":{"old":"old","new":"new"}}`),
+						[]byte(`{"id":1,"user_id":1,"title":"new","date":"2024-02-15T12:00:00Z","status":"not_done","priority":"medium"}`),
+						revision.CreatedAt)
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.noteId, args.id).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			args:         args{id: revision.ID, noteId: revision.NoteId},
+			wantRevision: revision,
+		},
+		{
+			name: "Failed_NotFound",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.noteId, args.id).WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			args:         args{id: 100, noteId: revision.NoteId},
+			wantRevision: entity.Revision{},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			got, err := r.GetRevisionById(context.Background(), tt.args.id, tt.args.noteId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRevision, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	noteId := 1
+	createdAt := time.Date(2024, 2, 15, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+
+	rows := sqlmock.NewRows(revisionColumns).
+		AddRow(2, noteId, 1, entity.RevisionUpdate, []byte(`{"status":{"old":"not_done","new":"done"}}`), []byte(`{"id":1}`), createdAt).
+		AddRow(1, noteId, 1, entity.RevisionCreate, []byte(`{"title":{"old":"","new":"title"}}`), []byte(`{"id":1}`), createdAt)
+
+	expectedQuery := "SELECT id, note_id, user_id, action, changes, snapshot, created_at FROM note_revisions WHERE note_id = $1 ORDER BY id DESC"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(noteId).WillReturnRows(rows)
+
+	mock.ExpectCommit()
+
+	got, err := r.GetRevisions(context.Background(), noteId)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, entity.RevisionUpdate, got[0].Action)
+	assert.Equal(t, entity.Change{Old: entity.StatusNotDone, New: entity.StatusDone}, got[0].Changes["status"])
+	assert.Equal(t, 1, got[1].Snapshot.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,43 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func createSessionBuilder(session entity.Session) (string, []interface{}, error) {
+	builder := sq.Insert(sessions).
+		Columns("user_id", "refresh_token", "device", "user_agent", "ip", "expires_at").
+		Values(session.UserId, session.RefreshToken, session.Client.Device, session.Client.UserAgent, session.Client.IP, session.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateSession(ctx context.Context, session entity.Session) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createSessionBuilder(session)
+	if err != nil {
+		return 0, err
+	}
+
+	var sessionId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&sessionId)
+	if err != nil {
+		return 0, err
+	}
+
+	return sessionId, tx.Commit()
+}
@@ -0,0 +1,90 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		session entity.Session
+	}
+
+	type mockBehavior func(args args)
+
+	expectedQuery := "INSERT INTO sessions (user_id,refresh_token,device,user_agent,ip,expires_at) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id"
+	session := entity.Session{
+		UserId:       1,
+		RefreshToken: "refresh_token",
+		Client:       entity.Client{Device: "Pixel 8", UserAgent: "curl/8.0", IP: "127.0.0.1"},
+		ExpiresAt:    time.Now().Add(time.Hour).Round(time.Second),
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		id           int
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(args.session.UserId, args.session.RefreshToken, args.session.Client.Device,
+						args.session.Client.UserAgent, args.session.Client.IP, args.session.ExpiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+				mock.ExpectCommit()
+			},
+			args: args{session: session},
+			id:   1,
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WillReturnError(errors.New("new error"))
+
+				mock.ExpectRollback()
+			},
+			args:    args{session: session},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			got, err := r.CreateSession(context.Background(), tt.args.session)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func deleteSessionsBuilder(where sq.Sqlizer) (string, []interface{}, error) {
+	builder := sq.Delete(sessions).
+		Where(where).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) deleteSessions(ctx context.Context, where sq.Sqlizer) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteSessionsBuilder(where)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, tx.Commit()
+}
+
+func (r *DBRepo) DeleteSession(ctx context.Context, id, userId int) error {
+	_, err := r.deleteSessions(ctx, sq.Eq{"id": id, "user_id": userId})
+	return err
+}
+
+// DeleteSessions logs the user out everywhere.
+func (r *DBRepo) DeleteSessions(ctx context.Context, userId int) error {
+	_, err := r.deleteSessions(ctx, sq.Eq{"user_id": userId})
+	return err
+}
+
+// DeleteExpiredSessions deletes sessions of all users which can't be refreshed anymore
+// together with their used refresh tokens, returning the number of deleted sessions.
+func (r *DBRepo) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	return r.deleteSessions(ctx, sq.Expr("expires_at <= NOW()"))
+}
@@ -0,0 +1,116 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		userId int
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectExec := "DELETE FROM sessions WHERE id = $1 AND user_id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
+					WithArgs(args.id, args.userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			args: args{id: 2, userId: 1},
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec("DELETE FROM sessions").WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			args:    args{id: 2, userId: 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.DeleteSession(context.Background(), tt.args.id, tt.args.userId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM sessions WHERE user_id = $1")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.DeleteSessions(context.Background(), 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteExpiredSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM sessions WHERE expires_at <= NOW()")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	deleted, err := r.DeleteExpiredSessions(context.Background())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,109 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+var sessionColumns = []string{"id", "user_id", "refresh_token", "device", "user_agent", "ip", "created_at", "last_used_at", "expires_at"}
+
+func scanSession(row rowScanner, session *entity.Session) error {
+	return row.Scan(&session.ID, &session.UserId, &session.RefreshToken, &session.Client.Device, &session.Client.UserAgent,
+		&session.Client.IP, &session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt)
+}
+
+func getSessionBuilder(where sq.Sqlizer) (string, []interface{}, error) {
+	builder := sq.Select(sessionColumns...).
+		From(sessions).
+		Where(where).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) getSession(ctx context.Context, where sq.Sqlizer) (entity.Session, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Session{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getSessionBuilder(where)
+	if err != nil {
+		return entity.Session{}, err
+	}
+
+	var session entity.Session
+	err = scanSession(tx.QueryRowContext(ctx, query, args...), &session)
+	if err != nil {
+		return entity.Session{}, err
+	}
+
+	return session, tx.Commit()
+}
+
+func (r *DBRepo) GetSessionById(ctx context.Context, id, userId int) (entity.Session, error) {
+	return r.getSession(ctx, sq.Eq{"id": id, "user_id": userId})
+}
+
+// GetSessionByRefreshToken returns the session the refresh token with the hash is current for,
+// expired sessions aren't returned.
+func (r *DBRepo) GetSessionByRefreshToken(ctx context.Context, refreshTokenHash string) (entity.Session, error) {
+	return r.getSession(ctx, sq.And{sq.Eq{"refresh_token": refreshTokenHash}, sq.Expr("expires_at > NOW()")})
+}
+
+// GetSessionByUsedRefreshToken returns the session the refresh token with the hash was rotated out of.
+func (r *DBRepo) GetSessionByUsedRefreshToken(ctx context.Context, refreshTokenHash string) (entity.Session, error) {
+	return r.getSession(ctx, sq.Expr("id IN (SELECT session_id FROM "+usedTokens+" WHERE refresh_token = ?)", refreshTokenHash))
+}
+
+func getSessionsBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Select(sessionColumns...).
+		From(sessions).
+		Where(sq.Eq{"user_id": userId}).
+		Where(sq.Expr("expires_at > NOW()")).
+		OrderBy("last_used_at DESC", "id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetSessions returns active sessions of the user, expired ones aren't returned.
+func (r *DBRepo) GetSessions(ctx context.Context, userId int) ([]entity.Session, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getSessionsBuilder(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Session
+	for rows.Next() {
+		var session entity.Session
+		if err := scanSession(rows, &session); err != nil {
+			return nil, err
+		}
+		result = append(result, session)
+	}
+
+	return result, tx.Commit()
+}
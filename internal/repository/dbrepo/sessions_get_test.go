@@ -0,0 +1,214 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+var sessionRowColumns = []string{"id", "user_id", "refresh_token", "device", "user_agent", "ip", "created_at", "last_used_at", "expires_at"}
+
+func sessionRow(rows *sqlmock.Rows, s entity.Session) *sqlmock.Rows {
+	return rows.AddRow(s.ID, s.UserId, s.RefreshToken, s.Client.Device, s.Client.UserAgent, s.Client.IP, s.CreatedAt, s.LastUsedAt, s.ExpiresAt)
+}
+
+func TestGetSessionByRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type mockBehavior func(refreshToken string)
+
+	now := time.Now().Round(time.Second)
+	session := entity.Session{
+		ID:           1,
+		UserId:       1,
+		RefreshToken: "refresh_token",
+		Client:       entity.Client{Device: "Pixel 8", UserAgent: "curl/8.0", IP: "127.0.0.1"},
+		CreatedAt:    now,
+		LastUsedAt:   now,
+		ExpiresAt:    now.Add(time.Hour),
+	}
+
+	expectedQuery := "SELECT id, user_id, refresh_token, device, user_agent, ip, created_at, last_used_at, expires_at FROM sessions WHERE (refresh_token = $1 AND expires_at > NOW())"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		refreshToken string
+		wantSession  entity.Session
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(refreshToken string) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(refreshToken).
+					WillReturnRows(sessionRow(sqlmock.NewRows(sessionRowColumns), session))
+
+				mock.ExpectCommit()
+			},
+			refreshToken: session.RefreshToken,
+			wantSession:  session,
+		},
+		{
+			name: "NotFound",
+			mockBehavior: func(refreshToken string) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(refreshToken).
+					WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			refreshToken: "unknown",
+			wantErr:      sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.refreshToken)
+
+			got, err := r.GetSessionByRefreshToken(context.Background(), tt.refreshToken)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantSession, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetSessionByUsedRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now().Round(time.Second)
+	session := entity.Session{ID: 2, UserId: 1, RefreshToken: "current", CreatedAt: now, LastUsedAt: now, ExpiresAt: now}
+
+	mock.ExpectBegin()
+	expectedQuery := "SELECT id, user_id, refresh_token, device, user_agent, ip, created_at, last_used_at, expires_at FROM sessions WHERE id IN (SELECT session_id FROM used_refresh_tokens WHERE refresh_token = $1)"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs("used").
+		WillReturnRows(sessionRow(sqlmock.NewRows(sessionRowColumns), session))
+	mock.ExpectCommit()
+
+	got, err := r.GetSessionByUsedRefreshToken(context.Background(), "used")
+	assert.NoError(t, err)
+	assert.Equal(t, session, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSessionById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now().Round(time.Second)
+	session := entity.Session{ID: 2, UserId: 1, RefreshToken: "refresh_token", CreatedAt: now, LastUsedAt: now, ExpiresAt: now}
+
+	mock.ExpectBegin()
+	expectedQuery := "SELECT id, user_id, refresh_token, device, user_agent, ip, created_at, last_used_at, expires_at FROM sessions WHERE id = $1 AND user_id = $2"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(session.ID, session.UserId).
+		WillReturnRows(sessionRow(sqlmock.NewRows(sessionRowColumns), session))
+	mock.ExpectCommit()
+
+	got, err := r.GetSessionById(context.Background(), session.ID, session.UserId)
+	assert.NoError(t, err)
+	assert.Equal(t, session, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSessions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now().Round(time.Second)
+	sessions := []entity.Session{
+		{ID: 2, UserId: 1, RefreshToken: "phone", Client: entity.Client{Device: "Pixel 8"}, CreatedAt: now, LastUsedAt: now, ExpiresAt: now},
+		{ID: 1, UserId: 1, RefreshToken: "laptop", Client: entity.Client{Device: "MacBook"}, CreatedAt: now, LastUsedAt: now.Add(-time.Hour), ExpiresAt: now},
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func()
+		wantSessions []entity.Session
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows(sessionRowColumns)
+				for _, s := range sessions {
+					rows = sessionRow(rows, s)
+				}
+
+				expectedQuery := "SELECT id, user_id, refresh_token, device, user_agent, ip, created_at, last_used_at, expires_at FROM sessions WHERE user_id = $1 AND expires_at > NOW() ORDER BY last_used_at DESC, id DESC"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(1).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			wantSessions: sessions,
+		},
+		{
+			name: "Failed",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT (.+) FROM sessions").WillReturnError(sql.ErrConnDone)
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior()
+
+			got, err := r.GetSessions(context.Background(), 1)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantSessions, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func rotateSessionBuilder(session entity.Session, usedTokenHash string) (string, []interface{}, error) {
+	builder := sq.Update(sessions).
+		Set("refresh_token", session.RefreshToken).
+		Set("user_agent", session.Client.UserAgent).
+		Set("ip", session.Client.IP).
+		Set("last_used_at", sq.Expr("NOW()")).
+		Set("expires_at", session.ExpiresAt).
+		Where(sq.Eq{"id": session.ID, "refresh_token": usedTokenHash}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func createUsedTokenBuilder(sessionId int, usedTokenHash string) (string, []interface{}, error) {
+	builder := sq.Insert(usedTokens).
+		Columns("refresh_token", "session_id").
+		Values(usedTokenHash, sessionId).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// RotateSession replaces the hash of the used refresh token of the session with the one of a new token and
+// records the client it was used from. The used hash is kept to detect reuse of the token, sql.ErrNoRows
+// is returned when the token has already been rotated.
+func (r *DBRepo) RotateSession(ctx context.Context, session entity.Session, usedTokenHash string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := rotateSessionBuilder(session, usedTokenHash)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rotated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rotated == 0 {
+		return sql.ErrNoRows
+	}
+
+	query, args, err = createUsedTokenBuilder(session.ID, usedTokenHash)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
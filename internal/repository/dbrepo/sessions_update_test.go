@@ -0,0 +1,107 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		session   entity.Session
+		usedToken string
+	}
+
+	type mockBehavior func(args args)
+
+	expectExec := "UPDATE sessions SET refresh_token = $1, user_agent = $2, ip = $3, last_used_at = NOW(), expires_at = $4 WHERE id = $5 AND refresh_token = $6"
+	expectInsert := "INSERT INTO used_refresh_tokens (refresh_token,session_id) VALUES ($1,$2)"
+	session := entity.Session{
+		ID:           1,
+		RefreshToken: "refresh_token_test",
+		Client:       entity.Client{UserAgent: "curl/8.0", IP: "127.0.0.1"},
+		ExpiresAt:    time.Now().Round(time.Second),
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
+					WithArgs(args.session.RefreshToken, args.session.Client.UserAgent, args.session.Client.IP, args.session.ExpiresAt, args.session.ID, args.usedToken).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec(regexp.QuoteMeta(expectInsert)).
+					WithArgs(args.usedToken, args.session.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			args: args{session: session, usedToken: "used_token"},
+		},
+		{
+			name: "AlreadyRotated",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
+					WithArgs(args.session.RefreshToken, args.session.Client.UserAgent, args.session.Client.IP, args.session.ExpiresAt, args.session.ID, args.usedToken).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectRollback()
+			},
+			args:    args{session: session, usedToken: "used_token"},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			args:    args{session: session, usedToken: "used_token"},
+			wantErr: errors.New("test error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.RotateSession(context.Background(), tt.args.session, tt.args.usedToken)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
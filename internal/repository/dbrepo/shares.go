@@ -0,0 +1,225 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// qualify prefixes columns with the alias of their table for joins.
+func qualify(alias string, columns []string) []string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		result[i] = alias + "." + column
+	}
+	return result
+}
+
+func shareNoteBuilder(share entity.NoteShare) (string, []interface{}, error) {
+	builder := sq.Insert(shares).
+		Columns("note_id", "user_id", "permission").
+		Values(share.NoteId, share.UserId, share.Permission).
+		Suffix("ON CONFLICT (note_id, user_id) DO UPDATE SET permission = EXCLUDED.permission").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// ShareNote grants the user the permission on the note, the permission of an existing share is replaced.
+func (r *DBRepo) ShareNote(ctx context.Context, share entity.NoteShare) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := shareNoteBuilder(share)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func unshareNoteBuilder(noteId, userId int) (string, []interface{}, error) {
+	builder := sq.Delete(shares).
+		Where(sq.Eq{"note_id": noteId, "user_id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// UnshareNote revokes access of the user to the note, the note assigned to the user is unassigned.
+func (r *DBRepo) UnshareNote(ctx context.Context, noteId, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := unshareNoteBuilder(noteId, userId)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	query, args, err = unassignBuilder(sq.Eq{"id": noteId, "assignee_id": userId})
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func getNoteShareBuilder(noteId, userId int) (string, []interface{}, error) {
+	builder := sq.Select("s.note_id", "s.user_id", "s.permission", "s.created_at", "n.user_id").
+		From(shares + " s").
+		Join(notes + " n ON n.id = s.note_id").
+		Where(sq.Eq{"s.note_id": noteId, "s.user_id": userId, "n.deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetNoteShare returns the share of the note with the user along with the owner of the note,
+// shares of trashed notes aren't returned.
+func (r *DBRepo) GetNoteShare(ctx context.Context, noteId, userId int) (entity.NoteShare, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.NoteShare{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getNoteShareBuilder(noteId, userId)
+	if err != nil {
+		return entity.NoteShare{}, err
+	}
+
+	var share entity.NoteShare
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&share.NoteId, &share.UserId, &share.Permission, &share.CreatedAt, &share.OwnerId)
+	if err != nil {
+		return entity.NoteShare{}, err
+	}
+
+	return share, tx.Commit()
+}
+
+func getNoteSharesBuilder(noteId int) (string, []interface{}, error) {
+	builder := sq.Select("s.note_id", "s.user_id", "u.login", "s.permission", "s.created_at").
+		From(shares+" s").
+		Join(users+" u ON u.id = s.user_id").
+		Where(sq.Eq{"s.note_id": noteId}).
+		OrderBy("s.created_at", "s.user_id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetNoteShares returns collaborators of the note with their logins.
+func (r *DBRepo) GetNoteShares(ctx context.Context, noteId int) ([]entity.NoteShare, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getNoteSharesBuilder(noteId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.NoteShare
+	for rows.Next() {
+		var share entity.NoteShare
+		if err := rows.Scan(&share.NoteId, &share.UserId, &share.Login, &share.Permission, &share.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, share)
+	}
+
+	return result, tx.Commit()
+}
+
+func getSharedNotesBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Select(qualify("n", noteColumns)...).
+		Column("s.permission").
+		From(notes+" n").
+		Join(shares+" s ON s.note_id = n.id").
+		Where(sq.Eq{"s.user_id": userId, "n.deleted_at": nil}).
+		OrderBy("s.created_at DESC", "n.id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetSharedNotes returns notes of other users shared with the user, the latest shared first.
+func (r *DBRepo) GetSharedNotes(ctx context.Context, userId int) ([]entity.SharedNote, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getSharedNotesBuilder(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.SharedNote
+	for rows.Next() {
+		var note entity.SharedNote
+		if err := scanNote(rows, &note.Note, &note.Permission); err != nil {
+			return nil, err
+		}
+		result = append(result, note)
+	}
+
+	return result, tx.Commit()
+}
@@ -0,0 +1,163 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareNote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	share := entity.NoteShare{NoteId: 1, UserId: 2, Permission: entity.PermissionEditor}
+
+	mock.ExpectBegin()
+	expectedQuery := "INSERT INTO note_shares (note_id,user_id,permission) VALUES ($1,$2,$3) " +
+		"ON CONFLICT (note_id, user_id) DO UPDATE SET permission = EXCLUDED.permission"
+	mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(share.NoteId, share.UserId, share.Permission).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.ShareNote(context.Background(), share))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnshareNote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM note_shares WHERE note_id = $1 AND user_id = $2")).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE notes SET assignee_id = $1 WHERE assignee_id = $2 AND id = $3")).
+		WithArgs(nil, 2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.UnshareNote(context.Background(), 1, 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNoteShare(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type mockBehavior func(noteId, userId int)
+
+	expectedQuery := "SELECT s.note_id, s.user_id, s.permission, s.created_at, n.user_id FROM note_shares s " +
+		"JOIN notes n ON n.id = s.note_id WHERE n.deleted_at IS NULL AND s.note_id = $1 AND s.user_id = $2"
+
+	now := time.Now().Round(time.Second)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		want         entity.NoteShare
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(noteId, userId int) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(noteId, userId).
+					WillReturnRows(sqlmock.NewRows([]string{"note_id", "user_id", "permission", "created_at", "user_id"}).
+						AddRow(noteId, userId, entity.PermissionViewer, now, 1))
+
+				mock.ExpectCommit()
+			},
+			want: entity.NoteShare{NoteId: 3, UserId: 2, OwnerId: 1, Permission: entity.PermissionViewer, CreatedAt: now},
+		},
+		{
+			name: "NotShared",
+			mockBehavior: func(noteId, userId int) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(noteId, userId).
+					WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(3, 2)
+
+			got, err := r.GetNoteShare(context.Background(), 3, 2)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetSharedNotes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	date := time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	expectedQuery := "SELECT n.id, n.user_id, n.title, n.description, n.date, n.status, n.priority, n.due_at, n.recurrence, " +
+		"n.list_id, n.deleted_at, n.assignee_id, s.permission FROM notes n JOIN note_shares s ON s.note_id = n.id " +
+		"WHERE n.deleted_at IS NULL AND s.user_id = $1 ORDER BY s.created_at DESC, n.id DESC"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(append(noteColumns, "permission")).
+			AddRow(3, 1, "Plan", "", date, entity.StatusNotDone, entity.PriorityLow, nil, nil, nil, nil, nil, entity.PermissionEditor))
+	mock.ExpectCommit()
+
+	got, err := r.GetSharedNotes(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.SharedNote{{
+		Note: entity.Note{
+			ID:       3,
+			UserId:   1,
+			Title:    "Plan",
+			Date:     date,
+			Status:   entity.StatusNotDone,
+			Priority: entity.PriorityLow,
+		},
+		Permission: entity.PermissionEditor,
+	}}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
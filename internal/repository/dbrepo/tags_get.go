@@ -0,0 +1,174 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+/*-----------------------------
+					GET TAG
+ ----------------------------- */
+
+func getTagBuilder(data any, userId int) (string, []interface{}, error) {
+	builder := sq.Select("t.id", "t.user_id", "t.name", "COUNT(n.id)").
+		From(tags + " t").
+		LeftJoin(noteTags + " nt ON nt.tag_id = t.id").
+		LeftJoin(notes + " n ON n.id = nt.note_id AND n.deleted_at IS NULL").
+		Where(sq.Eq{"t.user_id": userId}).
+		GroupBy("t.id").
+		PlaceholderFormat(sq.Dollar)
+
+	switch data.(type) {
+	case int:
+		builder = builder.Where(sq.Eq{"t.id": data})
+	case string:
+		builder = builder.Where(sq.Eq{"t.name": data})
+	default:
+		builder = builder.OrderBy("t.name ASC")
+	}
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetTagById(ctx context.Context, id, userId int) (entity.Tag, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Tag{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getTagBuilder(id, userId)
+	if err != nil {
+		return entity.Tag{}, err
+	}
+
+	var tag entity.Tag
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&tag.ID, &tag.UserId, &tag.Name, &tag.NotesCount)
+	if err != nil {
+		return entity.Tag{}, err
+	}
+
+	return tag, tx.Commit()
+}
+
+func (r *DBRepo) GetTagByName(ctx context.Context, name string, userId int) (entity.Tag, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Tag{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getTagBuilder(name, userId)
+	if err != nil {
+		return entity.Tag{}, err
+	}
+
+	var tag entity.Tag
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&tag.ID, &tag.UserId, &tag.Name, &tag.NotesCount)
+	if err != nil {
+		return entity.Tag{}, err
+	}
+
+	return tag, tx.Commit()
+}
+
+/*-----------------------------
+					GET ALL TAGS
+ ----------------------------- */
+
+func (r *DBRepo) GetTags(ctx context.Context, userId int) ([]entity.Tag, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getTagBuilder(nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []entity.Tag
+	for rows.Next() {
+		var tag entity.Tag
+		if err := rows.Scan(&tag.ID, &tag.UserId, &tag.Name, &tag.NotesCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, tx.Commit()
+}
+
+/*-----------------------------
+					GET NOTES TAGS
+ ----------------------------- */
+
+func getNotesTagsBuilder(noteIds []int) (string, []interface{}, error) {
+	builder := sq.Select("nt.note_id", "t.name").
+		From(noteTags+" nt").
+		Join(tags+" t ON t.id = nt.tag_id").
+		Where(sq.Eq{"nt.note_id": noteIds}).
+		OrderBy("nt.note_id ASC", "t.name ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetNotesTags(ctx context.Context, noteIds []int) (map[int][]string, error) {
+	result := make(map[int][]string, len(noteIds))
+	if len(noteIds) == 0 {
+		return result, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getNotesTagsBuilder(noteIds)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			noteId int
+			name   string
+		)
+		if err := rows.Scan(&noteId, &name); err != nil {
+			return nil, err
+		}
+		result[noteId] = append(result[noteId], name)
+	}
+
+	return result, tx.Commit()
+}
@@ -0,0 +1,145 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTagById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id     int
+		userId int
+	}
+
+	type mockBehavior func(args args)
+
+	tag := entity.Tag{ID: 1, UserId: 1, Name: "work", NotesCount: 3}
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantTag      entity.Tag
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "user_id", "name", "count"}).
+					AddRow(tag.ID, tag.UserId, tag.Name, tag.NotesCount)
+
+				expectedQuery := "SELECT t.id, t.user_id, t.name, COUNT(n.id) FROM tags t LEFT JOIN note_tags nt ON nt.tag_id = t.id LEFT JOIN notes n ON n.id = nt.note_id AND n.deleted_at IS NULL WHERE t.user_id = $1 AND t.id = $2 GROUP BY t.id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnRows(rows)
+
+				mock.ExpectCommit()
+			},
+			args:    args{id: tag.ID, userId: tag.UserId},
+			wantTag: tag,
+		},
+		{
+			name: "Failed_NotFound",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectedQuery := "SELECT t.id, t.user_id, t.name, COUNT(n.id) FROM tags t LEFT JOIN note_tags nt ON nt.tag_id = t.id LEFT JOIN notes n ON n.id = nt.note_id AND n.deleted_at IS NULL WHERE t.user_id = $1 AND t.id = $2 GROUP BY t.id"
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(args.userId, args.id).WillReturnError(errors.New("failed test"))
+
+				mock.ExpectRollback()
+			},
+			args:    args{id: 100, userId: tag.UserId},
+			wantTag: entity.Tag{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			got, err := r.GetTagById(context.Background(), tt.args.id, tt.args.userId)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantTag, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	userId := 1
+	tags := []entity.Tag{
+		{ID: 2, UserId: userId, Name: "home", NotesCount: 0},
+		{ID: 1, UserId: userId, Name: "work", NotesCount: 3},
+	}
+
+	mock.ExpectBegin()
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "name", "count"}).
+		AddRow(tags[0].ID, tags[0].UserId, tags[0].Name, tags[0].NotesCount).
+		AddRow(tags[1].ID, tags[1].UserId, tags[1].Name, tags[1].NotesCount)
+
+	expectedQuery := "SELECT t.id, t.user_id, t.name, COUNT(n.id) FROM tags t LEFT JOIN note_tags nt ON nt.tag_id = t.id LEFT JOIN notes n ON n.id = nt.note_id AND n.deleted_at IS NULL WHERE t.user_id = $1 GROUP BY t.id ORDER BY t.name ASC"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(userId).WillReturnRows(rows)
+
+	mock.ExpectCommit()
+
+	got, err := r.GetTags(context.Background(), userId)
+	assert.NoError(t, err)
+	assert.Equal(t, tags, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNotesTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+
+	rows := sqlmock.NewRows([]string{"note_id", "name"}).
+		AddRow(1, "home").
+		AddRow(1, "work").
+		AddRow(3, "work")
+
+	expectedQuery := "SELECT nt.note_id, t.name FROM note_tags nt JOIN tags t ON t.id = nt.tag_id WHERE nt.note_id IN ($1,$2,$3) ORDER BY nt.note_id ASC, t.name ASC"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).WithArgs(1, 2, 3).WillReturnRows(rows)
+
+	mock.ExpectCommit()
+
+	got, err := r.GetNotesTags(context.Background(), []int{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int][]string{1: {"home", "work"}, 3: {"work"}}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,77 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func insertTagsBuilder(userId int, names []string) (string, []interface{}, error) {
+	builder := sq.Insert(tags).
+		Columns("user_id", "name").
+		Suffix("ON CONFLICT (user_id, name) DO NOTHING").
+		PlaceholderFormat(sq.Dollar)
+
+	for _, name := range names {
+		builder = builder.Values(userId, name)
+	}
+
+	return builder.ToSql()
+}
+
+func deleteNoteTagsBuilder(noteId int) (string, []interface{}, error) {
+	builder := sq.Delete(noteTags).
+		Where(sq.Eq{"note_id": noteId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func linkNoteTagsBuilder(noteId, userId int, names []string) (string, []interface{}, error) {
+	selectTags := sq.Select().
+		Column(sq.Expr("?", noteId)).
+		Column("id").
+		From(tags).
+		Where(sq.Eq{"user_id": userId, "name": names})
+
+	builder := sq.Insert(noteTags).
+		Columns("note_id", "tag_id").
+		Select(selectTags).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// setNoteTags replaces the tags of the note inside tx, creating missing tags for the user.
+func setNoteTags(ctx context.Context, tx *sql.Tx, noteId, userId int, names []string) error {
+	query, args, err := deleteNoteTagsBuilder(noteId)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	query, args, err = insertTagsBuilder(userId, names)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = linkNoteTagsBuilder(noteId, userId, names)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	return err
+}
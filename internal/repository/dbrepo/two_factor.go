@@ -0,0 +1,330 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func getTwoFactorBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Select("user_id", "secret", "last_step", "created_at", "enabled_at", "locked_until").
+		From(twoFactor).
+		Where(sq.Eq{"user_id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) GetTwoFactor(ctx context.Context, userId int) (entity.TwoFactor, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.TwoFactor{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getTwoFactorBuilder(userId)
+	if err != nil {
+		return entity.TwoFactor{}, err
+	}
+
+	var tf entity.TwoFactor
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&tf.UserId, &tf.Secret, &tf.LastStep, &tf.CreatedAt, &tf.EnabledAt, &tf.LockedUntil)
+	if err != nil {
+		return entity.TwoFactor{}, err
+	}
+
+	return tf, tx.Commit()
+}
+
+func setTwoFactorSecretBuilder(userId int, secret string) (string, []interface{}, error) {
+	builder := sq.Insert(twoFactor).
+		Columns("user_id", "secret").
+		Values(userId, secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_step = 0, created_at = NOW() " +
+			"WHERE " + twoFactor + ".enabled_at IS NULL").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// SetTwoFactorSecret starts enrollment with the secret replacing a pending one, an enabled secret isn't replaced.
+func (r *DBRepo) SetTwoFactorSecret(ctx context.Context, userId int, secret string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := setTwoFactorSecretBuilder(userId, secret)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func enableTwoFactorBuilder(userId int, step int64) (string, []interface{}, error) {
+	builder := sq.Update(twoFactor).
+		Set("enabled_at", sq.Expr("NOW()")).
+		Set("last_step", step).
+		Where(sq.Eq{"user_id": userId, "enabled_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func deleteRecoveryCodesBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Delete(recoveryCodes).
+		Where(sq.Eq{"user_id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func createRecoveryCodesBuilder(userId int, codeHashes []string) (string, []interface{}, error) {
+	builder := sq.Insert(recoveryCodes).
+		Columns("user_id", "code_hash").
+		PlaceholderFormat(sq.Dollar)
+
+	for _, hash := range codeHashes {
+		builder = builder.Values(userId, hash)
+	}
+
+	return builder.ToSql()
+}
+
+// EnableTwoFactor enables the pending secret of the user confirmed by the code of the step
+// and replaces recovery codes. sql.ErrNoRows is returned when there is no pending secret.
+func (r *DBRepo) EnableTwoFactor(ctx context.Context, userId int, step int64, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := enableTwoFactorBuilder(userId, step)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if enabled, err := res.RowsAffected(); err != nil {
+		return err
+	} else if enabled == 0 {
+		return sql.ErrNoRows
+	}
+
+	query, args, err = deleteRecoveryCodesBuilder(userId)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = createRecoveryCodesBuilder(userId, codeHashes)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setTwoFactorStepBuilder(userId int, step int64) (string, []interface{}, error) {
+	builder := sq.Update(twoFactor).
+		Set("last_step", step).
+		Set("failed_attempts", 0).
+		Where(sq.Eq{"user_id": userId}).
+		Where(sq.Lt{"last_step": step}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// SetTwoFactorStep records the step of an accepted code resetting the count of wrong codes,
+// sql.ErrNoRows is returned when a code of the step or a later one has already been accepted.
+func (r *DBRepo) SetTwoFactorStep(ctx context.Context, userId int, step int64) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := setTwoFactorStepBuilder(userId, step)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func failTwoFactorBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Update(twoFactor).
+		Set("failed_attempts", sq.Expr("failed_attempts + 1")).
+		Where(sq.Eq{"user_id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func lockTwoFactorBuilder(userId, maxAttempts int, lockedUntil time.Time) (string, []interface{}, error) {
+	builder := sq.Update(twoFactor).
+		Set("failed_attempts", 0).
+		Set("locked_until", lockedUntil).
+		Where(sq.Eq{"user_id": userId}).
+		Where(sq.GtOrEq{"failed_attempts": maxAttempts}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// FailTwoFactor counts a wrong code, codes of the user are locked until lockedUntil once the attempts are exhausted.
+func (r *DBRepo) FailTwoFactor(ctx context.Context, userId, maxAttempts int, lockedUntil time.Time) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := failTwoFactorBuilder(userId)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = lockTwoFactorBuilder(userId, maxAttempts, lockedUntil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func deleteTwoFactorBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Delete(twoFactor).
+		Where(sq.Eq{"user_id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// DeleteTwoFactor disables 2FA of the user along with recovery codes.
+func (r *DBRepo) DeleteTwoFactor(ctx context.Context, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteRecoveryCodesBuilder(userId)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = deleteTwoFactorBuilder(userId)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func useRecoveryCodeBuilder(userId int, codeHash string) (string, []interface{}, error) {
+	builder := sq.Update(recoveryCodes).
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userId, "code_hash": codeHash, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// UseRecoveryCode marks the recovery code with the hash as used, sql.ErrNoRows is returned
+// when the code is unknown or used.
+func (r *DBRepo) UseRecoveryCode(ctx context.Context, userId int, codeHash string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := useRecoveryCodeBuilder(userId, codeHash)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if used, err := res.RowsAffected(); err != nil {
+		return err
+	} else if used == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,158 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func createChallengeBuilder(challenge entity.TwoFactorChallenge) (string, []interface{}, error) {
+	builder := sq.Insert(challenges).
+		Columns("user_id", "token_hash", "device", "expires_at").
+		Values(challenge.UserId, challenge.TokenHash, challenge.Device, challenge.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateChallenge(ctx context.Context, challenge entity.TwoFactorChallenge) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createChallengeBuilder(challenge)
+	if err != nil {
+		return 0, err
+	}
+
+	var challengeId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&challengeId)
+	if err != nil {
+		return 0, err
+	}
+
+	return challengeId, tx.Commit()
+}
+
+func getChallengeBuilder(tokenHash string) (string, []interface{}, error) {
+	builder := sq.Select("id", "user_id", "token_hash", "device", "attempts", "expires_at").
+		From(challenges).
+		Where(sq.Eq{"token_hash": tokenHash}).
+		Where(sq.Expr("expires_at > NOW()")).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetChallenge returns the challenge with the token hash, expired challenges aren't returned.
+func (r *DBRepo) GetChallenge(ctx context.Context, tokenHash string) (entity.TwoFactorChallenge, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.TwoFactorChallenge{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getChallengeBuilder(tokenHash)
+	if err != nil {
+		return entity.TwoFactorChallenge{}, err
+	}
+
+	var c entity.TwoFactorChallenge
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&c.ID, &c.UserId, &c.TokenHash, &c.Device, &c.Attempts, &c.ExpiresAt)
+	if err != nil {
+		return entity.TwoFactorChallenge{}, err
+	}
+
+	return c, tx.Commit()
+}
+
+func failChallengeBuilder(id int) (string, []interface{}, error) {
+	builder := sq.Update(challenges).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func deleteChallengeBuilder(where sq.Sqlizer) (string, []interface{}, error) {
+	builder := sq.Delete(challenges).
+		Where(where).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// FailChallenge counts a wrong code, the challenge is deleted once the attempts are exhausted.
+func (r *DBRepo) FailChallenge(ctx context.Context, id, maxAttempts int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := failChallengeBuilder(id)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = deleteChallengeBuilder(sq.And{sq.Eq{"id": id}, sq.GtOrEq{"attempts": maxAttempts}})
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteChallenge deletes the challenge once it's exchanged for tokens, sql.ErrNoRows is returned
+// when it has already been deleted.
+func (r *DBRepo) DeleteChallenge(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteChallengeBuilder(sq.Eq{"id": id})
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
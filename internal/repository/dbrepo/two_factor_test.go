@@ -0,0 +1,187 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnableTwoFactor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type mockBehavior func(userId int, step int64)
+
+	enableQuery := "UPDATE two_factor SET enabled_at = NOW(), last_step = $1 WHERE enabled_at IS NULL AND user_id = $2"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(userId int, step int64) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(enableQuery)).
+					WithArgs(step, userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recovery_codes WHERE user_id = $1")).
+					WithArgs(userId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO recovery_codes (user_id,code_hash) VALUES ($1,$2),($3,$4)")).
+					WithArgs(userId, "hash1", userId, "hash2").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "NotEnrolled",
+			mockBehavior: func(userId int, step int64) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(enableQuery)).
+					WithArgs(step, userId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 100)
+
+			err := r.EnableTwoFactor(context.Background(), 1, 100, []string{"hash1", "hash2"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSetTwoFactorStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type mockBehavior func(userId int, step int64)
+
+	stepQuery := "UPDATE two_factor SET last_step = $1, failed_attempts = $2 WHERE user_id = $3 AND last_step < $4"
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(userId int, step int64) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(stepQuery)).
+					WithArgs(step, 0, userId, step).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Replay",
+			mockBehavior: func(userId int, step int64) {
+				mock.ExpectBegin()
+
+				mock.ExpectExec(regexp.QuoteMeta(stepQuery)).
+					WithArgs(step, 0, userId, step).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(1, 100)
+
+			err := r.SetTwoFactorStep(context.Background(), 1, 100)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFailChallenge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE two_factor_challenges SET attempts = attempts + 1 WHERE id = $1")).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM two_factor_challenges WHERE (id = $1 AND attempts >= $2)")).
+		WithArgs(2, 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.FailChallenge(context.Background(), 2, 5))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFailTwoFactor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	lockedUntil := time.Date(2024, 5, 1, 12, 15, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE two_factor SET failed_attempts = failed_attempts + 1 WHERE user_id = $1")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE two_factor SET failed_attempts = $1, locked_until = $2 WHERE user_id = $3 AND failed_attempts >= $4")).
+		WithArgs(0, lockedUntil, 1, 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.FailTwoFactor(context.Background(), 1, 5, lockedUntil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,8 +10,8 @@ import (
 
 func createUserBuilder(user entity.User) (string, []interface{}, error) {
 	builder := sq.Insert(users).
-		Columns("email", "login", "password", "register_at").
-		Values(user.Email, user.Login, user.Password, user.RegisteredAt).
+		Columns("email", "login", "password", "register_at", "time_zone").
+		Values(user.Email, user.Login, user.Password, user.RegisteredAt, user.TimeZone).
 		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar)
 
@@ -40,9 +40,9 @@ func TestCreateUser(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectExec := "INSERT INTO users (email,login,password,register_at) VALUES ($1,$2,$3,$4) RETURNING id"
+				expectExec := "INSERT INTO users (email,login,password,register_at,time_zone) VALUES ($1,$2,$3,$4,$5) RETURNING id"
 				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
-					WithArgs(args.user.Email, args.user.Login, args.user.Password, args.user.RegisteredAt).
+					WithArgs(args.user.Email, args.user.Login, args.user.Password, args.user.RegisteredAt, args.user.TimeZone).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 				mock.ExpectCommit()
@@ -62,9 +62,9 @@ func TestCreateUser(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectExec := "INSERT INTO users (email,login,password,register_at) VALUES ($1,$2,$3,$4) RETURNING id"
+				expectExec := "INSERT INTO users (email,login,password,register_at,time_zone) VALUES ($1,$2,$3,$4,$5) RETURNING id"
 				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
-					WithArgs(args.user.Email, args.user.Login, args.user.Password, args.user.RegisteredAt).
+					WithArgs(args.user.Email, args.user.Login, args.user.Password, args.user.RegisteredAt, args.user.TimeZone).
 					WillReturnError(errors.New("empty email"))
 
 				mock.ExpectRollback()
@@ -9,15 +9,19 @@ import (
 )
 
 type getInput struct {
-	id           *int
-	login        *string
-	email        *string
-	password     *string
-	refreshToken *string
+	id    *int
+	login *string
+	email *string
+}
+
+var userColumns = []string{"id", "email", "login", "password", "register_at", "time_zone", "email_verified_at"}
+
+func scanUser(row rowScanner, user *entity.User) error {
+	return row.Scan(&user.ID, &user.Email, &user.Login, &user.Password, &user.RegisteredAt, &user.TimeZone, &user.EmailVerifiedAt)
 }
 
 func getUserBuilder(data getInput) (string, []interface{}, error) {
-	builder := sq.Select("id", "email", "login", "password", "register_at").
+	builder := sq.Select(userColumns...).
 		From(users).
 		PlaceholderFormat(sq.Dollar)
 
@@ -33,14 +37,6 @@ func getUserBuilder(data getInput) (string, []interface{}, error) {
 		builder = builder.Where(sq.Eq{"email": *(data.email)})
 	}
 
-	if data.password != nil {
-		builder = builder.Where(sq.Eq{"password": *(data.password)})
-	}
-
-	if data.refreshToken != nil {
-		builder = builder.Where(sq.Eq{"refresh_token": *(data.refreshToken)})
-	}
-
 	return builder.ToSql()
 }
 
@@ -60,8 +56,7 @@ func (r *DBRepo) GetUserByID(ctx context.Context, id int) (entity.User, error) {
 	}
 
 	var user entity.User
-	err = tx.QueryRowContext(ctx, query, args...).
-		Scan(&user.ID, &user.Email, &user.Login, &user.Password, &user.RegisteredAt)
+	err = scanUser(tx.QueryRowContext(ctx, query, args...), &user)
 	if err != nil {
 		return entity.User{}, err
 	}
@@ -85,8 +80,7 @@ func (r *DBRepo) GetUserByLogin(ctx context.Context, login string) (entity.User,
 	}
 
 	var user entity.User
-	err = tx.QueryRowContext(ctx, query, args...).
-		Scan(&user.ID, &user.Email, &user.Login, &user.Password, &user.RegisteredAt)
+	err = scanUser(tx.QueryRowContext(ctx, query, args...), &user)
 	if err != nil {
 		return entity.User{}, err
 	}
@@ -110,56 +104,7 @@ func (r *DBRepo) GetUserByEmail(ctx context.Context, email string) (entity.User,
 	}
 
 	var user entity.User
-	err = tx.QueryRowContext(ctx, query, args...).
-		Scan(&user.ID, &user.Email, &user.Login, &user.Password, &user.RegisteredAt)
-	if err != nil {
-		return entity.User{}, err
-	}
-
-	return user, tx.Commit()
-}
-
-func (r *DBRepo) GetUserByCredentials(ctx context.Context, login, password string) (entity.User, error) {
-	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
-		Isolation: sql.LevelReadCommitted,
-	})
-	if err != nil {
-		return entity.User{}, err
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	query, args, err := getUserBuilder(getInput{login: &login, password: &password})
-	if err != nil {
-		return entity.User{}, err
-	}
-
-	var user entity.User
-	err = tx.QueryRowContext(ctx, query, args...).
-		Scan(&user.ID, &user.Email, &user.Login, &user.Password, &user.RegisteredAt)
-	if err != nil {
-		return entity.User{}, err
-	}
-
-	return user, tx.Commit()
-}
-
-func (r *DBRepo) GetUserByRefreshToken(ctx context.Context, refreshToken string) (entity.User, error) {
-	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
-		Isolation: sql.LevelReadCommitted,
-	})
-	if err != nil {
-		return entity.User{}, nil
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	query, args, err := getUserBuilder(getInput{refreshToken: &refreshToken})
-	if err != nil {
-		return entity.User{}, err
-	}
-
-	var user entity.User
-	err = tx.QueryRowContext(ctx, query, args...).
-		Scan(&user.ID, &user.Email, &user.Login, &user.Password, &user.RegisteredAt)
+	err = scanUser(tx.QueryRowContext(ctx, query, args...), &user)
 	if err != nil {
 		return entity.User{}, err
 	}
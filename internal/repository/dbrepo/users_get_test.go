@@ -35,6 +35,7 @@ func TestGetUserById(t *testing.T) {
 			Login:        "test",
 			Password:     "hashed",
 			RegisteredAt: time.Time{},
+			TimeZone:     entity.DefaultTimeZone,
 		},
 	}
 
@@ -50,10 +51,10 @@ func TestGetUserById(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "email", "login", "password", "register_at"}).
-					AddRow(users[0].ID, users[0].Email, users[0].Login, users[0].Password, users[0].RegisteredAt)
+				rows := sqlmock.NewRows([]string{"id", "email", "login", "password", "register_at", "time_zone", "email_verified_at"}).
+					AddRow(users[0].ID, users[0].Email, users[0].Login, users[0].Password, users[0].RegisteredAt, users[0].TimeZone, users[0].EmailVerifiedAt)
 
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE id = $1"
+				expectExec := "SELECT id, email, login, password, register_at, time_zone, email_verified_at FROM users WHERE id = $1"
 				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
 					WithArgs(args.id).
 					WillReturnRows(rows)
@@ -70,7 +71,7 @@ func TestGetUserById(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE id = $1"
+				expectExec := "SELECT id, email, login, password, register_at, time_zone, email_verified_at FROM users WHERE id = $1"
 				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
 					WithArgs(args.id).
 					WillReturnError(errors.New("test error"))
@@ -122,6 +123,7 @@ func TestGetUserByLogin(t *testing.T) {
 			Login:        "test",
 			Password:     "hashed",
 			RegisteredAt: time.Time{},
+			TimeZone:     entity.DefaultTimeZone,
 		},
 	}
 
@@ -137,10 +139,10 @@ func TestGetUserByLogin(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "email", "login", "password", "register_at"}).
-					AddRow(users[0].ID, users[0].Email, users[0].Login, users[0].Password, users[0].RegisteredAt)
+				rows := sqlmock.NewRows([]string{"id", "email", "login", "password", "register_at", "time_zone", "email_verified_at"}).
+					AddRow(users[0].ID, users[0].Email, users[0].Login, users[0].Password, users[0].RegisteredAt, users[0].TimeZone, users[0].EmailVerifiedAt)
 
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE login = $1"
+				expectExec := "SELECT id, email, login, password, register_at, time_zone, email_verified_at FROM users WHERE login = $1"
 				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
 					WithArgs(args.login).
 					WillReturnRows(rows)
@@ -157,7 +159,7 @@ func TestGetUserByLogin(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE login = $1"
+				expectExec := "SELECT id, email, login, password, register_at, time_zone, email_verified_at FROM users WHERE login = $1"
 				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
 					WithArgs(args.login).
 					WillReturnError(errors.New("test error"))
@@ -209,6 +211,7 @@ func TestGetUserByEmail(t *testing.T) {
 			Login:        "test",
 			Password:     "hashed",
 			RegisteredAt: time.Time{},
+			TimeZone:     entity.DefaultTimeZone,
 		},
 	}
 
@@ -224,10 +227,10 @@ func TestGetUserByEmail(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				rows := sqlmock.NewRows([]string{"id", "email", "login", "password", "register_at"}).
-					AddRow(users[0].ID, users[0].Email, users[0].Login, users[0].Password, users[0].RegisteredAt)
+				rows := sqlmock.NewRows([]string{"id", "email", "login", "password", "register_at", "time_zone", "email_verified_at"}).
+					AddRow(users[0].ID, users[0].Email, users[0].Login, users[0].Password, users[0].RegisteredAt, users[0].TimeZone, users[0].EmailVerifiedAt)
 
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE email = $1"
+				expectExec := "SELECT id, email, login, password, register_at, time_zone, email_verified_at FROM users WHERE email = $1"
 				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
 					WithArgs(args.email).
 					WillReturnRows(rows)
@@ -244,7 +247,7 @@ func TestGetUserByEmail(t *testing.T) {
 			mockBehavior: func(args args) {
 				mock.ExpectBegin()
 
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE email = $1"
+				expectExec := "SELECT id, email, login, password, register_at, time_zone, email_verified_at FROM users WHERE email = $1"
 				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
 					WithArgs(args.email).
 					WillReturnError(errors.New("test error"))
@@ -273,179 +276,3 @@ func TestGetUserByEmail(t *testing.T) {
 		})
 	}
 }
-
-func TestGetUserByCredentials(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer db.Close()
-
-	r := New(db)
-
-	type args struct {
-		login    string
-		password string
-	}
-
-	type mockBehavior func(args args)
-
-	users := []entity.User{
-		{
-			ID:           1,
-			Email:        "test@mail.ru",
-			Login:        "test",
-			Password:     "hashed",
-			RegisteredAt: time.Time{},
-		},
-	}
-
-	tests := []struct {
-		name         string
-		mockBehavior mockBehavior
-		args         args
-		wantUser     entity.User
-		wantErr      bool
-	}{
-		{
-			name: "Success",
-			mockBehavior: func(args args) {
-				mock.ExpectBegin()
-
-				rows := sqlmock.NewRows([]string{"id", "email", "login", "password", "register_at"}).
-					AddRow(users[0].ID, users[0].Email, users[0].Login, users[0].Password, users[0].RegisteredAt)
-
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE login = $1 AND password = $2"
-				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
-					WithArgs(args.login, args.password).
-					WillReturnRows(rows)
-
-				mock.ExpectCommit()
-			},
-			args: args{
-				login:    "test",
-				password: "hashed",
-			},
-			wantUser: users[0],
-		},
-		{
-			name: "Failed",
-			mockBehavior: func(args args) {
-				mock.ExpectBegin()
-
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE email = $1 AND password = $2"
-				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
-					WithArgs(args.login, args.password).
-					WillReturnError(errors.New("test error"))
-
-				mock.ExpectRollback()
-			},
-			args: args{
-				login: "failed",
-			},
-			wantErr: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockBehavior(tt.args)
-
-			got, err := r.GetUserByCredentials(context.Background(), tt.args.login, tt.args.password)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
-			assert.Equal(t, tt.wantUser, got)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
-}
-
-func TestGetUserByRefreshToken(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer db.Close()
-
-	r := New(db)
-
-	type args struct {
-		refreshToken string
-	}
-
-	type mockBehavior func(args args)
-
-	users := []entity.User{
-		{
-			ID:           1,
-			Email:        "test@mail.ru",
-			Login:        "test",
-			Password:     "hashed",
-			RegisteredAt: time.Time{},
-		},
-	}
-
-	tests := []struct {
-		name         string
-		mockBehavior mockBehavior
-		args         args
-		wantUser     entity.User
-		wantErr      bool
-	}{
-		{
-			name: "Success",
-			mockBehavior: func(args args) {
-				mock.ExpectBegin()
-
-				rows := sqlmock.NewRows([]string{"id", "email", "login", "password", "register_at"}).
-					AddRow(users[0].ID, users[0].Email, users[0].Login, users[0].Password, users[0].RegisteredAt)
-
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE refresh_token = $1"
-				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
-					WithArgs(args.refreshToken).
-					WillReturnRows(rows)
-
-				mock.ExpectCommit()
-			},
-			args: args{
-				refreshToken: "test_refresh_token",
-			},
-			wantUser: users[0],
-		},
-		{
-			name: "Failed",
-			mockBehavior: func(args args) {
-				mock.ExpectBegin()
-
-				expectExec := "SELECT id, email, login, password, register_at FROM users WHERE refresh_token = $1"
-				mock.ExpectQuery(regexp.QuoteMeta(expectExec)).
-					WithArgs(args.refreshToken).
-					WillReturnError(errors.New("test error"))
-
-				mock.ExpectRollback()
-			},
-			args: args{
-				refreshToken: "",
-			},
-			wantErr: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockBehavior(tt.args)
-
-			got, err := r.GetUserByRefreshToken(context.Background(), tt.args.refreshToken)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
-			assert.Equal(t, tt.wantUser, got)
-			assert.NoError(t, mock.ExpectationsWereMet())
-		})
-	}
-}
@@ -0,0 +1,70 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func updateUserTimeZoneBuilder(userId int, timeZone string) (string, []interface{}, error) {
+	builder := sq.Update(users).
+		Set("time_zone", timeZone).
+		Where(sq.Eq{"id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) UpdateUserTimeZone(ctx context.Context, userId int, timeZone string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := updateUserTimeZoneBuilder(userId, timeZone)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func updateUserPasswordBuilder(userId int, password string) (string, []interface{}, error) {
+	builder := sq.Update(users).
+		Set("password", password).
+		Where(sq.Eq{"id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) UpdateUserPassword(ctx context.Context, userId int, password string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := updateUserPasswordBuilder(userId, password)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
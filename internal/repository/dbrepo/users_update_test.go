@@ -0,0 +1,160 @@
+package dbrepo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateUserTimeZone(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id       int
+		timeZone string
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectExec := "UPDATE users SET time_zone = $1 WHERE id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
+					WithArgs(args.timeZone, args.id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			args: args{
+				id:       1,
+				timeZone: "Europe/Berlin",
+			},
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectExec := "UPDATE users SET time_zone = $1 WHERE id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
+					WithArgs(args.timeZone, args.id).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			args: args{
+				id:       1,
+				timeZone: "Europe/Berlin",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.UpdateUserTimeZone(context.Background(), tt.args.id, tt.args.timeZone)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type args struct {
+		id       int
+		password string
+	}
+
+	type mockBehavior func(args args)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		args         args
+		wantErr      bool
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectExec := "UPDATE users SET password = $1 WHERE id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
+					WithArgs(args.password, args.id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			args: args{
+				id:       1,
+				password: "$argon2id$v=19$m=65536,t=1,p=4$c2FsdA$a2V5",
+			},
+		},
+		{
+			name: "Failed",
+			mockBehavior: func(args args) {
+				mock.ExpectBegin()
+
+				expectExec := "UPDATE users SET password = $1 WHERE id = $2"
+				mock.ExpectExec(regexp.QuoteMeta(expectExec)).
+					WithArgs(args.password, args.id).
+					WillReturnError(errors.New("test error"))
+
+				mock.ExpectRollback()
+			},
+			args: args{
+				id:       1,
+				password: "$argon2id$v=19$m=65536,t=1,p=4$c2FsdA$a2V5",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(tt.args)
+
+			err := r.UpdateUserPassword(context.Background(), tt.args.id, tt.args.password)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+var invitationColumns = []string{"id", "workspace_id", "email", "role", "invited_by", "created_at", "expires_at"}
+
+func scanInvitation(row rowScanner, invitation *entity.WorkspaceInvitation) error {
+	var invitedBy sql.NullInt64
+	err := row.Scan(&invitation.ID, &invitation.WorkspaceId, &invitation.Email, &invitation.Role, &invitedBy,
+		&invitation.CreatedAt, &invitation.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	invitation.InvitedBy = int(invitedBy.Int64)
+	return nil
+}
+
+func createInvitationBuilder(invitation entity.WorkspaceInvitation) (string, []interface{}, error) {
+	builder := sq.Insert(invitations).
+		Columns("workspace_id", "email", "role", "token_hash", "invited_by", "expires_at").
+		Values(invitation.WorkspaceId, invitation.Email, invitation.Role, invitation.TokenHash, invitation.InvitedBy, invitation.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) CreateWorkspaceInvitation(ctx context.Context, invitation entity.WorkspaceInvitation) (int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createInvitationBuilder(invitation)
+	if err != nil {
+		return 0, err
+	}
+
+	var invitationId int
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&invitationId)
+	if err != nil {
+		return 0, err
+	}
+
+	return invitationId, tx.Commit()
+}
+
+func getInvitationsBuilder(workspaceId int) (string, []interface{}, error) {
+	builder := sq.Select(invitationColumns...).
+		From(invitations).
+		Where(sq.Eq{"workspace_id": workspaceId}).
+		Where(sq.Expr("expires_at > NOW()")).
+		OrderBy("created_at DESC", "id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetWorkspaceInvitations returns pending invitations of the workspace, the latest first.
+func (r *DBRepo) GetWorkspaceInvitations(ctx context.Context, workspaceId int) ([]entity.WorkspaceInvitation, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getInvitationsBuilder(workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.WorkspaceInvitation
+	for rows.Next() {
+		var invitation entity.WorkspaceInvitation
+		if err := scanInvitation(rows, &invitation); err != nil {
+			return nil, err
+		}
+		result = append(result, invitation)
+	}
+
+	return result, tx.Commit()
+}
+
+func deleteInvitationBuilder(id, workspaceId int) (string, []interface{}, error) {
+	builder := sq.Delete(invitations).
+		Where(sq.Eq{"id": id, "workspace_id": workspaceId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) DeleteWorkspaceInvitation(ctx context.Context, id, workspaceId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteInvitationBuilder(id, workspaceId)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func useInvitationBuilder(tokenHash, email string) (string, []interface{}, error) {
+	builder := sq.Delete(invitations).
+		Where(sq.Eq{"token_hash": tokenHash, "email": email}).
+		Where(sq.Expr("expires_at > NOW()")).
+		Suffix("RETURNING " + strings.Join(invitationColumns, ", ")).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// AcceptWorkspaceInvitation uses the invitation sent to the email adding the user to the workspace
+// with the role invited, a user who is already a member keeps their role. sql.ErrNoRows is returned
+// when the invitation is unknown, expired or sent to another email.
+func (r *DBRepo) AcceptWorkspaceInvitation(ctx context.Context, tokenHash, email string, userId int) (entity.WorkspaceInvitation, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.WorkspaceInvitation{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := useInvitationBuilder(tokenHash, email)
+	if err != nil {
+		return entity.WorkspaceInvitation{}, err
+	}
+
+	var invitation entity.WorkspaceInvitation
+	if err = scanInvitation(tx.QueryRowContext(ctx, query, args...), &invitation); err != nil {
+		return entity.WorkspaceInvitation{}, err
+	}
+
+	query, args, err = addMemberBuilder(invitation.WorkspaceId, userId, invitation.Role)
+	if err != nil {
+		return entity.WorkspaceInvitation{}, err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return entity.WorkspaceInvitation{}, err
+	}
+
+	return invitation, tx.Commit()
+}
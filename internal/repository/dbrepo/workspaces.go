@@ -0,0 +1,417 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// ownedBy filters notes and lists of the active workspace of the request, or personal ones of the user
+// when no workspace is active. Alias qualifies the columns for joins.
+func ownedBy(ctx context.Context, alias string, userId int) sq.Eq {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+
+	if member, ok := entity.WorkspaceFromContext(ctx); ok {
+		return sq.Eq{prefix + "workspace_id": member.WorkspaceId}
+	}
+
+	return sq.Eq{prefix + "user_id": userId, prefix + "workspace_id": nil}
+}
+
+// workspaceValue is the workspace new notes and lists are created in, NULL for personal ones.
+func workspaceValue(ctx context.Context) any {
+	if member, ok := entity.WorkspaceFromContext(ctx); ok {
+		return member.WorkspaceId
+	}
+	return nil
+}
+
+/*-----------------------------
+				WORKSPACES
+ ----------------------------- */
+
+func createWorkspaceBuilder(name string) (string, []interface{}, error) {
+	builder := sq.Insert(workspaces).
+		Columns("name").
+		Values(name).
+		Suffix("RETURNING id, created_at").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func addMemberBuilder(workspaceId, userId int, role string) (string, []interface{}, error) {
+	builder := sq.Insert(members).
+		Columns("workspace_id", "user_id", "role").
+		Values(workspaceId, userId, role).
+		Suffix("ON CONFLICT (workspace_id, user_id) DO NOTHING").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// CreateWorkspace creates the workspace with the user as its owner.
+func (r *DBRepo) CreateWorkspace(ctx context.Context, name string, ownerId int) (entity.Workspace, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.Workspace{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := createWorkspaceBuilder(name)
+	if err != nil {
+		return entity.Workspace{}, err
+	}
+
+	workspace := entity.Workspace{Name: name, Role: entity.WorkspaceRoleOwner}
+	if err = tx.QueryRowContext(ctx, query, args...).Scan(&workspace.ID, &workspace.CreatedAt); err != nil {
+		return entity.Workspace{}, err
+	}
+
+	query, args, err = addMemberBuilder(workspace.ID, ownerId, entity.WorkspaceRoleOwner)
+	if err != nil {
+		return entity.Workspace{}, err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return entity.Workspace{}, err
+	}
+
+	return workspace, tx.Commit()
+}
+
+func getWorkspacesBuilder(userId int) (string, []interface{}, error) {
+	builder := sq.Select("w.id", "w.name", "m.role", "w.created_at").
+		From(workspaces+" w").
+		Join(members+" m ON m.workspace_id = w.id").
+		Where(sq.Eq{"m.user_id": userId}).
+		OrderBy("w.name", "w.id").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// GetWorkspaces returns workspaces the user is a member of along with the role of the user.
+func (r *DBRepo) GetWorkspaces(ctx context.Context, userId int) ([]entity.Workspace, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getWorkspacesBuilder(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.Workspace
+	for rows.Next() {
+		var workspace entity.Workspace
+		if err := rows.Scan(&workspace.ID, &workspace.Name, &workspace.Role, &workspace.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, workspace)
+	}
+
+	return result, tx.Commit()
+}
+
+func updateWorkspaceBuilder(id int, name string) (string, []interface{}, error) {
+	builder := sq.Update(workspaces).
+		Set("name", name).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) UpdateWorkspace(ctx context.Context, id int, name string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := updateWorkspaceBuilder(id, name)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// deleteWorkspaceAttachmentsBuilder deletes attachments of the workspace notes returning keys of their blobs.
+func deleteWorkspaceAttachmentsBuilder(id int) (string, []interface{}, error) {
+	subquery := sq.Select("id").
+		From(notes).
+		Where(sq.Eq{"workspace_id": id})
+
+	builder := sq.Delete(attachments).
+		Where(sq.Expr("note_id IN (?)", subquery)).
+		Suffix("RETURNING blob_key").
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func deleteWorkspaceBuilder(id int) (string, []interface{}, error) {
+	builder := sq.Delete(workspaces).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// DeleteWorkspace deletes the workspace with its notes, lists, members and invitations, keys of blobs
+// of the notes attachments are returned for the caller to delete them once the workspace is gone.
+func (r *DBRepo) DeleteWorkspace(ctx context.Context, id int) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteWorkspaceAttachmentsBuilder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query, args, err = deleteWorkspaceBuilder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if deleted == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return keys, tx.Commit()
+}
+
+/*-----------------------------
+				MEMBERS
+ ----------------------------- */
+
+func getMembersBuilder(workspaceId int, userId int) (string, []interface{}, error) {
+	builder := sq.Select("m.workspace_id", "m.user_id", "u.login", "m.role", "m.created_at").
+		From(members + " m").
+		Join(users + " u ON u.id = m.user_id").
+		Where(sq.Eq{"m.workspace_id": workspaceId}).
+		PlaceholderFormat(sq.Dollar)
+
+	if userId != 0 {
+		builder = builder.Where(sq.Eq{"m.user_id": userId})
+	} else {
+		builder = builder.OrderBy("m.created_at", "m.user_id")
+	}
+
+	return builder.ToSql()
+}
+
+func scanMember(row rowScanner, member *entity.WorkspaceMember) error {
+	return row.Scan(&member.WorkspaceId, &member.UserId, &member.Login, &member.Role, &member.CreatedAt)
+}
+
+// GetWorkspaceMember returns the membership of the user in the workspace, sql.ErrNoRows is returned
+// when the user isn't a member.
+func (r *DBRepo) GetWorkspaceMember(ctx context.Context, workspaceId, userId int) (entity.WorkspaceMember, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return entity.WorkspaceMember{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getMembersBuilder(workspaceId, userId)
+	if err != nil {
+		return entity.WorkspaceMember{}, err
+	}
+
+	var member entity.WorkspaceMember
+	if err = scanMember(tx.QueryRowContext(ctx, query, args...), &member); err != nil {
+		return entity.WorkspaceMember{}, err
+	}
+
+	return member, tx.Commit()
+}
+
+func (r *DBRepo) GetWorkspaceMembers(ctx context.Context, workspaceId int) ([]entity.WorkspaceMember, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := getMembersBuilder(workspaceId, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []entity.WorkspaceMember
+	for rows.Next() {
+		var member entity.WorkspaceMember
+		if err := scanMember(rows, &member); err != nil {
+			return nil, err
+		}
+		result = append(result, member)
+	}
+
+	return result, tx.Commit()
+}
+
+func updateMemberBuilder(workspaceId, userId int, role string) (string, []interface{}, error) {
+	builder := sq.Update(members).
+		Set("role", role).
+		Where(sq.Eq{"workspace_id": workspaceId, "user_id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+func (r *DBRepo) UpdateWorkspaceMember(ctx context.Context, workspaceId, userId int, role string) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := updateMemberBuilder(workspaceId, userId, role)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if updated, err := res.RowsAffected(); err != nil {
+		return err
+	} else if updated == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func deleteMemberBuilder(workspaceId, userId int) (string, []interface{}, error) {
+	builder := sq.Delete(members).
+		Where(sq.Eq{"workspace_id": workspaceId, "user_id": userId}).
+		PlaceholderFormat(sq.Dollar)
+
+	return builder.ToSql()
+}
+
+// DeleteWorkspaceMember removes the user from the workspace, notes and lists they created stay in it
+// while notes assigned to them are unassigned.
+func (r *DBRepo) DeleteWorkspaceMember(ctx context.Context, workspaceId, userId int) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := deleteMemberBuilder(workspaceId, userId)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if deleted, err := res.RowsAffected(); err != nil {
+		return err
+	} else if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	query, args, err = unassignBuilder(sq.Eq{"workspace_id": workspaceId, "assignee_id": userId})
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,236 @@
+package dbrepo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceScope(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	ctx := entity.ContextWithWorkspace(context.Background(), entity.WorkspaceMember{WorkspaceId: 7, UserId: 2, Role: entity.WorkspaceRoleMember})
+	date := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	expectedQuery := "SELECT id, user_id, title, description, date, status, priority, due_at, recurrence, list_id, deleted_at, assignee_id " +
+		"FROM notes WHERE workspace_id = $1 AND deleted_at IS NULL AND id = $2"
+	mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+		WithArgs(7, 3).
+		WillReturnRows(sqlmock.NewRows(noteColumns).
+			AddRow(3, 1, "Plan", "", date, entity.StatusNotDone, entity.PriorityLow, nil, nil, nil, nil, nil))
+	mock.ExpectCommit()
+
+	note, err := r.GetNoteById(ctx, 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, note.UserId, "notes of a workspace keep their creator")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO lists (user_id,name,workspace_id) VALUES ($1,$2,$3) RETURNING id")).
+		WithArgs(2, "Team", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+
+	id, err := r.CreateList(ctx, entity.List{UserId: 2, Name: "Team"})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateWorkspace(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	now := time.Now().Round(time.Second)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO workspaces (name) VALUES ($1) RETURNING id, created_at")).
+		WithArgs("Team").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO workspace_members (workspace_id,user_id,role) VALUES ($1,$2,$3) "+
+		"ON CONFLICT (workspace_id, user_id) DO NOTHING")).
+		WithArgs(7, 1, entity.WorkspaceRoleOwner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	got, err := r.CreateWorkspace(context.Background(), "Team", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Workspace{ID: 7, Name: "Team", Role: entity.WorkspaceRoleOwner, CreatedAt: now}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteWorkspace(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	expectedAttachmentsQuery := "DELETE FROM note_attachments WHERE note_id IN (SELECT id FROM notes " +
+		"WHERE workspace_id = $1) RETURNING blob_key"
+	expectedQuery := "DELETE FROM workspaces WHERE id = $1"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(expectedAttachmentsQuery)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"blob_key"}).AddRow("notes/3/a").AddRow("notes/4/b"))
+		mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		keys, err := r.DeleteWorkspace(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"notes/3/a", "notes/4/b"}, keys)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotExists", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(expectedAttachmentsQuery)).
+			WithArgs(8).
+			WillReturnRows(sqlmock.NewRows([]string{"blob_key"}))
+		mock.ExpectExec(regexp.QuoteMeta(expectedQuery)).
+			WithArgs(8).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err := r.DeleteWorkspace(context.Background(), 8)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetWorkspaceMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	type mockBehavior func(workspaceId, userId int)
+
+	expectedQuery := "SELECT m.workspace_id, m.user_id, u.login, m.role, m.created_at FROM workspace_members m " +
+		"JOIN users u ON u.id = m.user_id WHERE m.workspace_id = $1 AND m.user_id = $2"
+
+	now := time.Now().Round(time.Second)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		want         entity.WorkspaceMember
+		wantErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(workspaceId, userId int) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(workspaceId, userId).
+					WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "login", "role", "created_at"}).
+						AddRow(workspaceId, userId, "colleague", entity.WorkspaceRoleGuest, now))
+
+				mock.ExpectCommit()
+			},
+			want: entity.WorkspaceMember{WorkspaceId: 7, UserId: 2, Login: "colleague", Role: entity.WorkspaceRoleGuest, CreatedAt: now},
+		},
+		{
+			name: "NotMember",
+			mockBehavior: func(workspaceId, userId int) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+					WithArgs(workspaceId, userId).
+					WillReturnError(sql.ErrNoRows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehavior(7, 2)
+
+			got, err := r.GetWorkspaceMember(context.Background(), 7, 2)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAcceptWorkspaceInvitation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := New(db)
+
+	expectedQuery := "DELETE FROM workspace_invitations WHERE email = $1 AND token_hash = $2 AND expires_at > NOW() " +
+		"RETURNING id, workspace_id, email, role, invited_by, created_at, expires_at"
+
+	t.Run("Success", func(t *testing.T) {
+		now := time.Now().Round(time.Second)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WithArgs("bob@example.com", "hash").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id", "email", "role", "invited_by", "created_at", "expires_at"}).
+				AddRow(4, 7, "bob@example.com", entity.WorkspaceRoleMember, nil, now, now.Add(time.Hour)))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO workspace_members (workspace_id,user_id,role) VALUES ($1,$2,$3) "+
+			"ON CONFLICT (workspace_id, user_id) DO NOTHING")).
+			WithArgs(7, 2, entity.WorkspaceRoleMember).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		invitation, err := r.AcceptWorkspaceInvitation(context.Background(), "hash", "bob@example.com", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, invitation.WorkspaceId)
+		assert.Zero(t, invitation.InvitedBy, "inviter may have deleted the account")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("AnotherEmail", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WithArgs("eve@example.com", "hash").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := r.AcceptWorkspaceInvitation(context.Background(), "hash", "eve@example.com", 3)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
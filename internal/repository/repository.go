@@ -12,10 +12,100 @@ type NotesRepository interface {
 	GetNoteByTitle(ctx context.Context, title string, userId int) (entity.Note, error)
 	GetNoteById(ctx context.Context, id, userId int) (entity.Note, error)
 	GetNotes(ctx context.Context, userId int) ([]entity.Note, error)
-	GetNotesExtended(ctx context.Context, limit, offset int, status string, date time.Time, userId int) ([]entity.Note, error)
-	UpdateNote(ctx context.Context, id, userId int, title, description, status string) error
+	GetNotesExtended(ctx context.Context, limit, offset int, filter entity.NoteFilter, userId int) ([]entity.Note, error)
+	SearchNotes(ctx context.Context, limit, offset int, filter entity.NoteFilter, userId int) ([]entity.Note, error)
+	CountNotes(ctx context.Context, filter entity.NoteFilter, userId int) (int, error)
+	UpdateNote(ctx context.Context, id, userId int, upd entity.NoteUpdate) error
+	ReplaceNote(ctx context.Context, note entity.Note) error
+	AssignNote(ctx context.Context, id, userId, assigneeId int) error
 	DeleteNoteById(ctx context.Context, id, userId int) error
 	DeleteNotes(ctx context.Context, userId int) error
+	GetTrashedNoteById(ctx context.Context, id, userId int) (entity.Note, error)
+	GetTrashedNotes(ctx context.Context, userId int) ([]entity.Note, error)
+	RestoreNote(ctx context.Context, id, userId int) error
+	PurgeNote(ctx context.Context, id, userId int) ([]string, error)
+	PurgeNotes(ctx context.Context, userId int) ([]string, error)
+	PurgeTrashedBefore(ctx context.Context, before time.Time) (int64, []string, error)
+}
+
+type SharesRepository interface {
+	ShareNote(ctx context.Context, share entity.NoteShare) error
+	UnshareNote(ctx context.Context, noteId, userId int) error
+	GetNoteShare(ctx context.Context, noteId, userId int) (entity.NoteShare, error)
+	GetNoteShares(ctx context.Context, noteId int) ([]entity.NoteShare, error)
+	GetSharedNotes(ctx context.Context, userId int) ([]entity.SharedNote, error)
+}
+
+type WorkspacesRepository interface {
+	CreateWorkspace(ctx context.Context, name string, ownerId int) (entity.Workspace, error)
+	GetWorkspaces(ctx context.Context, userId int) ([]entity.Workspace, error)
+	UpdateWorkspace(ctx context.Context, id int, name string) error
+	DeleteWorkspace(ctx context.Context, id int) ([]string, error)
+	GetWorkspaceMember(ctx context.Context, workspaceId, userId int) (entity.WorkspaceMember, error)
+	GetWorkspaceMembers(ctx context.Context, workspaceId int) ([]entity.WorkspaceMember, error)
+	UpdateWorkspaceMember(ctx context.Context, workspaceId, userId int, role string) error
+	DeleteWorkspaceMember(ctx context.Context, workspaceId, userId int) error
+	CreateWorkspaceInvitation(ctx context.Context, invitation entity.WorkspaceInvitation) (int, error)
+	GetWorkspaceInvitations(ctx context.Context, workspaceId int) ([]entity.WorkspaceInvitation, error)
+	DeleteWorkspaceInvitation(ctx context.Context, id, workspaceId int) error
+	AcceptWorkspaceInvitation(ctx context.Context, tokenHash, email string, userId int) (entity.WorkspaceInvitation, error)
+}
+
+type NotificationsRepository interface {
+	CreateNotification(ctx context.Context, notification entity.Notification) (int, error)
+	GetNotifications(ctx context.Context, userId int, unread bool, limit int) ([]entity.Notification, error)
+	ReadNotification(ctx context.Context, id, userId int) error
+	ReadNotifications(ctx context.Context, userId int) error
+}
+
+type CommentsRepository interface {
+	CreateComment(ctx context.Context, comment entity.Comment) (int, error)
+	GetComment(ctx context.Context, id, noteId int) (entity.Comment, error)
+	GetComments(ctx context.Context, noteId, afterId, limit int) ([]entity.Comment, error)
+	UpdateComment(ctx context.Context, comment entity.Comment) error
+	DeleteComment(ctx context.Context, id, noteId int) error
+	CountNotesComments(ctx context.Context, noteIds []int) (map[int]int, error)
+}
+
+type AttachmentsRepository interface {
+	CreateAttachment(ctx context.Context, attachment entity.Attachment) (entity.Attachment, error)
+	GetAttachment(ctx context.Context, id, noteId int) (entity.Attachment, error)
+	GetAttachments(ctx context.Context, noteId int) ([]entity.Attachment, error)
+	DeleteAttachment(ctx context.Context, id, noteId int) error
+}
+
+type TagsRepository interface {
+	GetTags(ctx context.Context, userId int) ([]entity.Tag, error)
+	GetTagById(ctx context.Context, id, userId int) (entity.Tag, error)
+	GetTagByName(ctx context.Context, name string, userId int) (entity.Tag, error)
+	GetNotesTags(ctx context.Context, noteIds []int) (map[int][]string, error)
+	UpdateTag(ctx context.Context, id, userId int, name string) error
+	DeleteTag(ctx context.Context, id, userId int) error
+}
+
+type RevisionsRepository interface {
+	CreateRevision(ctx context.Context, revision entity.Revision) (int, error)
+	GetRevisions(ctx context.Context, noteId int) ([]entity.Revision, error)
+	GetRevisionById(ctx context.Context, id, noteId int) (entity.Revision, error)
+}
+
+type ListsRepository interface {
+	CreateList(ctx context.Context, list entity.List) (int, error)
+	GetLists(ctx context.Context, userId int) ([]entity.List, error)
+	GetListById(ctx context.Context, id, userId int) (entity.List, error)
+	GetListByName(ctx context.Context, name string, userId int) (entity.List, error)
+	UpdateList(ctx context.Context, id, userId int, name string) error
+	DeleteList(ctx context.Context, id, userId int, cascade bool) error
+	MoveNotes(ctx context.Context, noteIds []int, listId, userId int) error
+}
+
+type ItemsRepository interface {
+	CreateItem(ctx context.Context, item entity.Item) (int, error)
+	GetItemById(ctx context.Context, id, noteId int) (entity.Item, error)
+	GetItems(ctx context.Context, noteId int) ([]entity.Item, error)
+	UpdateItem(ctx context.Context, id, noteId int, upd entity.ItemUpdate) error
+	ReorderItems(ctx context.Context, noteId int, ids []int) error
+	DeleteItem(ctx context.Context, id, noteId int) error
 }
 
 type UsersRepository interface {
@@ -23,12 +113,70 @@ type UsersRepository interface {
 	GetUserByID(ctx context.Context, id int) (entity.User, error)
 	GetUserByLogin(ctx context.Context, login string) (entity.User, error)
 	GetUserByEmail(ctx context.Context, email string) (entity.User, error)
-	GetUserByCredentials(ctx context.Context, login, password string) (entity.User, error)
-	GetUserByRefreshToken(ctx context.Context, refreshToken string) (entity.User, error)
-	SetSession(ctx context.Context, id int, session entity.Session) error
+	UpdateUserTimeZone(ctx context.Context, id int, timeZone string) error
+	UpdateUserPassword(ctx context.Context, id int, password string) error
+}
+
+type SessionsRepository interface {
+	CreateSession(ctx context.Context, session entity.Session) (int, error)
+	GetSessionById(ctx context.Context, id, userId int) (entity.Session, error)
+	GetSessionByRefreshToken(ctx context.Context, refreshTokenHash string) (entity.Session, error)
+	GetSessions(ctx context.Context, userId int) ([]entity.Session, error)
+	GetSessionByUsedRefreshToken(ctx context.Context, refreshTokenHash string) (entity.Session, error)
+	RotateSession(ctx context.Context, session entity.Session, usedTokenHash string) error
+	DeleteSession(ctx context.Context, id, userId int) error
+	DeleteSessions(ctx context.Context, userId int) error
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
+}
+
+type PasswordResetsRepository interface {
+	CreatePasswordReset(ctx context.Context, reset entity.PasswordReset) (int, error)
+	ResetPassword(ctx context.Context, tokenHash, password string) (int, error)
+}
+
+type EmailVerificationsRepository interface {
+	CreateEmailVerification(ctx context.Context, verification entity.EmailVerification) (int, error)
+	GetLatestEmailVerification(ctx context.Context, userId int) (entity.EmailVerification, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (int, error)
+}
+
+type TwoFactorRepository interface {
+	GetTwoFactor(ctx context.Context, userId int) (entity.TwoFactor, error)
+	SetTwoFactorSecret(ctx context.Context, userId int, secret string) error
+	EnableTwoFactor(ctx context.Context, userId int, step int64, codeHashes []string) error
+	SetTwoFactorStep(ctx context.Context, userId int, step int64) error
+	FailTwoFactor(ctx context.Context, userId, maxAttempts int, lockedUntil time.Time) error
+	DeleteTwoFactor(ctx context.Context, userId int) error
+	UseRecoveryCode(ctx context.Context, userId int, codeHash string) error
+	CreateChallenge(ctx context.Context, challenge entity.TwoFactorChallenge) (int, error)
+	GetChallenge(ctx context.Context, tokenHash string) (entity.TwoFactorChallenge, error)
+	FailChallenge(ctx context.Context, id, maxAttempts int) error
+	DeleteChallenge(ctx context.Context, id int) error
+}
+
+type PersonalAccessTokensRepository interface {
+	CreatePersonalAccessToken(ctx context.Context, token entity.PersonalAccessToken) (int, error)
+	GetPersonalAccessTokens(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error)
+	UsePersonalAccessToken(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error)
+	DeletePersonalAccessToken(ctx context.Context, id, userId int) error
+	DeletePersonalAccessTokens(ctx context.Context, userId int) error
 }
 
 type Repository interface {
 	NotesRepository
+	SharesRepository
+	WorkspacesRepository
+	NotificationsRepository
+	CommentsRepository
+	AttachmentsRepository
+	TagsRepository
+	RevisionsRepository
+	ListsRepository
+	ItemsRepository
 	UsersRepository
+	SessionsRepository
+	PasswordResetsRepository
+	EmailVerificationsRepository
+	TwoFactorRepository
+	PersonalAccessTokensRepository
 }
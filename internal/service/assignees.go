@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/logger"
+)
+
+// AssignNote assigns the note to the user, zero assignee unassigns the note. Editors of the note can assign it
+// to anyone with access to it: the owner and collaborators of a personal note or members of the workspace.
+// The assignment is recorded in the history of the note and the assignee is notified unless they assigned themselves.
+func (s *Service) AssignNote(ctx context.Context, noteId, userId, assigneeId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	note, err := s.getNote(ctx, noteId, userId, entity.PermissionEditor)
+	if err != nil {
+		return err
+	}
+
+	if assigneeId != 0 {
+		ok, err := s.hasNoteAccess(ctx, note, assigneeId)
+		if err != nil {
+			return err
+		} else if !ok {
+			return entity.ErrAssigneeNoAccess
+		}
+	}
+
+	current := 0
+	if note.AssigneeId != nil {
+		current = *note.AssigneeId
+	}
+
+	if current == assigneeId {
+		return nil
+	}
+
+	if err = s.repo.AssignNote(ctx, noteId, note.UserId, assigneeId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrNoteNotExists
+		}
+		return err
+	}
+
+	assigned := note
+	assigned.AssigneeId = nil
+	if assigneeId != 0 {
+		assigned.AssigneeId = &assigneeId
+	}
+
+	if err = s.recordRevision(ctx, entity.RevisionAssign, note, assigned, userId); err != nil {
+		return err
+	}
+
+	if assigneeId == 0 || assigneeId == userId {
+		return nil
+	}
+
+	// the assignment is already saved, so a failed notification is only logged
+	_, err = s.repo.CreateNotification(ctx, entity.Notification{
+		UserId:  assigneeId,
+		Type:    entity.NotificationAssigned,
+		NoteId:  &noteId,
+		ActorId: &userId,
+	})
+	if err != nil {
+		logger.ErrorKV(ctx, "Failed create notification", "note_id", noteId, "user_id", assigneeId, "err", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type assigneesRepo struct {
+	repository.Repository
+
+	note          entity.Note
+	shares        map[int]string
+	members       map[int]string
+	revisions     []entity.Revision
+	notifications []entity.Notification
+}
+
+func (r *assigneesRepo) GetNoteById(ctx context.Context, id, userId int) (entity.Note, error) {
+	_, inWorkspace := entity.WorkspaceFromContext(ctx)
+	if id != r.note.ID || !inWorkspace && r.note.UserId != userId {
+		return entity.Note{}, sql.ErrNoRows
+	}
+	return r.note, nil
+}
+
+func (r *assigneesRepo) GetNotesTags(context.Context, []int) (map[int][]string, error) {
+	return nil, nil
+}
+
+func (r *assigneesRepo) GetNoteShare(_ context.Context, noteId, userId int) (entity.NoteShare, error) {
+	permission, ok := r.shares[userId]
+	if !ok || noteId != r.note.ID {
+		return entity.NoteShare{}, sql.ErrNoRows
+	}
+	return entity.NoteShare{NoteId: noteId, UserId: userId, OwnerId: r.note.UserId, Permission: permission}, nil
+}
+
+func (r *assigneesRepo) GetWorkspaceMember(_ context.Context, workspaceId, userId int) (entity.WorkspaceMember, error) {
+	role, ok := r.members[userId]
+	if !ok {
+		return entity.WorkspaceMember{}, sql.ErrNoRows
+	}
+	return entity.WorkspaceMember{WorkspaceId: workspaceId, UserId: userId, Role: role}, nil
+}
+
+func (r *assigneesRepo) AssignNote(_ context.Context, id, userId, assigneeId int) error {
+	if id != r.note.ID || userId != r.note.UserId {
+		return sql.ErrNoRows
+	}
+	r.note.AssigneeId = nil
+	if assigneeId != 0 {
+		r.note.AssigneeId = &assigneeId
+	}
+	return nil
+}
+
+func (r *assigneesRepo) CreateRevision(_ context.Context, revision entity.Revision) (int, error) {
+	r.revisions = append(r.revisions, revision)
+	return len(r.revisions), nil
+}
+
+func (r *assigneesRepo) CreateNotification(_ context.Context, notification entity.Notification) (int, error) {
+	r.notifications = append(r.notifications, notification)
+	return len(r.notifications), nil
+}
+
+func TestAssignNote(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &assigneesRepo{
+		note:   entity.Note{ID: 10, UserId: 1, Title: "Plan"},
+		shares: map[int]string{2: entity.PermissionEditor, 3: entity.PermissionViewer},
+	}
+	s := &Service{repo: repo}
+
+	assert.ErrorIs(t, s.AssignNote(ctx, 10, 1, 4), entity.ErrAssigneeNoAccess, "assignee must see the note")
+	assert.ErrorIs(t, s.AssignNote(ctx, 10, 3, 2), entity.ErrNoteForbidden, "viewers can't assign")
+	assert.ErrorIs(t, s.AssignNote(ctx, 10, 4, 1), entity.ErrNoteNotExists)
+
+	require.NoError(t, s.AssignNote(ctx, 10, 1, 3))
+	require.NotNil(t, repo.note.AssigneeId)
+	assert.Equal(t, 3, *repo.note.AssigneeId)
+
+	require.Len(t, repo.revisions, 1)
+	assert.Equal(t, entity.RevisionAssign, repo.revisions[0].Action)
+	assert.Equal(t, entity.Change{Old: (*int)(nil), New: repo.note.AssigneeId}, repo.revisions[0].Changes["assignee_id"])
+
+	require.Len(t, repo.notifications, 1)
+	assert.Equal(t, 3, repo.notifications[0].UserId)
+	assert.Equal(t, entity.NotificationAssigned, repo.notifications[0].Type)
+	assert.Equal(t, 1, *repo.notifications[0].ActorId)
+
+	require.NoError(t, s.AssignNote(ctx, 10, 1, 3))
+	assert.Len(t, repo.revisions, 1, "assigning the same user again changes nothing")
+
+	require.NoError(t, s.AssignNote(ctx, 10, 2, 2), "editors can reassign the note")
+	assert.Len(t, repo.revisions, 2)
+	assert.Len(t, repo.notifications, 1, "assigning yourself doesn't notify")
+
+	require.NoError(t, s.AssignNote(ctx, 10, 1, 0))
+	assert.Nil(t, repo.note.AssigneeId)
+	assert.Len(t, repo.revisions, 3)
+}
+
+func TestAssignWorkspaceNote(t *testing.T) {
+	repo := &assigneesRepo{
+		note:    entity.Note{ID: 10, UserId: 1, Title: "Plan"},
+		members: map[int]string{1: entity.WorkspaceRoleOwner, 2: entity.WorkspaceRoleMember, 3: entity.WorkspaceRoleGuest},
+	}
+	s := &Service{repo: repo}
+
+	guestCtx := entity.ContextWithWorkspace(context.Background(), entity.WorkspaceMember{WorkspaceId: 7, UserId: 3, Role: entity.WorkspaceRoleGuest})
+	assert.ErrorIs(t, s.AssignNote(guestCtx, 10, 3, 3), entity.ErrWorkspaceForbidden)
+
+	ctx := entity.ContextWithWorkspace(context.Background(), entity.WorkspaceMember{WorkspaceId: 7, UserId: 2, Role: entity.WorkspaceRoleMember})
+	assert.ErrorIs(t, s.AssignNote(ctx, 10, 2, 4), entity.ErrAssigneeNoAccess, "assignee must be a member of the workspace")
+
+	require.NoError(t, s.AssignNote(ctx, 10, 2, 3))
+	assert.Equal(t, 3, *repo.note.AssigneeId)
+	assert.Len(t, repo.notifications, 1)
+}
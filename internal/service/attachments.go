@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/logger"
+)
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// MaxAttachmentSize is the size limit of an uploaded file in bytes.
+func (s *Service) MaxAttachmentSize() int64 {
+	return s.maxAttachmentSize
+}
+
+// UploadAttachment stores the file of size bytes and attaches it to the note, editors of the note can attach files.
+// The type is sniffed from the content instead of trusting the client and must be one of the allowed types.
+func (s *Service) UploadAttachment(ctx context.Context, noteId, userId int, name string, size int64, content io.Reader) (entity.Attachment, error) {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return entity.Attachment{}, err
+	}
+
+	if _, err := s.getNote(ctx, noteId, userId, entity.PermissionEditor); err != nil {
+		return entity.Attachment{}, err
+	}
+
+	if size <= 0 {
+		return entity.Attachment{}, entity.ErrInvalidAttachment
+	}
+
+	if size > s.maxAttachmentSize {
+		return entity.Attachment{}, entity.ErrAttachmentTooLarge
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return entity.Attachment{}, err
+	}
+	head = head[:n]
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil || !slices.Contains(s.attachmentTypes, contentType) {
+		return entity.Attachment{}, entity.ErrAttachmentType
+	}
+
+	token, err := auth.NewToken()
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	attachment := entity.Attachment{
+		NoteId:      noteId,
+		UserId:      userId,
+		Name:        name,
+		ContentType: contentType,
+		Size:        size,
+		BlobKey:     fmt.Sprintf("notes/%d/%s", noteId, token),
+	}
+
+	if err = s.blobs.Put(ctx, attachment.BlobKey, io.MultiReader(bytes.NewReader(head), content), size, contentType); err != nil {
+		return entity.Attachment{}, err
+	}
+
+	created, err := s.repo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		s.deleteBlobs(ctx, []string{attachment.BlobKey})
+		return entity.Attachment{}, err
+	}
+
+	return created, nil
+}
+
+// GetAttachments returns attachments of the note, anyone who can see the note can see them.
+func (s *Service) GetAttachments(ctx context.Context, noteId, userId int) ([]entity.Attachment, error) {
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAttachments(ctx, noteId)
+}
+
+// GetAttachment returns the attachment of the note the user can see.
+func (s *Service) GetAttachment(ctx context.Context, noteId, id, userId int) (entity.Attachment, error) {
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionViewer); err != nil {
+		return entity.Attachment{}, err
+	}
+
+	return s.getAttachment(ctx, id, noteId)
+}
+
+// OpenAttachment reads length bytes of the attachment starting at offset, length <= 0 reads until the end.
+// The caller gets the attachment with GetAttachment first, so access is already checked.
+func (s *Service) OpenAttachment(ctx context.Context, attachment entity.Attachment, offset, length int64) (io.ReadCloser, error) {
+	return s.blobs.Get(ctx, attachment.BlobKey, offset, length)
+}
+
+// DeleteAttachment removes the attachment with its blob. Uploaders can delete their attachments, the owner
+// of a personal note and admins of a workspace can delete any attachment of the note.
+func (s *Service) DeleteAttachment(ctx context.Context, noteId, id, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	note, err := s.getNote(ctx, noteId, userId, entity.PermissionEditor)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := s.getAttachment(ctx, id, noteId)
+	if err != nil {
+		return err
+	}
+
+	if attachment.UserId != userId && !canModerateNote(ctx, note, userId) {
+		return entity.ErrAttachmentForbidden
+	}
+
+	if err = s.repo.DeleteAttachment(ctx, id, noteId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrAttachmentNotExists
+		}
+		return err
+	}
+
+	s.deleteBlobs(ctx, []string{attachment.BlobKey})
+
+	return nil
+}
+
+func (s *Service) getAttachment(ctx context.Context, id, noteId int) (entity.Attachment, error) {
+	attachment, err := s.repo.GetAttachment(ctx, id, noteId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Attachment{}, entity.ErrAttachmentNotExists
+		}
+		return entity.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// deleteBlobs deletes blobs nothing refers to anymore. Failures are only logged since the rows are already gone,
+// the blobs are left orphaned in the store then.
+func (s *Service) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			logger.ErrorKV(ctx, "Failed delete blob", "key", key, "err", err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/blobstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// png is the signature of a PNG file which is enough to sniff the type.
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type attachmentsRepo struct {
+	*sharesRepo
+
+	attachments map[int]entity.Attachment
+}
+
+func (r *attachmentsRepo) CreateAttachment(_ context.Context, attachment entity.Attachment) (entity.Attachment, error) {
+	attachment.ID = len(r.attachments) + 1
+	attachment.CreatedAt = time.Now()
+	r.attachments[attachment.ID] = attachment
+	return attachment, nil
+}
+
+func (r *attachmentsRepo) GetAttachment(_ context.Context, id, noteId int) (entity.Attachment, error) {
+	attachment, ok := r.attachments[id]
+	if !ok || attachment.NoteId != noteId {
+		return entity.Attachment{}, sql.ErrNoRows
+	}
+	return attachment, nil
+}
+
+func (r *attachmentsRepo) GetAttachments(_ context.Context, noteId int) ([]entity.Attachment, error) {
+	var result []entity.Attachment
+	for _, attachment := range r.attachments {
+		if attachment.NoteId == noteId {
+			result = append(result, attachment)
+		}
+	}
+	return result, nil
+}
+
+func (r *attachmentsRepo) DeleteAttachment(_ context.Context, id, noteId int) error {
+	if _, err := r.GetAttachment(context.Background(), id, noteId); err != nil {
+		return err
+	}
+	delete(r.attachments, id)
+	return nil
+}
+
+func (r *attachmentsRepo) GetTrashedNoteById(_ context.Context, id, userId int) (entity.Note, error) {
+	note, ok := r.notes[id]
+	if !ok || note.UserId != userId || note.DeletedAt == nil {
+		return entity.Note{}, sql.ErrNoRows
+	}
+	return note, nil
+}
+
+func (r *attachmentsRepo) PurgeNote(_ context.Context, id, _ int) ([]string, error) {
+	var keys []string
+	for attachmentId, attachment := range r.attachments {
+		if attachment.NoteId == id {
+			keys = append(keys, attachment.BlobKey)
+			delete(r.attachments, attachmentId)
+		}
+	}
+	delete(r.notes, id)
+	return keys, nil
+}
+
+func TestAttachments(t *testing.T) {
+	ctx := context.Background()
+
+	blobs, err := blobstore.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	repo := &attachmentsRepo{
+		sharesRepo: &sharesRepo{
+			notes: map[int]entity.Note{10: {ID: 10, UserId: 1, Title: "Plan"}},
+			shares: map[[2]int]string{
+				{10, 2}: entity.PermissionViewer,
+				{10, 3}: entity.PermissionEditor,
+			},
+		},
+		attachments: make(map[int]entity.Attachment),
+	}
+	s := &Service{repo: repo, blobs: blobs, maxAttachmentSize: 64, attachmentTypes: []string{"image/png", "application/pdf"}}
+
+	upload := func(userId int, content []byte) (entity.Attachment, error) {
+		return s.UploadAttachment(ctx, 10, userId, "screen.png", int64(len(content)), bytes.NewReader(content))
+	}
+
+	_, err = upload(1, []byte("just text"))
+	assert.ErrorIs(t, err, entity.ErrAttachmentType, "type is sniffed from the content")
+
+	_, err = upload(1, bytes.Repeat([]byte{0}, 65))
+	assert.ErrorIs(t, err, entity.ErrAttachmentTooLarge)
+
+	_, err = upload(1, nil)
+	assert.ErrorIs(t, err, entity.ErrInvalidAttachment)
+
+	_, err = upload(2, png)
+	assert.ErrorIs(t, err, entity.ErrNoteForbidden, "viewers can't attach files")
+
+	attachment, err := upload(3, png)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", attachment.ContentType)
+	assert.True(t, strings.HasPrefix(attachment.BlobKey, "notes/10/"))
+
+	owned, err := upload(1, png)
+	require.NoError(t, err)
+
+	got, err := s.GetAttachment(ctx, 10, attachment.ID, 2)
+	require.NoError(t, err, "viewers can download attachments")
+
+	r, err := s.OpenAttachment(ctx, got, 1, 3)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, r.Close())
+	require.NoError(t, err)
+	assert.Equal(t, png[1:4], content)
+
+	_, err = s.GetAttachment(ctx, 10, attachment.ID, 4)
+	assert.ErrorIs(t, err, entity.ErrNoteNotExists)
+
+	assert.ErrorIs(t, s.DeleteAttachment(ctx, 10, owned.ID, 3), entity.ErrAttachmentForbidden,
+		"editors delete only their attachments")
+
+	require.NoError(t, s.DeleteAttachment(ctx, 10, attachment.ID, 1), "the owner deletes any attachment")
+	_, err = blobs.Get(ctx, attachment.BlobKey, 0, 0)
+	assert.ErrorIs(t, err, blobstore.ErrNotFound)
+
+	trashedAt := time.Now()
+	note := repo.notes[10]
+	note.DeletedAt = &trashedAt
+	repo.notes[10] = note
+
+	require.NoError(t, s.DeleteNotePermanently(ctx, 10, 1))
+	assert.Empty(t, repo.attachments)
+	_, err = blobs.Get(ctx, owned.BlobKey, 0, 0)
+	assert.ErrorIs(t, err, blobstore.ErrNotFound, "blobs are deleted with the note")
+}
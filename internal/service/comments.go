@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/logger"
+)
+
+// maxMentions limits logins looked up for a comment, the rest of mentions are left as plain text.
+const maxMentions = 10
+
+// CreateComment adds the comment to the thread of the note, anyone who can see the note can comment it.
+// Mentioned users who can see the note are notified.
+func (s *Service) CreateComment(ctx context.Context, noteId, userId int, body string) (entity.Comment, error) {
+	note, err := s.getNote(ctx, noteId, userId, entity.PermissionViewer)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	mentioned, err := s.resolveMentions(ctx, note, body)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	comment := entity.Comment{NoteId: noteId, UserId: userId, Body: body, Mentions: mentionLogins(mentioned)}
+	if comment.ID, err = s.repo.CreateComment(ctx, comment); err != nil {
+		return entity.Comment{}, err
+	}
+
+	s.notifyMentioned(ctx, noteId, userId, mentioned, nil)
+
+	return s.getComment(ctx, comment.ID, noteId)
+}
+
+// GetComments returns a page of the thread of the note oldest first, the page starts after the cursor when it's set.
+// One comment more than limit is fetched to find out whether there are comments beyond the page.
+func (s *Service) GetComments(ctx context.Context, noteId, userId int, cursor *entity.CommentCursor, limit int) (entity.CommentsPage, error) {
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionViewer); err != nil {
+		return entity.CommentsPage{}, err
+	}
+
+	afterId := 0
+	if cursor != nil {
+		afterId = cursor.ID
+	}
+
+	comments, err := s.repo.GetComments(ctx, noteId, afterId, limit+1)
+	if err != nil {
+		return entity.CommentsPage{}, err
+	}
+
+	page := entity.CommentsPage{Comments: comments}
+	if len(comments) > limit {
+		page.Comments = comments[:limit]
+		page.HasMore = true
+		page.NextCursor = entity.CommentCursor{ID: page.Comments[limit-1].ID}.String()
+	}
+
+	return page, nil
+}
+
+// UpdateComment changes the body of the comment, only the author can edit it. Users mentioned for the first time
+// are notified.
+func (s *Service) UpdateComment(ctx context.Context, noteId, id, userId int, body string) (entity.Comment, error) {
+	note, err := s.getNote(ctx, noteId, userId, entity.PermissionViewer)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	comment, err := s.getComment(ctx, id, noteId)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	if comment.UserId != userId {
+		return entity.Comment{}, entity.ErrCommentForbidden
+	}
+
+	mentioned, err := s.resolveMentions(ctx, note, body)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	previous := comment.Mentions
+	comment.Body, comment.Mentions = body, mentionLogins(mentioned)
+
+	if err = s.repo.UpdateComment(ctx, comment); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Comment{}, entity.ErrCommentNotExists
+		}
+		return entity.Comment{}, err
+	}
+
+	s.notifyMentioned(ctx, noteId, userId, mentioned, previous)
+
+	return s.getComment(ctx, id, noteId)
+}
+
+// DeleteComment removes the comment. Authors can delete their comments, the owner of a personal note
+// and admins of a workspace can delete any comment of the note.
+func (s *Service) DeleteComment(ctx context.Context, noteId, id, userId int) error {
+	note, err := s.getNote(ctx, noteId, userId, entity.PermissionViewer)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.getComment(ctx, id, noteId)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserId != userId && !canModerateNote(ctx, note, userId) {
+		return entity.ErrCommentForbidden
+	}
+
+	if err = s.repo.DeleteComment(ctx, id, noteId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrCommentNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) getComment(ctx context.Context, id, noteId int) (entity.Comment, error) {
+	comment, err := s.repo.GetComment(ctx, id, noteId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Comment{}, entity.ErrCommentNotExists
+		}
+		return entity.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// resolveMentions looks up users mentioned in the body, unknown logins and users who can't see the note are skipped.
+func (s *Service) resolveMentions(ctx context.Context, note entity.Note, body string) ([]entity.User, error) {
+	logins := entity.ParseMentions(body)
+	if len(logins) > maxMentions {
+		logins = logins[:maxMentions]
+	}
+
+	var mentioned []entity.User
+	for _, login := range logins {
+		user, err := s.repo.GetUserByLogin(ctx, login)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, err
+		}
+
+		ok, err := s.hasNoteAccess(ctx, note, user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			mentioned = append(mentioned, user)
+		}
+	}
+
+	return mentioned, nil
+}
+
+// notifyMentioned notifies mentioned users except the author and users already mentioned before the edit.
+// The comment is already saved, so failed notifications are only logged.
+func (s *Service) notifyMentioned(ctx context.Context, noteId, authorId int, mentioned []entity.User, previous []string) {
+	notified := make(map[string]struct{}, len(previous))
+	for _, login := range previous {
+		notified[login] = struct{}{}
+	}
+
+	for _, user := range mentioned {
+		if _, ok := notified[user.Login]; ok || user.ID == authorId {
+			continue
+		}
+
+		_, err := s.repo.CreateNotification(ctx, entity.Notification{
+			UserId:  user.ID,
+			Type:    entity.NotificationMentioned,
+			NoteId:  &noteId,
+			ActorId: &authorId,
+		})
+		if err != nil {
+			logger.ErrorKV(ctx, "Failed create notification", "note_id", noteId, "user_id", user.ID, "err", err)
+		}
+	}
+}
+
+// attachCommentCounts sets numbers of comments of notes for listings.
+func (s *Service) attachCommentCounts(ctx context.Context, notes []entity.Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(notes))
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+
+	counts, err := s.repo.CountNotesComments(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range notes {
+		notes[i].CommentsCount = counts[notes[i].ID]
+	}
+
+	return nil
+}
+
+func mentionLogins(users []entity.User) []string {
+	logins := make([]string, len(users))
+	for i, user := range users {
+		logins[i] = user.Login
+	}
+	return logins
+}
+
+// canModerateNote reports whether the user can delete comments and attachments of others on the note.
+func canModerateNote(ctx context.Context, note entity.Note, userId int) bool {
+	if member, ok := entity.WorkspaceFromContext(ctx); ok {
+		return entity.WorkspaceRoleAllows(member.Role, entity.WorkspaceRoleAdmin)
+	}
+
+	return note.UserId == userId
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type commentsRepo struct {
+	repository.Repository
+
+	note          entity.Note
+	shares        map[int]string
+	users         map[string]int
+	comments      []entity.Comment
+	notifications []entity.Notification
+}
+
+func (r *commentsRepo) GetNoteById(_ context.Context, id, userId int) (entity.Note, error) {
+	if id != r.note.ID || userId != r.note.UserId {
+		return entity.Note{}, sql.ErrNoRows
+	}
+	return r.note, nil
+}
+
+func (r *commentsRepo) GetNotesTags(context.Context, []int) (map[int][]string, error) {
+	return nil, nil
+}
+
+func (r *commentsRepo) GetNoteShare(_ context.Context, noteId, userId int) (entity.NoteShare, error) {
+	permission, ok := r.shares[userId]
+	if !ok || noteId != r.note.ID {
+		return entity.NoteShare{}, sql.ErrNoRows
+	}
+	return entity.NoteShare{NoteId: noteId, UserId: userId, OwnerId: r.note.UserId, Permission: permission}, nil
+}
+
+func (r *commentsRepo) GetUserByLogin(_ context.Context, login string) (entity.User, error) {
+	id, ok := r.users[login]
+	if !ok {
+		return entity.User{}, sql.ErrNoRows
+	}
+	return entity.User{ID: id, Login: login}, nil
+}
+
+func (r *commentsRepo) CreateComment(_ context.Context, comment entity.Comment) (int, error) {
+	comment.ID = len(r.comments) + 1
+	r.comments = append(r.comments, comment)
+	return comment.ID, nil
+}
+
+func (r *commentsRepo) GetComment(_ context.Context, id, noteId int) (entity.Comment, error) {
+	if id < 1 || id > len(r.comments) || r.comments[id-1].NoteId != noteId {
+		return entity.Comment{}, sql.ErrNoRows
+	}
+	return r.comments[id-1], nil
+}
+
+func (r *commentsRepo) GetComments(_ context.Context, noteId, afterId, limit int) ([]entity.Comment, error) {
+	var result []entity.Comment
+	for _, comment := range r.comments {
+		if comment.NoteId == noteId && comment.ID > afterId && len(result) < limit {
+			result = append(result, comment)
+		}
+	}
+	return result, nil
+}
+
+func (r *commentsRepo) UpdateComment(_ context.Context, comment entity.Comment) error {
+	r.comments[comment.ID-1] = comment
+	return nil
+}
+
+func (r *commentsRepo) DeleteComment(_ context.Context, id, _ int) error {
+	r.comments[id-1].NoteId = 0
+	return nil
+}
+
+func (r *commentsRepo) CreateNotification(_ context.Context, notification entity.Notification) (int, error) {
+	r.notifications = append(r.notifications, notification)
+	return len(r.notifications), nil
+}
+
+func TestComments(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &commentsRepo{
+		note:   entity.Note{ID: 10, UserId: 1, Title: "Plan"},
+		shares: map[int]string{2: entity.PermissionViewer, 3: entity.PermissionEditor},
+		users:  map[string]int{"owner": 1, "bob": 2, "carol": 3, "stranger": 4},
+	}
+	s := &Service{repo: repo}
+
+	_, err := s.CreateComment(ctx, 10, 4, "let me in")
+	assert.ErrorIs(t, err, entity.ErrNoteNotExists)
+
+	comment, err := s.CreateComment(ctx, 10, 2, "@owner @stranger @nobody @bob what about this?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"owner", "bob"}, comment.Mentions, "unknown users and users without access aren't mentioned")
+
+	require.Len(t, repo.notifications, 1, "authors aren't notified of their own mentions")
+	assert.Equal(t, 1, repo.notifications[0].UserId)
+	assert.Equal(t, entity.NotificationMentioned, repo.notifications[0].Type)
+
+	_, err = s.UpdateComment(ctx, 10, comment.ID, 1, "hijacked")
+	assert.ErrorIs(t, err, entity.ErrCommentForbidden)
+
+	comment, err = s.UpdateComment(ctx, 10, comment.ID, 2, "@owner @carol what about this?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"owner", "carol"}, comment.Mentions)
+	require.Len(t, repo.notifications, 2, "only newly mentioned users are notified")
+	assert.Equal(t, 3, repo.notifications[1].UserId)
+
+	_, err = s.CreateComment(ctx, 10, 3, "second")
+	require.NoError(t, err)
+	_, err = s.CreateComment(ctx, 10, 1, "third")
+	require.NoError(t, err)
+
+	page, err := s.GetComments(ctx, 10, 2, nil, 2)
+	require.NoError(t, err)
+	assert.Len(t, page.Comments, 2)
+	assert.True(t, page.HasMore)
+
+	cursor, err := entity.ParseCommentCursor(page.NextCursor)
+	require.NoError(t, err)
+	page, err = s.GetComments(ctx, 10, 2, &cursor, 2)
+	require.NoError(t, err)
+	require.Len(t, page.Comments, 1)
+	assert.Equal(t, "third", page.Comments[0].Body)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
+
+	assert.ErrorIs(t, s.DeleteComment(ctx, 10, 2, 2), entity.ErrCommentForbidden, "collaborators delete only their comments")
+	require.NoError(t, s.DeleteComment(ctx, 10, 2, 1), "the owner moderates comments of the note")
+	assert.ErrorIs(t, s.DeleteComment(ctx, 10, 2, 1), entity.ErrCommentNotExists)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func (s *Service) CreateItem(ctx context.Context, item entity.Item, userId int) (int, error) {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return 0, err
+	}
+
+	if _, err := s.checkNoteAccess(ctx, item.NoteId, userId, entity.PermissionEditor); err != nil {
+		return 0, err
+	}
+
+	return s.repo.CreateItem(ctx, item)
+}
+
+func (s *Service) GetItems(ctx context.Context, noteId, userId int) ([]entity.Item, error) {
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetItems(ctx, noteId)
+}
+
+func (s *Service) UpdateItem(ctx context.Context, id, noteId int, upd entity.ItemUpdate, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if err := s.checkItemExists(ctx, id, noteId, userId); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateItem(ctx, id, noteId, upd); err != nil {
+		return err
+	}
+
+	if upd.Checked != nil && *upd.Checked {
+		return s.completeNoteIfChecked(ctx, noteId, userId)
+	}
+
+	return nil
+}
+
+// ReorderItems moves items of the note into the order of ids, which must list every item exactly once.
+func (s *Service) ReorderItems(ctx context.Context, noteId int, ids []int, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionEditor); err != nil {
+		return err
+	}
+
+	items, err := s.repo.GetItems(ctx, noteId)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) != len(items) {
+		return entity.ErrInvalidItemsOrder
+	}
+
+	current := make(map[int]struct{}, len(items))
+	for _, item := range items {
+		current[item.ID] = struct{}{}
+	}
+
+	for _, id := range ids {
+		if _, ok := current[id]; !ok {
+			return entity.ErrInvalidItemsOrder
+		}
+		delete(current, id)
+	}
+
+	return s.repo.ReorderItems(ctx, noteId, ids)
+}
+
+func (s *Service) DeleteItem(ctx context.Context, id, noteId, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if err := s.checkItemExists(ctx, id, noteId, userId); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteItem(ctx, id, noteId); err != nil {
+		return err
+	}
+
+	return s.completeNoteIfChecked(ctx, noteId, userId)
+}
+
+func (s *Service) checkItemExists(ctx context.Context, id, noteId, userId int) error {
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionEditor); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetItemById(ctx, id, noteId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrItemNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// completeNoteIfChecked marks the note as done once all of its items are checked
+// when auto completion is enabled in config.
+func (s *Service) completeNoteIfChecked(ctx context.Context, noteId, userId int) error {
+	if !s.autoComplete {
+		return nil
+	}
+
+	items, err := s.repo.GetItems(ctx, noteId)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 || entity.ItemsProgress(items) < 100 {
+		return nil
+	}
+
+	note, err := s.GetNoteById(ctx, noteId, userId)
+	if err != nil {
+		return err
+	}
+
+	if note.Status == entity.StatusDone {
+		return nil
+	}
+
+	return s.UpdateNote(ctx, noteId, entity.NoteUpdate{Status: entity.StatusDone}, userId)
+}
+
+// copyItems copies the checklist of the note to another note with every item unchecked.
+func (s *Service) copyItems(ctx context.Context, fromNoteId, toNoteId int) error {
+	items, err := s.repo.GetItems(ctx, fromNoteId)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if _, err = s.repo.CreateItem(ctx, entity.Item{NoteId: toNoteId, Title: item.Title}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
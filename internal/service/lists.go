@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func (s *Service) CreateList(ctx context.Context, list entity.List) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetListByName(ctx, list.Name, list.UserId); err == nil {
+		return entity.ErrListExists
+	}
+
+	_, err := s.repo.CreateList(ctx, list)
+	return err
+}
+
+func (s *Service) GetLists(ctx context.Context, userId int) ([]entity.List, error) {
+	return s.repo.GetLists(ctx, userId)
+}
+
+func (s *Service) GetListById(ctx context.Context, id, userId int) (entity.List, error) {
+	list, err := s.repo.GetListById(ctx, id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.List{}, entity.ErrListNotExists
+		}
+		return entity.List{}, err
+	}
+
+	return list, nil
+}
+
+func (s *Service) UpdateList(ctx context.Context, id int, name string, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	list, err := s.GetListById(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	if list.Name == name {
+		return nil
+	}
+
+	if _, err = s.repo.GetListByName(ctx, name, userId); err == nil {
+		return entity.ErrListExists
+	}
+
+	return s.repo.UpdateList(ctx, id, userId, name)
+}
+
+// DeleteList deletes the list together with its notes when cascade is set,
+// otherwise the notes are moved to the inbox.
+func (s *Service) DeleteList(ctx context.Context, id, userId int, cascade bool) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if _, err := s.GetListById(ctx, id, userId); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteList(ctx, id, userId, cascade)
+}
+
+// MoveNotes moves notes into the list, zero listId moves them to the inbox.
+func (s *Service) MoveNotes(ctx context.Context, noteIds []int, listId, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if err := s.checkListExists(ctx, listId, userId); err != nil {
+		return err
+	}
+
+	for _, id := range noteIds {
+		if !s.isNoteExists(ctx, id, userId) {
+			return entity.ErrNoteNotExists
+		}
+	}
+
+	return s.repo.MoveNotes(ctx, noteIds, listId, userId)
+}
+
+// checkListExists checks that the list belongs to the user, zero id stands for the inbox.
+func (s *Service) checkListExists(ctx context.Context, listId, userId int) error {
+	if listId == 0 {
+		return nil
+	}
+
+	_, err := s.GetListById(ctx, listId, userId)
+	return err
+}
@@ -2,33 +2,41 @@ package service
 
 import (
 	"context"
-	"database/sql"
-	"errors"
-	"time"
 
 	"github.com/pintoter/todo-list/internal/entity"
 )
 
 func (s *Service) CreateNote(ctx context.Context, note entity.Note) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if err := s.checkEmailVerified(ctx, RequireVerifiedEmailForNotes, note.UserId); err != nil {
+		return err
+	}
+
 	if s.isNoteExists(ctx, note.Title, note.UserId) {
 		return entity.ErrNoteExists
 	}
 
-	_, err := s.repo.CreateNote(ctx, note)
-	return err
-}
+	if note.ListId != nil {
+		if err := s.checkListExists(ctx, *note.ListId, note.UserId); err != nil {
+			return err
+		}
+	}
 
-func (s *Service) GetNoteById(ctx context.Context, id, userId int) (entity.Note, error) {
-	note, err := s.repo.GetNoteById(ctx, id, userId)
+	id, err := s.repo.CreateNote(ctx, note)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return entity.Note{}, entity.ErrNoteNotExists
-		} else {
-			return entity.Note{}, err
-		}
+		return err
 	}
 
-	return note, nil
+	note.ID = id
+	return s.recordRevision(ctx, entity.RevisionCreate, entity.Note{}, note, note.UserId)
+}
+
+// GetNoteById returns the note of the user or a note shared with the user.
+func (s *Service) GetNoteById(ctx context.Context, id, userId int) (entity.Note, error) {
+	return s.getNote(ctx, id, userId, entity.PermissionViewer)
 }
 
 func (s *Service) GetNotes(ctx context.Context, userId int) ([]entity.Note, error) {
@@ -37,39 +45,129 @@ func (s *Service) GetNotes(ctx context.Context, userId int) ([]entity.Note, erro
 		return nil, err
 	}
 
-	return notes, nil
+	if err = s.attachTags(ctx, notes); err != nil {
+		return nil, err
+	}
+
+	return notes, s.attachCommentCounts(ctx, notes)
 }
 
-func (s *Service) GetNotesExtended(ctx context.Context, limit, offset int, status string, date time.Time, userId int) ([]entity.Note, error) {
-	notes, err := s.repo.GetNotesExtended(ctx, limit, offset, status, date, userId)
+// GetNotesExtended returns a page of notes selected either by offset or by the cursor of the filter.
+// One note more than limit is fetched to find out whether there are notes beyond the page.
+func (s *Service) GetNotesExtended(ctx context.Context, limit, offset int, filter entity.NoteFilter, userId int) (entity.NotesPage, error) {
+	if filter.Cursor != nil && filter.Cursor.Order != filter.Order() {
+		return entity.NotesPage{}, entity.ErrInvalidCursor
+	}
+
+	var notes []entity.Note
+	var err error
+	if filter.Query != "" {
+		notes, err = s.repo.SearchNotes(ctx, limit+1, offset, filter, userId)
+	} else {
+		notes, err = s.repo.GetNotesExtended(ctx, limit+1, offset, filter, userId)
+	}
 	if err != nil {
-		return nil, err
+		return entity.NotesPage{}, err
+	}
+
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	hasBeyond := len(notes) > limit
+	if hasBeyond && backward {
+		notes = notes[len(notes)-limit:]
+	} else if hasBeyond {
+		notes = notes[:limit]
+	}
+
+	page := entity.NotesPage{Notes: notes}
+	if page.Total, err = s.repo.CountNotes(ctx, filter, userId); err != nil {
+		return entity.NotesPage{}, err
+	}
+
+	if len(notes) > 0 {
+		page.HasMore = hasBeyond || backward
+		if page.HasMore {
+			page.NextCursor = entity.NewNoteCursor(notes[len(notes)-1], filter, false).String()
+		}
+
+		if (backward && hasBeyond) || (!backward && (offset > 0 || filter.Cursor != nil)) {
+			page.PrevCursor = entity.NewNoteCursor(notes[0], filter, true).String()
+		}
 	}
 
-	return notes, nil
+	if err = s.attachTags(ctx, page.Notes); err != nil {
+		return entity.NotesPage{}, err
+	}
+
+	return page, s.attachCommentCounts(ctx, page.Notes)
 }
 
-func (s *Service) UpdateNote(ctx context.Context, id int, title, description, status string, userId int) error {
-	if !s.isNoteExists(ctx, id, userId) {
-		return entity.ErrNoteNotExists
+// UpdateNote changes the note of the user or a note shared with the user as an editor,
+// only the owner can move the note between lists since lists are the owner's.
+func (s *Service) UpdateNote(ctx context.Context, id int, upd entity.NoteUpdate, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	note, err := s.getNote(ctx, id, userId, entity.PermissionEditor)
+	if err != nil {
+		return err
 	}
+	ownerId := note.UserId
 
-	if title != "" && s.isNoteExists(ctx, title, userId) {
+	if upd.Title != "" && s.isNoteExists(ctx, upd.Title, ownerId) {
 		return entity.ErrNoteExists
 	}
 
-	return s.repo.UpdateNote(ctx, id, userId, title, description, status)
+	if upd.ListId != nil {
+		// lists of a workspace are shared by its members, personal lists are the owner's
+		if _, ok := entity.WorkspaceFromContext(ctx); !ok && ownerId != userId {
+			return entity.ErrNoteForbidden
+		}
+
+		if err = s.checkListExists(ctx, *upd.ListId, userId); err != nil {
+			return err
+		}
+	}
+
+	if err = s.repo.UpdateNote(ctx, id, ownerId, upd); err != nil {
+		return err
+	}
+
+	updated, err := s.GetNoteById(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	if err = s.recordRevision(ctx, entity.RevisionUpdate, note, updated, userId); err != nil {
+		return err
+	}
+
+	// marking a recurring note as done spawns its next occurrence
+	if updated.Status == entity.StatusDone && note.Status != entity.StatusDone {
+		return s.spawnNextOccurrence(ctx, updated)
+	}
+
+	return nil
 }
 
+// DeleteNoteById moves the note to the trash, collaborators can't delete notes shared with them.
 func (s *Service) DeleteNoteById(ctx context.Context, id, userId int) error {
-	if s.isNoteExists(ctx, id, userId) {
-		return s.repo.DeleteNoteById(ctx, id, userId)
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if _, err := s.checkNoteAccess(ctx, id, userId, entity.PermissionOwner); err != nil {
+		return err
 	}
 
-	return entity.ErrNoteNotExists
+	return s.repo.DeleteNoteById(ctx, id, userId)
 }
 
 func (s *Service) DeleteNotes(ctx context.Context, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
 	err := s.repo.DeleteNotes(ctx, userId)
 	if err != nil {
 		return err
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notesRepo struct {
+	repository.Repository
+
+	lists []entity.List
+	notes []entity.Note
+}
+
+func (r *notesRepo) GetListById(_ context.Context, id, userId int) (entity.List, error) {
+	for _, list := range r.lists {
+		if list.ID == id && list.UserId == userId {
+			return list, nil
+		}
+	}
+	return entity.List{}, sql.ErrNoRows
+}
+
+func (r *notesRepo) GetNoteByTitle(_ context.Context, title string, userId int) (entity.Note, error) {
+	for _, note := range r.notes {
+		if note.Title == title && note.UserId == userId {
+			return note, nil
+		}
+	}
+	return entity.Note{}, sql.ErrNoRows
+}
+
+func (r *notesRepo) CreateNote(_ context.Context, note entity.Note) (int, error) {
+	note.ID = len(r.notes) + 1
+	r.notes = append(r.notes, note)
+	return note.ID, nil
+}
+
+func (r *notesRepo) CreateRevision(context.Context, entity.Revision) (int, error) {
+	return 1, nil
+}
+
+func TestCreateNoteInList(t *testing.T) {
+	repo := &notesRepo{lists: []entity.List{{ID: 3, UserId: 1, Name: "Work"}, {ID: 4, UserId: 2, Name: "Home"}}}
+	s := &Service{repo: repo}
+	ctx := context.Background()
+
+	listId := 3
+	require.NoError(t, s.CreateNote(ctx, entity.Note{UserId: 1, Title: "Report", ListId: &listId}))
+	require.Len(t, repo.notes, 1)
+	require.NotNil(t, repo.notes[0].ListId)
+	assert.Equal(t, 3, *repo.notes[0].ListId)
+
+	for _, id := range []int{4, 5} {
+		listId := id
+		err := s.CreateNote(ctx, entity.Note{UserId: 1, Title: "Foreign", ListId: &listId})
+		assert.ErrorIs(t, err, entity.ErrListNotExists, "list %d", id)
+	}
+	assert.Len(t, repo.notes, 1)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// GetNotifications returns the latest notifications of the user, only unread ones when unread is set.
+func (s *Service) GetNotifications(ctx context.Context, userId int, unread bool, limit int) ([]entity.Notification, error) {
+	return s.repo.GetNotifications(ctx, userId, unread, limit)
+}
+
+// ReadNotification marks the notification as read.
+func (s *Service) ReadNotification(ctx context.Context, id, userId int) error {
+	if err := s.repo.ReadNotification(ctx, id, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrNotificationNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ReadNotifications marks all notifications of the user as read.
+func (s *Service) ReadNotifications(ctx context.Context, userId int) error {
+	return s.repo.ReadNotifications(ctx, userId)
+}
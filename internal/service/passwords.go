@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/logger"
+	"github.com/pintoter/todo-list/pkg/mailer"
+)
+
+// ForgotPassword emails a password reset token to the user with the email. Unknown emails
+// are ignored without an error, so they can't be told apart from registered ones.
+func (s *Service) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := auth.NewToken()
+	if err != nil {
+		return err
+	}
+
+	reset := entity.PasswordReset{
+		UserId:    user.ID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(s.passwordResetTTL),
+	}
+
+	if _, err = s.repo.CreatePasswordReset(ctx, reset); err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nuse the token below to set a new password, it expires in %s:\n\n%s\n\n"+
+			"If you didn't ask to reset your password, ignore this email.\n", user.Login, s.passwordResetTTL, token),
+	}
+
+	// failed delivery isn't reported to the client either
+	if err = s.mailer.Send(ctx, msg); err != nil {
+		logger.ErrorKV(ctx, "Failed send password reset email", "user_id", user.ID, "err", err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password by the reset token, the user is logged out everywhere.
+func (s *Service) ResetPassword(ctx context.Context, token, password string) error {
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	userId, err := s.repo.ResetPassword(ctx, auth.HashToken(token), hashedPassword)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	logger.InfoKV(ctx, "Password reset", "user_id", userId)
+
+	return s.denylist.RevokeUserTokens(ctx, userId, time.Now(), s.accessTokenTTL)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/denylist"
+	"github.com/pintoter/todo-list/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resetsRepo struct {
+	repository.Repository
+
+	resets   map[string]entity.PasswordReset
+	password string
+}
+
+func (r *resetsRepo) GetUserByEmail(_ context.Context, email string) (entity.User, error) {
+	if email != "user@example.com" {
+		return entity.User{}, sql.ErrNoRows
+	}
+	return entity.User{ID: 1, Login: "user", Email: email}, nil
+}
+
+func (r *resetsRepo) CreatePasswordReset(_ context.Context, reset entity.PasswordReset) (int, error) {
+	r.resets[reset.TokenHash] = reset
+	return len(r.resets), nil
+}
+
+func (r *resetsRepo) ResetPassword(_ context.Context, tokenHash, password string) (int, error) {
+	reset, ok := r.resets[tokenHash]
+	if !ok || reset.UsedAt != nil || !reset.ExpiresAt.After(time.Now()) {
+		return 0, sql.ErrNoRows
+	}
+
+	now := time.Now()
+	reset.UsedAt = &now
+	r.resets[tokenHash] = reset
+	r.password = password
+
+	return reset.UserId, nil
+}
+
+var resetTokenRegexp = regexp.MustCompile(`(?m)^[0-9a-f]{64}\r?$`)
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	files, err := mailer.NewFile(dir, "todo@example.com")
+	require.NoError(t, err)
+
+	repo := &resetsRepo{resets: make(map[string]entity.PasswordReset)}
+	revocations := denylist.NewMemory()
+	s := &Service{
+		repo:             repo,
+		hasher:           plainHasher{},
+		denylist:         revocations,
+		mailer:           files,
+		accessTokenTTL:   time.Minute,
+		passwordResetTTL: time.Hour,
+	}
+
+	assert.NoError(t, s.ForgotPassword(ctx, "unknown@example.com"))
+	mails, _ := os.ReadDir(dir)
+	assert.Empty(t, mails, "unknown email must not get a mail")
+
+	require.NoError(t, s.ForgotPassword(ctx, "user@example.com"))
+	mails, _ = os.ReadDir(dir)
+	require.Len(t, mails, 1)
+
+	mail, err := os.ReadFile(filepath.Join(dir, mails[0].Name()))
+	require.NoError(t, err)
+	token := string(resetTokenRegexp.Find(mail))[:64]
+
+	_, stored := repo.resets[token]
+	assert.False(t, stored, "reset token must not be stored in plaintext")
+	_, stored = repo.resets[auth.HashToken(token)]
+	assert.True(t, stored)
+
+	assert.ErrorIs(t, s.ResetPassword(ctx, "wrong", "new password"), entity.ErrInvalidResetToken)
+
+	require.NoError(t, s.ResetPassword(ctx, token, "new password"))
+	assert.Equal(t, "new password", repo.password)
+
+	revokedBefore, _ := revocations.UserTokensRevokedBefore(ctx, 1)
+	assert.False(t, revokedBefore.IsZero(), "access tokens of the user must be revoked")
+
+	assert.ErrorIs(t, s.ResetPassword(ctx, token, "other password"), entity.ErrInvalidResetToken, "reset token must be single use")
+}
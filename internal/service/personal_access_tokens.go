@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/auth"
+)
+
+// personalAccessTokenPrefix tells personal access tokens apart from JWTs and makes them recognizable
+// by secret scanners.
+const personalAccessTokenPrefix = "todo_pat_"
+
+// IsPersonalAccessToken reports whether the bearer token is a personal access token rather than a JWT.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, personalAccessTokenPrefix)
+}
+
+// CreatePersonalAccessToken issues a token limited to the scopes, it never expires when expiresAt is nil.
+// The token is returned only once since only its hash is stored.
+func (s *Service) CreatePersonalAccessToken(ctx context.Context, userId int, name string, scopes []string,
+	expiresAt *time.Time) (string, entity.PersonalAccessToken, error) {
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return "", entity.PersonalAccessToken{}, err
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", entity.PersonalAccessToken{}, entity.ErrInvalidExpiresAt
+	}
+
+	secret, err := auth.NewToken()
+	if err != nil {
+		return "", entity.PersonalAccessToken{}, err
+	}
+	token := personalAccessTokenPrefix + secret
+
+	pat := entity.PersonalAccessToken{
+		UserId:    userId,
+		Name:      name,
+		TokenHash: auth.HashToken(token),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+
+	pat.ID, err = s.repo.CreatePersonalAccessToken(ctx, pat)
+	if err != nil {
+		return "", entity.PersonalAccessToken{}, err
+	}
+
+	return token, pat, nil
+}
+
+func (s *Service) GetPersonalAccessTokens(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error) {
+	return s.repo.GetPersonalAccessTokens(ctx, userId)
+}
+
+func (s *Service) DeletePersonalAccessToken(ctx context.Context, id, userId int) error {
+	err := s.repo.DeletePersonalAccessToken(ctx, id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrTokenNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CheckPersonalAccessToken returns the personal access token of the request recording its use,
+// unknown and expired tokens are rejected.
+func (s *Service) CheckPersonalAccessToken(ctx context.Context, token string) (entity.PersonalAccessToken, error) {
+	pat, err := s.repo.UsePersonalAccessToken(ctx, auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.PersonalAccessToken{}, entity.ErrTokenNotExists
+		}
+		return entity.PersonalAccessToken{}, err
+	}
+
+	return pat, nil
+}
+
+// normalizeScopes rejects unknown scopes and returns the known ones in a stable order without duplicates,
+// at least one scope is required.
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, entity.ErrInvalidScope
+	}
+
+	requested := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		requested[scope] = true
+	}
+
+	result := make([]string, 0, len(requested))
+	for _, scope := range entity.Scopes {
+		if requested[scope] {
+			result = append(result, scope)
+		}
+	}
+
+	if len(result) != len(requested) {
+		return nil, entity.ErrInvalidScope
+	}
+
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/pintoter/todo-list/pkg/denylist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accessTokensRepo struct {
+	repository.Repository
+
+	tokens []entity.PersonalAccessToken
+}
+
+func (r *accessTokensRepo) CreatePersonalAccessToken(_ context.Context, token entity.PersonalAccessToken) (int, error) {
+	token.ID = len(r.tokens) + 1
+	r.tokens = append(r.tokens, token)
+	return token.ID, nil
+}
+
+func (r *accessTokensRepo) UsePersonalAccessToken(_ context.Context, tokenHash string) (entity.PersonalAccessToken, error) {
+	for i, token := range r.tokens {
+		if token.TokenHash == tokenHash && (token.ExpiresAt == nil || token.ExpiresAt.After(time.Now())) {
+			now := time.Now()
+			r.tokens[i].LastUsedAt = &now
+			return r.tokens[i], nil
+		}
+	}
+	return entity.PersonalAccessToken{}, sql.ErrNoRows
+}
+
+func (r *accessTokensRepo) GetUserByID(_ context.Context, id int) (entity.User, error) {
+	return entity.User{ID: id}, nil
+}
+
+func (r *accessTokensRepo) DeleteSessions(context.Context, int) error {
+	return nil
+}
+
+func (r *accessTokensRepo) DeletePersonalAccessTokens(_ context.Context, userId int) error {
+	kept := r.tokens[:0]
+	for _, token := range r.tokens {
+		if token.UserId != userId {
+			kept = append(kept, token)
+		}
+	}
+	r.tokens = kept
+	return nil
+}
+
+func TestPersonalAccessTokens(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &accessTokensRepo{}
+	s := &Service{repo: repo}
+
+	_, _, err := s.CreatePersonalAccessToken(ctx, 1, "ci", nil, nil)
+	assert.ErrorIs(t, err, entity.ErrInvalidScope)
+
+	_, _, err = s.CreatePersonalAccessToken(ctx, 1, "ci", []string{"notes:admin"}, nil)
+	assert.ErrorIs(t, err, entity.ErrInvalidScope)
+
+	past := time.Now().Add(-time.Hour)
+	_, _, err = s.CreatePersonalAccessToken(ctx, 1, "ci", []string{entity.ScopeNotesRead}, &past)
+	assert.ErrorIs(t, err, entity.ErrInvalidExpiresAt)
+
+	token, pat, err := s.CreatePersonalAccessToken(ctx, 1, "ci",
+		[]string{entity.ScopeNotesWrite, entity.ScopeNotesRead, entity.ScopeNotesWrite}, nil)
+	require.NoError(t, err)
+	assert.True(t, IsPersonalAccessToken(token))
+	assert.Equal(t, []string{entity.ScopeNotesRead, entity.ScopeNotesWrite}, pat.Scopes)
+	assert.NotEqual(t, token, repo.tokens[0].TokenHash, "token must not be stored in plaintext")
+
+	got, err := s.CheckPersonalAccessToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.UserId)
+	assert.NotNil(t, repo.tokens[0].LastUsedAt)
+
+	_, err = s.CheckPersonalAccessToken(ctx, token+"0")
+	assert.ErrorIs(t, err, entity.ErrTokenNotExists)
+
+	repo.tokens[0].ExpiresAt = &past
+	_, err = s.CheckPersonalAccessToken(ctx, token)
+	assert.ErrorIs(t, err, entity.ErrTokenNotExists)
+}
+
+func TestPersonalAccessTokens_RevokedEverywhere(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &accessTokensRepo{}
+	s := &Service{repo: repo, denylist: denylist.NewMemory(), accessTokenTTL: time.Minute}
+
+	create := func(userId int) string {
+		token, _, err := s.CreatePersonalAccessToken(ctx, userId, "ci", []string{entity.ScopeNotesRead}, nil)
+		require.NoError(t, err)
+		return token
+	}
+
+	mine, other := create(1), create(2)
+
+	require.NoError(t, s.DeleteSessions(ctx, 1), "log out everywhere")
+	_, err := s.CheckPersonalAccessToken(ctx, mine)
+	assert.ErrorIs(t, err, entity.ErrTokenNotExists)
+
+	_, err = s.CheckPersonalAccessToken(ctx, other)
+	assert.NoError(t, err, "tokens of other users are kept")
+
+	require.NoError(t, s.RevokeUserTokens(ctx, 2), "admin revokes all tokens of the user")
+	_, err = s.CheckPersonalAccessToken(ctx, other)
+	assert.ErrorIs(t, err, entity.ErrTokenNotExists)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// maxNoteTitleLength is the length of the title column of notes.
+const maxNoteTitleLength = 80
+
+// occurrenceSuffix matches the date titles of spawned occurrences end with.
+var occurrenceSuffix = regexp.MustCompile(` \(\d{4}-\d{2}-\d{2}\)$`)
+
+// GetNextOccurrences previews up to n next occurrences of the recurring note.
+func (s *Service) GetNextOccurrences(ctx context.Context, id, n, userId int) ([]time.Time, error) {
+	note, err := s.GetNoteById(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if note.Recurrence == nil {
+		return nil, entity.ErrNoRecurrence
+	}
+
+	from, loc, err := s.occurrenceAnchor(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	return occurrences(*note.Recurrence, from, loc, n), nil
+}
+
+// spawnNextOccurrence creates the note following the completed recurring note.
+func (s *Service) spawnNextOccurrence(ctx context.Context, note entity.Note) error {
+	if note.Recurrence == nil {
+		return nil
+	}
+
+	rule := *note.Recurrence
+	if rule.Count == 1 {
+		return nil
+	}
+
+	from, loc, err := s.occurrenceAnchor(ctx, note)
+	if err != nil {
+		return err
+	}
+
+	rule = withAnchorDay(rule, from, loc)
+	next := nextOccurrence(rule, from, loc)
+	if rule.Until != nil && next.After(*rule.Until) {
+		return nil
+	}
+
+	if rule.Count > 0 {
+		rule.Count--
+	}
+
+	local := next.In(loc)
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	// titles are unique, so the occurrence is named after its date, the occurrence exists already
+	// when the note is completed again after being reopened
+	title := occurrenceTitle(note.Title, date)
+	if s.isNoteExists(ctx, title, note.UserId) {
+		return nil
+	}
+
+	spawned := entity.Note{
+		UserId:      note.UserId,
+		Title:       title,
+		Description: note.Description,
+		Date:        date,
+		Status:      entity.StatusNotDone,
+		Priority:    note.Priority,
+		Recurrence:  &rule,
+		ListId:      note.ListId,
+		Tags:        note.Tags,
+	}
+	if note.DueAt != nil {
+		spawned.DueAt = &next
+	}
+
+	spawned.ID, err = s.repo.CreateNote(ctx, spawned)
+	if err != nil {
+		return err
+	}
+
+	if err = s.recordRevision(ctx, entity.RevisionCreate, entity.Note{}, spawned, spawned.UserId); err != nil {
+		return err
+	}
+
+	return s.copyItems(ctx, note.ID, spawned.ID)
+}
+
+// occurrenceAnchor returns the current occurrence of the note and the zone its rule is evaluated in.
+// Notes with due_at repeat in the user's time zone, date-only notes repeat by calendar days.
+func (s *Service) occurrenceAnchor(ctx context.Context, note entity.Note) (time.Time, *time.Location, error) {
+	if note.DueAt == nil {
+		return time.Date(note.Date.Year(), note.Date.Month(), note.Date.Day(), 0, 0, 0, 0, time.UTC), time.UTC, nil
+	}
+
+	loc, err := s.GetUserLocation(ctx, note.UserId)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	return *note.DueAt, loc, nil
+}
+
+// occurrences returns up to n occurrences after from, honouring rule's count and until.
+func occurrences(rule entity.Recurrence, from time.Time, loc *time.Location, n int) []time.Time {
+	if rule.Count > 0 && n > rule.Count-1 {
+		n = rule.Count - 1
+	}
+
+	rule = withAnchorDay(rule, from, loc)
+
+	result := make([]time.Time, 0, n)
+	for i := 0; i < n; i++ {
+		from = nextOccurrence(rule, from, loc)
+		if rule.Until != nil && from.After(*rule.Until) {
+			break
+		}
+		result = append(result, from)
+	}
+
+	return result
+}
+
+func nextOccurrence(rule entity.Recurrence, from time.Time, loc *time.Location) time.Time {
+	interval := rule.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	local := from.In(loc)
+	switch rule.Frequency {
+	case entity.FrequencyWeekly:
+		if len(rule.Weekdays) == 0 {
+			return local.AddDate(0, 0, 7*interval)
+		}
+		return nextWeekday(rule.Weekdays, interval, local)
+	case entity.FrequencyMonthly:
+		return addMonths(local, interval, rule.AnchorDay)
+	case entity.FrequencyYearly:
+		return addMonths(local, 12*interval, rule.AnchorDay)
+	default:
+		return local.AddDate(0, 0, interval)
+	}
+}
+
+// nextWeekday finds the closest day from the weekdays set in weeks that are multiple of interval
+// counting from the week of from.
+func nextWeekday(weekdays []string, interval int, from time.Time) time.Time {
+	days := make(map[time.Weekday]struct{}, len(weekdays))
+	for _, day := range weekdays {
+		days[entity.Weekdays[day]] = struct{}{}
+	}
+
+	start := weekStart(from)
+	for i := 1; i <= 7*(interval+1); i++ {
+		next := from.AddDate(0, 0, i)
+		if _, ok := days[next.Weekday()]; !ok {
+			continue
+		}
+
+		if (daysBetween(start, weekStart(next))/7)%interval == 0 {
+			return next
+		}
+	}
+
+	return from.AddDate(0, 0, 7*interval)
+}
+
+// withAnchorDay pins monthly and yearly series to the day of month of from unless the rule is pinned already,
+// so an occurrence clamped to the end of a shorter month doesn't shift the following ones.
+func withAnchorDay(rule entity.Recurrence, from time.Time, loc *time.Location) entity.Recurrence {
+	if rule.AnchorDay == 0 && (rule.Frequency == entity.FrequencyMonthly || rule.Frequency == entity.FrequencyYearly) {
+		rule.AnchorDay = from.In(loc).Day()
+	}
+
+	return rule
+}
+
+// occurrenceTitle is the title of the series followed by the date of the occurrence.
+func occurrenceTitle(title string, date time.Time) string {
+	suffix := " (" + date.Format("2006-01-02") + ")"
+
+	base := []rune(occurrenceSuffix.ReplaceAllString(title, ""))
+	if limit := maxNoteTitleLength - len(suffix); len(base) > limit {
+		base = base[:limit]
+	}
+
+	return string(base) + suffix
+}
+
+// addMonths adds months keeping the anchor day of month, or the day of t when it's zero,
+// clamping it to the last day of shorter months.
+func addMonths(t time.Time, months, anchorDay int) time.Time {
+	year, month, day := t.Date()
+	if anchorDay > 0 {
+		day = anchorDay
+	}
+
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(year, month+time.Month(months), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+func daysBetween(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
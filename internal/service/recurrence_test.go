@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+
+	tests := []struct {
+		name string
+		rule entity.Recurrence
+		from time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		{
+			name: "Daily",
+			rule: entity.Recurrence{Frequency: entity.FrequencyDaily},
+			from: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "DailyWithIntervalOverDST",
+			rule: entity.Recurrence{Frequency: entity.FrequencyDaily, Interval: 2},
+			from: time.Date(2024, 3, 30, 17, 0, 0, 0, berlin),
+			loc:  berlin,
+			want: time.Date(2024, 4, 1, 17, 0, 0, 0, berlin),
+		},
+		{
+			name: "Weekly",
+			rule: entity.Recurrence{Frequency: entity.FrequencyWeekly},
+			from: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			want: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "WeeklyOnWeekdays",
+			rule: entity.Recurrence{Frequency: entity.FrequencyWeekly, Weekdays: []string{"mo", "fr"}},
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), // monday
+			loc:  time.UTC,
+			want: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "BiweeklyOnWeekdays",
+			rule: entity.Recurrence{Frequency: entity.FrequencyWeekly, Interval: 2, Weekdays: []string{"mo", "fr"}},
+			from: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), // friday
+			loc:  time.UTC,
+			want: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "MonthlyClampsToLastDay",
+			rule: entity.Recurrence{Frequency: entity.FrequencyMonthly},
+			from: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "YearlyFromLeapDay",
+			rule: entity.Recurrence{Frequency: entity.FrequencyYearly},
+			from: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			want: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextOccurrence(tt.rule, tt.from, tt.loc)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		rule entity.Recurrence
+		n    int
+		want int
+	}{
+		{
+			name: "Unlimited",
+			rule: entity.Recurrence{Frequency: entity.FrequencyDaily},
+			n:    5,
+			want: 5,
+		},
+		{
+			name: "LimitedByCount",
+			rule: entity.Recurrence{Frequency: entity.FrequencyDaily, Count: 3},
+			n:    5,
+			want: 2,
+		},
+		{
+			name: "LimitedByUntil",
+			rule: entity.Recurrence{Frequency: entity.FrequencyDaily, Until: &until},
+			n:    5,
+			want: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := occurrences(tt.rule, from, time.UTC, tt.n)
+			assert.Len(t, got, tt.want)
+		})
+	}
+}
+
+func TestOccurrencesKeepAnchorDay(t *testing.T) {
+	tests := []struct {
+		name string
+		rule entity.Recurrence
+		from time.Time
+		want []time.Time
+	}{
+		{
+			name: "Monthly",
+			rule: entity.Recurrence{Frequency: entity.FrequencyMonthly},
+			from: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "MonthlyFromClampedOccurrence",
+			rule: entity.Recurrence{Frequency: entity.FrequencyMonthly, AnchorDay: 31},
+			from: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "YearlyFromLeapDay",
+			rule: entity.Recurrence{Frequency: entity.FrequencyYearly},
+			from: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+				time.Date(2027, 2, 28, 0, 0, 0, 0, time.UTC),
+				time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := occurrences(tt.rule, tt.from, time.UTC, len(tt.want))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type recurrenceRepo struct {
+	repository.Repository
+
+	notes []entity.Note
+}
+
+func (r *recurrenceRepo) GetNoteByTitle(_ context.Context, title string, userId int) (entity.Note, error) {
+	for _, note := range r.notes {
+		if note.Title == title && note.UserId == userId {
+			return note, nil
+		}
+	}
+	return entity.Note{}, sql.ErrNoRows
+}
+
+func (r *recurrenceRepo) CreateNote(_ context.Context, note entity.Note) (int, error) {
+	note.ID = len(r.notes) + 1
+	r.notes = append(r.notes, note)
+	return note.ID, nil
+}
+
+func (r *recurrenceRepo) CreateRevision(context.Context, entity.Revision) (int, error) {
+	return 1, nil
+}
+
+func (r *recurrenceRepo) GetItems(context.Context, int) ([]entity.Item, error) {
+	return nil, nil
+}
+
+func TestSpawnNextOccurrence(t *testing.T) {
+	first := entity.Note{
+		ID:         1,
+		UserId:     1,
+		Title:      "Pay rent",
+		Date:       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Status:     entity.StatusDone,
+		Recurrence: &entity.Recurrence{Frequency: entity.FrequencyMonthly},
+	}
+	repo := &recurrenceRepo{notes: []entity.Note{first}}
+	s := &Service{repo: repo}
+	ctx := context.Background()
+
+	require.NoError(t, s.spawnNextOccurrence(ctx, first))
+	require.Len(t, repo.notes, 2)
+	second := repo.notes[1]
+	assert.Equal(t, "Pay rent (2024-02-29)", second.Title)
+	assert.Equal(t, 31, second.Recurrence.AnchorDay)
+
+	// completing the note again after reopening it doesn't spawn a duplicate
+	require.NoError(t, s.spawnNextOccurrence(ctx, first))
+	assert.Len(t, repo.notes, 2)
+
+	require.NoError(t, s.spawnNextOccurrence(ctx, second))
+	require.Len(t, repo.notes, 3)
+	assert.Equal(t, "Pay rent (2024-03-31)", repo.notes[2].Title)
+	assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), repo.notes[2].Date)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// GetNoteHistory returns revisions of the note, the latest first, anyone who can see the note can read them.
+func (s *Service) GetNoteHistory(ctx context.Context, noteId, userId int) ([]entity.Revision, error) {
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetRevisions(ctx, noteId)
+}
+
+// RevertNote restores the note to the state of the revision, the revert is recorded as a new revision.
+// Only the owner can revert since a revert may move the note between lists and rename it among the owner's notes,
+// collaborators can't. Notes of a deleted list are reverted to the inbox.
+func (s *Service) RevertNote(ctx context.Context, noteId, revisionId, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	current, err := s.getNote(ctx, noteId, userId, entity.PermissionOwner)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.getRevision(ctx, revisionId, noteId)
+	if err != nil {
+		return err
+	}
+
+	target := revision.Snapshot
+	target.ID, target.UserId = current.ID, current.UserId
+
+	if target.Title != current.Title && s.isNoteExists(ctx, target.Title, userId) {
+		return entity.ErrNoteExists
+	}
+
+	if target.ListId != nil && s.checkListExists(ctx, *target.ListId, userId) != nil {
+		target.ListId = nil
+	}
+
+	if err = s.repo.ReplaceNote(ctx, target); err != nil {
+		return err
+	}
+
+	reverted, err := s.GetNoteById(ctx, noteId, userId)
+	if err != nil {
+		return err
+	}
+
+	return s.recordRevision(ctx, entity.RevisionRevert, current, reverted, userId)
+}
+
+// DiffRevisions compares states of the note after two revisions field by field, anyone who can see the note can do it.
+func (s *Service) DiffRevisions(ctx context.Context, noteId, fromId, toId, userId int) (map[string]entity.Change, error) {
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	from, err := s.getRevision(ctx, fromId, noteId)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.getRevision(ctx, toId, noteId)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.DiffNotes(from.Snapshot, to.Snapshot), nil
+}
+
+// recordRevision saves the change of the note made by the user, updates without changes aren't recorded.
+func (s *Service) recordRevision(ctx context.Context, action string, from, to entity.Note, userId int) error {
+	changes := entity.DiffNotes(from, to)
+	if len(changes) == 0 && action != entity.RevisionCreate {
+		return nil
+	}
+
+	_, err := s.repo.CreateRevision(ctx, entity.Revision{
+		NoteId:   to.ID,
+		UserId:   userId,
+		Action:   action,
+		Changes:  changes,
+		Snapshot: to,
+	})
+	return err
+}
+
+func (s *Service) getRevision(ctx context.Context, id, noteId int) (entity.Revision, error) {
+	revision, err := s.repo.GetRevisionById(ctx, id, noteId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Revision{}, entity.ErrRevisionNotExists
+		}
+		return entity.Revision{}, err
+	}
+
+	return revision, nil
+}
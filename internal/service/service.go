@@ -1,54 +1,143 @@
 package service
 
 import (
+	"context"
+	"io"
 	"time"
 
 	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/mailer"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
+// Tokens are issued on sign in, only Challenge is set when the password is correct
+// but the sign in has to be completed with a two-factor code.
 type Tokens struct {
 	AccessToken  string
 	RefreshToken string
+	Challenge    string
 }
 
 type Config interface {
 	GetAccessTokenTTL() time.Duration
 	GetRefreshTokenTTL() time.Duration
+	GetAdminKey() string
+	GetPasswordResetTTL() time.Duration
+	GetVerificationTTL() time.Duration
+	GetVerificationResendInterval() time.Duration
+	GetVerificationURL() string
+	GetRequireVerifiedEmail() string
+	GetTwoFactorIssuer() string
+	GetTwoFactorChallengeTTL() time.Duration
+	GetInvitationTTL() time.Duration
+}
+
+type NotesConfig interface {
+	GetAutoComplete() bool
+	GetTrashRetention() time.Duration
+	GetPurgeInterval() time.Duration
+}
+
+type AttachmentsConfig interface {
+	GetMaxSize() int64
+	GetAllowedTypes() []string
 }
 
 type PasswordHasher interface {
 	Hash(password string) (string, error)
+	Verify(password, hash string) (bool, error)
+	NeedsRehash(hash string) bool
 }
 
 type TokenManager interface {
-	NewJWT(userId int, ttl time.Duration) (string, error)
+	NewJWT(userId, sessionId int, ttl time.Duration) (string, error)
 	NewRefreshToken() (string, error)
-	ParseToken(accessToken string) (int, error)
+	ParseToken(accessToken string) (auth.Claims, error)
+}
+
+// Denylist keeps revoked access tokens until they expire.
+type Denylist interface {
+	RevokeToken(ctx context.Context, id string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+	RevokeSessionTokens(ctx context.Context, sessionId int, ttl time.Duration) error
+	IsSessionRevoked(ctx context.Context, sessionId int) (bool, error)
+	RevokeUserTokens(ctx context.Context, userId int, issuedBefore time.Time, ttl time.Duration) error
+	UserTokensRevokedBefore(ctx context.Context, userId int) (time.Time, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg mailer.Message) error
+}
+
+// BlobStore keeps contents of attachments, Get reads until the end of the blob when length <= 0.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
 }
 
 type Service struct {
-	repo            repository.Repository
-	hasher          PasswordHasher
-	tokenManager    TokenManager
-	accessTokenTTL  time.Duration
-	refreshTokenTTL time.Duration
+	repo                       repository.Repository
+	hasher                     PasswordHasher
+	tokenManager               TokenManager
+	denylist                   Denylist
+	mailer                     Mailer
+	blobs                      BlobStore
+	adminKey                   string
+	accessTokenTTL             time.Duration
+	refreshTokenTTL            time.Duration
+	passwordResetTTL           time.Duration
+	verificationTTL            time.Duration
+	verificationResendInterval time.Duration
+	verificationURL            string
+	requireVerifiedEmail       string
+	twoFactorIssuer            string
+	twoFactorChallengeTTL      time.Duration
+	invitationTTL              time.Duration
+	autoComplete               bool
+	trashRetention             time.Duration
+	purgeInterval              time.Duration
+	maxAttachmentSize          int64
+	attachmentTypes            []string
 }
 
 type Deps struct {
-	Cfg          Config
-	Repo         repository.Repository
-	Hasher       PasswordHasher
-	TokenManager TokenManager
+	Cfg            Config
+	NotesCfg       NotesConfig
+	AttachmentsCfg AttachmentsConfig
+	Repo           repository.Repository
+	Hasher         PasswordHasher
+	TokenManager   TokenManager
+	Denylist       Denylist
+	Mailer         Mailer
+	Blobs          BlobStore
 }
 
 func New(deps Deps) *Service {
 	return &Service{
-		repo:            deps.Repo,
-		hasher:          deps.Hasher,
-		tokenManager:    deps.TokenManager,
-		accessTokenTTL:  deps.Cfg.GetAccessTokenTTL(),
-		refreshTokenTTL: deps.Cfg.GetRefreshTokenTTL(),
+		repo:                       deps.Repo,
+		hasher:                     deps.Hasher,
+		tokenManager:               deps.TokenManager,
+		denylist:                   deps.Denylist,
+		mailer:                     deps.Mailer,
+		blobs:                      deps.Blobs,
+		adminKey:                   deps.Cfg.GetAdminKey(),
+		accessTokenTTL:             deps.Cfg.GetAccessTokenTTL(),
+		refreshTokenTTL:            deps.Cfg.GetRefreshTokenTTL(),
+		passwordResetTTL:           deps.Cfg.GetPasswordResetTTL(),
+		verificationTTL:            deps.Cfg.GetVerificationTTL(),
+		verificationResendInterval: deps.Cfg.GetVerificationResendInterval(),
+		verificationURL:            deps.Cfg.GetVerificationURL(),
+		requireVerifiedEmail:       deps.Cfg.GetRequireVerifiedEmail(),
+		twoFactorIssuer:            deps.Cfg.GetTwoFactorIssuer(),
+		twoFactorChallengeTTL:      deps.Cfg.GetTwoFactorChallengeTTL(),
+		invitationTTL:              deps.Cfg.GetInvitationTTL(),
+		autoComplete:               deps.NotesCfg.GetAutoComplete(),
+		trashRetention:             deps.NotesCfg.GetTrashRetention(),
+		purgeInterval:              deps.NotesCfg.GetPurgeInterval(),
+		maxAttachmentSize:          deps.AttachmentsCfg.GetMaxSize(),
+		attachmentTypes:            deps.AttachmentsCfg.GetAllowedTypes(),
 	}
 }
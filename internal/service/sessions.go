@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/logger"
+)
+
+// RefreshTokens rotates the refresh token of the session. Every session is a family of refresh tokens
+// each of which can be used once, a used token presented again means it has leaked, so the whole
+// family is revoked and the security event is logged.
+func (s *Service) RefreshTokens(ctx context.Context, refreshToken string, client entity.Client) (Tokens, error) {
+	usedHash := auth.HashToken(refreshToken)
+
+	session, err := s.repo.GetSessionByRefreshToken(ctx, usedHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Tokens{}, s.detectRefreshTokenReuse(ctx, usedHash, client)
+		}
+		return Tokens{}, err
+	}
+
+	if !auth.CompareToken(refreshToken, session.RefreshToken) {
+		return Tokens{}, entity.ErrSessionDoesntExist
+	}
+
+	newToken, err := s.tokenManager.NewRefreshToken()
+	if err != nil {
+		return Tokens{}, err
+	}
+	session.RefreshToken = auth.HashToken(newToken)
+	session.ExpiresAt = time.Now().Add(s.refreshTokenTTL)
+	session.Client.UserAgent = client.UserAgent
+	session.Client.IP = client.IP
+
+	if err = s.repo.RotateSession(ctx, session, usedHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// rotated by a concurrent request with the same token
+			return Tokens{}, s.revokeTokenFamily(ctx, session, client)
+		}
+		return Tokens{}, err
+	}
+
+	accessToken, err := s.tokenManager.NewJWT(session.UserId, session.ID, s.accessTokenTTL)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{AccessToken: accessToken, RefreshToken: newToken}, nil
+}
+
+// detectRefreshTokenReuse revokes the family of the refresh token when it has already been rotated,
+// otherwise the token is unknown or expired.
+func (s *Service) detectRefreshTokenReuse(ctx context.Context, usedHash string, client entity.Client) error {
+	session, err := s.repo.GetSessionByUsedRefreshToken(ctx, usedHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrSessionDoesntExist
+		}
+		return err
+	}
+
+	return s.revokeTokenFamily(ctx, session, client)
+}
+
+// revokeTokenFamily deletes the session, so neither the legitimate client nor the one holding
+// the leaked token can refresh it anymore. Access tokens of the user are revoked as well
+// since the ones of the session can't be told apart.
+func (s *Service) revokeTokenFamily(ctx context.Context, session entity.Session, client entity.Client) error {
+	logger.WarnKV(ctx, "Refresh token reuse detected, revoking the session",
+		"user_id", session.UserId, "session_id", session.ID, "ip", client.IP, "user_agent", client.UserAgent)
+
+	if err := s.repo.DeleteSession(ctx, session.ID, session.UserId); err != nil {
+		return err
+	}
+
+	if err := s.denylist.RevokeUserTokens(ctx, session.UserId, time.Now(), s.accessTokenTTL); err != nil {
+		return err
+	}
+
+	return entity.ErrRefreshTokenReused
+}
+
+// createSession starts a new session of the user, other sessions stay valid.
+// Only the hash of the refresh token is stored.
+func (s *Service) createSession(ctx context.Context, userId int, client entity.Client) (Tokens, error) {
+	refreshToken, err := s.tokenManager.NewRefreshToken()
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	session := entity.Session{
+		UserId:       userId,
+		RefreshToken: auth.HashToken(refreshToken),
+		Client:       client,
+		ExpiresAt:    time.Now().Add(s.refreshTokenTTL),
+	}
+
+	session.ID, err = s.repo.CreateSession(ctx, session)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	accessToken, err := s.tokenManager.NewJWT(userId, session.ID, s.accessTokenTTL)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// GetSessions returns active sessions of the user, the session of the request is marked as current.
+func (s *Service) GetSessions(ctx context.Context, userId, currentId int) ([]entity.Session, error) {
+	sessions, err := s.repo.GetSessions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentId
+	}
+
+	return sessions, nil
+}
+
+// DeleteSession logs the device of the session out, access tokens issued for the session are revoked
+// along with its refresh token.
+func (s *Service) DeleteSession(ctx context.Context, id, userId int) error {
+	if _, err := s.repo.GetSessionById(ctx, id, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrSessionDoesntExist
+		}
+		return err
+	}
+
+	if err := s.repo.DeleteSession(ctx, id, userId); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeSessionTokens(ctx, id, s.accessTokenTTL)
+}
+
+// DeleteSessions logs the user out everywhere including the current session,
+// access tokens issued so far and personal access tokens are revoked as well.
+func (s *Service) DeleteSessions(ctx context.Context, userId int) error {
+	if err := s.repo.DeleteSessions(ctx, userId); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeletePersonalAccessTokens(ctx, userId); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeUserTokens(ctx, userId, time.Now(), s.accessTokenTTL)
+}
+
+// Logout deletes the session of the access token and revokes the token itself
+// until it expires.
+func (s *Service) Logout(ctx context.Context, claims auth.Claims) error {
+	if err := s.repo.DeleteSession(ctx, claims.SessionId, claims.UserId); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeToken(ctx, claims.ID, time.Until(claims.ExpiresAt))
+}
+
+// RevokeUserTokens logs the user out everywhere on behalf of an admin.
+func (s *Service) RevokeUserTokens(ctx context.Context, userId int) error {
+	if _, err := s.repo.GetUserByID(ctx, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrUserNotExist
+		}
+		return err
+	}
+
+	if err := s.DeleteSessions(ctx, userId); err != nil {
+		return err
+	}
+
+	logger.InfoKV(ctx, "Revoked user tokens", "user_id", userId)
+
+	return nil
+}
+
+// CheckAccessToken rejects the token revoked by id or by its session, or issued before all tokens of the user were revoked.
+// Issue time of a token has a precision of a second, so tokens issued within the second of
+// the revocation are rejected as well.
+func (s *Service) CheckAccessToken(ctx context.Context, claims auth.Claims) error {
+	revoked, err := s.denylist.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		return entity.ErrTokenRevoked
+	}
+
+	if claims.SessionId != 0 {
+		if revoked, err = s.denylist.IsSessionRevoked(ctx, claims.SessionId); err != nil {
+			return err
+		}
+
+		if revoked {
+			return entity.ErrTokenRevoked
+		}
+	}
+
+	issuedBefore, err := s.denylist.UserTokensRevokedBefore(ctx, claims.UserId)
+	if err != nil {
+		return err
+	}
+
+	if !issuedBefore.IsZero() && !claims.IssuedAt.After(issuedBefore) {
+		return entity.ErrTokenRevoked
+	}
+
+	return nil
+}
+
+// CheckAdminKey authorizes admin requests, they are disabled while no admin key is configured.
+func (s *Service) CheckAdminKey(key string) error {
+	if s.adminKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(s.adminKey)) != 1 {
+		return entity.ErrInvalidAdminKey
+	}
+
+	return nil
+}
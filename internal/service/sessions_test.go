@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/denylist"
+	"github.com/stretchr/testify/assert"
+)
+
+type sessionsRepo struct {
+	repository.Repository
+
+	mu       sync.Mutex
+	sessions []entity.Session
+}
+
+func (r *sessionsRepo) GetUserByLogin(_ context.Context, login string) (entity.User, error) {
+	return entity.User{ID: 1, Login: login, Password: "password"}, nil
+}
+
+func (r *sessionsRepo) GetTwoFactor(context.Context, int) (entity.TwoFactor, error) {
+	return entity.TwoFactor{}, sql.ErrNoRows
+}
+
+func (r *sessionsRepo) CreateSession(_ context.Context, session entity.Session) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions = append(r.sessions, session)
+	return len(r.sessions), nil
+}
+
+func (r *sessionsRepo) GetSessionById(_ context.Context, id, userId int) (entity.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > len(r.sessions) || r.sessions[id-1].UserId != userId {
+		return entity.Session{}, sql.ErrNoRows
+	}
+	return r.sessions[id-1], nil
+}
+
+func (r *sessionsRepo) DeleteSession(context.Context, int, int) error {
+	return nil
+}
+
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error)       { return password, nil }
+func (plainHasher) Verify(password, hash string) (bool, error) { return password == hash, nil }
+func (plainHasher) NeedsRehash(string) bool                    { return false }
+
+type authConfig struct{}
+
+func (authConfig) GetSecret() string { return "secret" }
+
+func TestSignIn_ConcurrentRefreshTokens(t *testing.T) {
+	repo := &sessionsRepo{}
+	s := &Service{
+		repo:            repo,
+		hasher:          plainHasher{},
+		tokenManager:    auth.NewManager(authConfig{}, nil),
+		accessTokenTTL:  time.Minute,
+		refreshTokenTTL: time.Hour,
+	}
+
+	const n = 100
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		tokens = make(map[string]struct{}, n)
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res, err := s.SignIn(context.Background(), "login", "password", entity.Client{})
+			assert.NoError(t, err)
+
+			mu.Lock()
+			tokens[res.RefreshToken] = struct{}{}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, tokens, n, "refresh tokens of concurrent sign ins must be unique")
+
+	hashes := make(map[string]struct{}, n)
+	for _, session := range repo.sessions {
+		_, plain := tokens[session.RefreshToken]
+		assert.False(t, plain, "refresh token must not be stored in plaintext")
+		hashes[session.RefreshToken] = struct{}{}
+	}
+	assert.Len(t, hashes, n)
+
+	for token := range tokens {
+		_, ok := hashes[auth.HashToken(token)]
+		assert.True(t, ok, "hash of every refresh token must be stored")
+	}
+}
+
+func TestDeleteSession_RevokesAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	repo := &sessionsRepo{}
+	s := &Service{
+		repo:            repo,
+		hasher:          plainHasher{},
+		tokenManager:    auth.NewManager(authConfig{}, nil),
+		denylist:        denylist.NewMemory(),
+		accessTokenTTL:  time.Minute,
+		refreshTokenTTL: time.Hour,
+	}
+
+	laptop, err := s.SignIn(ctx, "login", "password", entity.Client{Device: "laptop"})
+	assert.NoError(t, err)
+	phone, err := s.SignIn(ctx, "login", "password", entity.Client{Device: "phone"})
+	assert.NoError(t, err)
+
+	laptopClaims, err := s.tokenManager.ParseToken(laptop.AccessToken)
+	assert.NoError(t, err)
+	phoneClaims, err := s.tokenManager.ParseToken(phone.AccessToken)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.DeleteSession(ctx, laptopClaims.SessionId, 1))
+
+	assert.ErrorIs(t, s.CheckAccessToken(ctx, laptopClaims), entity.ErrTokenRevoked)
+	assert.NoError(t, s.CheckAccessToken(ctx, phoneClaims), "tokens of other sessions must stay valid")
+}
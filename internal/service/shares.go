@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// ShareNote grants the user with the login viewer or editor permission on the note, sharing again
+// replaces the permission. Only the owner can share the note and notes of workspaces aren't shared.
+func (s *Service) ShareNote(ctx context.Context, noteId, ownerId int, login, permission string) (entity.NoteShare, error) {
+	if permission != entity.PermissionViewer && permission != entity.PermissionEditor {
+		return entity.NoteShare{}, entity.ErrInvalidPermission
+	}
+
+	if _, ok := entity.WorkspaceFromContext(ctx); ok {
+		return entity.NoteShare{}, entity.ErrWorkspaceSharing
+	}
+
+	if _, err := s.checkNoteAccess(ctx, noteId, ownerId, entity.PermissionOwner); err != nil {
+		return entity.NoteShare{}, err
+	}
+
+	user, err := s.repo.GetUserByLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.NoteShare{}, entity.ErrUserNotExist
+		}
+		return entity.NoteShare{}, err
+	}
+
+	if user.ID == ownerId {
+		return entity.NoteShare{}, entity.ErrShareWithOwner
+	}
+
+	share := entity.NoteShare{
+		NoteId:     noteId,
+		UserId:     user.ID,
+		Login:      user.Login,
+		OwnerId:    ownerId,
+		Permission: permission,
+		CreatedAt:  time.Now(),
+	}
+
+	if err = s.repo.ShareNote(ctx, share); err != nil {
+		return entity.NoteShare{}, err
+	}
+
+	return share, nil
+}
+
+// UnshareNote revokes access of the collaborator to the note, the owner can revoke anyone
+// while collaborators can only leave the note themselves.
+func (s *Service) UnshareNote(ctx context.Context, noteId, userId, collaboratorId int) error {
+	if collaboratorId != userId {
+		if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionOwner); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.UnshareNote(ctx, noteId, collaboratorId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrShareNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetNoteShares returns collaborators of the note, only the owner can see them.
+func (s *Service) GetNoteShares(ctx context.Context, noteId, userId int) ([]entity.NoteShare, error) {
+	if _, err := s.checkNoteAccess(ctx, noteId, userId, entity.PermissionOwner); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetNoteShares(ctx, noteId)
+}
+
+// GetSharedNotes returns notes other users shared with the user.
+func (s *Service) GetSharedNotes(ctx context.Context, userId int) ([]entity.SharedNote, error) {
+	shared, err := s.repo.GetSharedNotes(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]entity.Note, len(shared))
+	for i := range shared {
+		notes[i] = shared[i].Note
+	}
+
+	if err = s.attachTags(ctx, notes); err != nil {
+		return nil, err
+	}
+
+	if err = s.attachCommentCounts(ctx, notes); err != nil {
+		return nil, err
+	}
+
+	for i := range shared {
+		shared[i].Note = notes[i]
+	}
+
+	return shared, nil
+}
+
+// getNote returns the note with tags when the user owns it or it's shared with the user
+// with at least the permission.
+func (s *Service) getNote(ctx context.Context, id, userId int, permission string) (entity.Note, error) {
+	note, err := s.repo.GetNoteById(ctx, id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		var ownerId int
+		if ownerId, err = s.checkSharedNote(ctx, id, userId, permission); err != nil {
+			return entity.Note{}, err
+		}
+		note, err = s.repo.GetNoteById(ctx, id, ownerId)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Note{}, entity.ErrNoteNotExists
+		}
+		return entity.Note{}, err
+	}
+
+	notes := []entity.Note{note}
+	if err = s.attachTags(ctx, notes); err != nil {
+		return entity.Note{}, err
+	}
+
+	return notes[0], nil
+}
+
+// checkNoteAccess returns the owner of the note when the user has at least the permission on it.
+func (s *Service) checkNoteAccess(ctx context.Context, noteId, userId int, permission string) (int, error) {
+	if s.isNoteExists(ctx, noteId, userId) {
+		return userId, nil
+	}
+
+	return s.checkSharedNote(ctx, noteId, userId, permission)
+}
+
+// checkSharedNote returns the owner of the note shared with the user when the permission granted is enough.
+func (s *Service) checkSharedNote(ctx context.Context, noteId, userId int, permission string) (int, error) {
+	share, err := s.repo.GetNoteShare(ctx, noteId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, entity.ErrNoteNotExists
+		}
+		return 0, err
+	}
+
+	if !entity.PermissionAllows(share.Permission, permission) {
+		return 0, entity.ErrNoteForbidden
+	}
+
+	return share.OwnerId, nil
+}
+
+// hasNoteAccess reports whether the user can see the note: notes of a workspace are seen by its members,
+// personal notes by the owner and users they are shared with.
+func (s *Service) hasNoteAccess(ctx context.Context, note entity.Note, userId int) (bool, error) {
+	var err error
+	if workspace, ok := entity.WorkspaceFromContext(ctx); ok {
+		_, err = s.repo.GetWorkspaceMember(ctx, workspace.WorkspaceId, userId)
+	} else if userId != note.UserId {
+		_, err = s.repo.GetNoteShare(ctx, note.ID, userId)
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
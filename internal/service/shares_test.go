@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sharesRepo struct {
+	repository.Repository
+
+	notes     map[int]entity.Note
+	shares    map[[2]int]string
+	revisions []entity.Revision
+}
+
+func (r *sharesRepo) GetNoteById(_ context.Context, id, userId int) (entity.Note, error) {
+	note, ok := r.notes[id]
+	if !ok || note.UserId != userId || note.DeletedAt != nil {
+		return entity.Note{}, sql.ErrNoRows
+	}
+	return note, nil
+}
+
+func (r *sharesRepo) GetNoteByTitle(_ context.Context, title string, userId int) (entity.Note, error) {
+	for _, note := range r.notes {
+		if note.Title == title && note.UserId == userId {
+			return note, nil
+		}
+	}
+	return entity.Note{}, sql.ErrNoRows
+}
+
+func (r *sharesRepo) UpdateNote(_ context.Context, id, userId int, upd entity.NoteUpdate) error {
+	note := r.notes[id]
+	if note.UserId != userId {
+		return sql.ErrNoRows
+	}
+	note.Title = upd.Title
+	r.notes[id] = note
+	return nil
+}
+
+func (r *sharesRepo) DeleteNoteById(_ context.Context, id, _ int) error {
+	delete(r.notes, id)
+	return nil
+}
+
+func (r *sharesRepo) GetNotesTags(context.Context, []int) (map[int][]string, error) {
+	return nil, nil
+}
+
+func (r *sharesRepo) CreateRevision(context.Context, entity.Revision) (int, error) {
+	return 1, nil
+}
+
+func (r *sharesRepo) GetRevisions(_ context.Context, noteId int) ([]entity.Revision, error) {
+	var result []entity.Revision
+	for _, revision := range r.revisions {
+		if revision.NoteId == noteId {
+			result = append(result, revision)
+		}
+	}
+	return result, nil
+}
+
+func (r *sharesRepo) GetRevisionById(_ context.Context, id, noteId int) (entity.Revision, error) {
+	for _, revision := range r.revisions {
+		if revision.ID == id && revision.NoteId == noteId {
+			return revision, nil
+		}
+	}
+	return entity.Revision{}, sql.ErrNoRows
+}
+
+func (r *sharesRepo) GetUserByLogin(_ context.Context, login string) (entity.User, error) {
+	switch login {
+	case "owner":
+		return entity.User{ID: 1, Login: login}, nil
+	case "colleague":
+		return entity.User{ID: 2, Login: login}, nil
+	}
+	return entity.User{}, sql.ErrNoRows
+}
+
+func (r *sharesRepo) ShareNote(_ context.Context, share entity.NoteShare) error {
+	r.shares[[2]int{share.NoteId, share.UserId}] = share.Permission
+	return nil
+}
+
+func (r *sharesRepo) UnshareNote(_ context.Context, noteId, userId int) error {
+	if _, ok := r.shares[[2]int{noteId, userId}]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.shares, [2]int{noteId, userId})
+	return nil
+}
+
+func (r *sharesRepo) GetNoteShare(_ context.Context, noteId, userId int) (entity.NoteShare, error) {
+	permission, ok := r.shares[[2]int{noteId, userId}]
+	note, exists := r.notes[noteId]
+	if !ok || !exists {
+		return entity.NoteShare{}, sql.ErrNoRows
+	}
+	return entity.NoteShare{NoteId: noteId, UserId: userId, OwnerId: note.UserId, Permission: permission}, nil
+}
+
+func TestNoteSharing(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &sharesRepo{
+		notes:  map[int]entity.Note{10: {ID: 10, UserId: 1, Title: "Plan"}},
+		shares: make(map[[2]int]string),
+	}
+	s := &Service{repo: repo}
+
+	_, err := s.GetNoteById(ctx, 10, 2)
+	assert.ErrorIs(t, err, entity.ErrNoteNotExists, "unshared note must be hidden")
+
+	_, err = s.ShareNote(ctx, 10, 1, "colleague", entity.PermissionOwner)
+	assert.ErrorIs(t, err, entity.ErrInvalidPermission)
+
+	_, err = s.ShareNote(ctx, 10, 1, "owner", entity.PermissionViewer)
+	assert.ErrorIs(t, err, entity.ErrShareWithOwner)
+
+	_, err = s.ShareNote(ctx, 10, 1, "colleague", entity.PermissionViewer)
+	require.NoError(t, err)
+
+	note, err := s.GetNoteById(ctx, 10, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, note.UserId)
+
+	assert.ErrorIs(t, s.UpdateNote(ctx, 10, entity.NoteUpdate{Title: "Viewer"}, 2), entity.ErrNoteForbidden)
+
+	_, err = s.ShareNote(ctx, 10, 2, "owner", entity.PermissionEditor)
+	assert.ErrorIs(t, err, entity.ErrNoteForbidden, "collaborators can't share notes")
+
+	_, err = s.ShareNote(ctx, 10, 1, "colleague", entity.PermissionEditor)
+	require.NoError(t, err)
+
+	require.NoError(t, s.UpdateNote(ctx, 10, entity.NoteUpdate{Title: "Editor"}, 2))
+	assert.Equal(t, "Editor", repo.notes[10].Title)
+
+	listId := 1
+	assert.ErrorIs(t, s.UpdateNote(ctx, 10, entity.NoteUpdate{ListId: &listId}, 2), entity.ErrNoteForbidden)
+
+	assert.ErrorIs(t, s.DeleteNoteById(ctx, 10, 2), entity.ErrNoteForbidden)
+	assert.ErrorIs(t, s.DeleteNoteById(ctx, 10, 3), entity.ErrNoteNotExists)
+
+	require.NoError(t, s.UnshareNote(ctx, 10, 2, 2), "collaborators can leave the note")
+	_, err = s.GetNoteById(ctx, 10, 2)
+	assert.ErrorIs(t, err, entity.ErrNoteNotExists)
+
+	require.NoError(t, s.DeleteNoteById(ctx, 10, 1))
+}
+
+func TestSharedNoteHistory(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &sharesRepo{
+		notes:  map[int]entity.Note{10: {ID: 10, UserId: 1, Title: "Plan"}},
+		shares: map[[2]int]string{{10, 2}: entity.PermissionViewer},
+		revisions: []entity.Revision{
+			{ID: 2, NoteId: 10, UserId: 1, Action: entity.RevisionUpdate, Snapshot: entity.Note{ID: 10, UserId: 1, Title: "Plan"}},
+			{ID: 1, NoteId: 10, UserId: 1, Action: entity.RevisionCreate, Snapshot: entity.Note{ID: 10, UserId: 1, Title: "Draft"}},
+		},
+	}
+	s := &Service{repo: repo}
+
+	history, err := s.GetNoteHistory(ctx, 10, 2)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	changes, err := s.DiffRevisions(ctx, 10, 1, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, entity.Change{Old: "Draft", New: "Plan"}, changes["title"])
+
+	assert.ErrorIs(t, s.RevertNote(ctx, 10, 1, 2), entity.ErrNoteForbidden, "only the owner reverts notes")
+
+	_, err = s.GetNoteHistory(ctx, 10, 3)
+	assert.ErrorIs(t, err, entity.ErrNoteNotExists)
+	_, err = s.DiffRevisions(ctx, 10, 1, 2, 3)
+	assert.ErrorIs(t, err, entity.ErrNoteNotExists)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+func (s *Service) GetTags(ctx context.Context, userId int) ([]entity.Tag, error) {
+	return s.repo.GetTags(ctx, userId)
+}
+
+func (s *Service) UpdateTag(ctx context.Context, id int, name string, userId int) error {
+	tag, err := s.repo.GetTagById(ctx, id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrTagNotExists
+		}
+		return err
+	}
+
+	if tag.Name == name {
+		return nil
+	}
+
+	if _, err = s.repo.GetTagByName(ctx, name, userId); err == nil {
+		return entity.ErrTagExists
+	}
+
+	return s.repo.UpdateTag(ctx, id, userId, name)
+}
+
+func (s *Service) DeleteTag(ctx context.Context, id, userId int) error {
+	if _, err := s.repo.GetTagById(ctx, id, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrTagNotExists
+		}
+		return err
+	}
+
+	return s.repo.DeleteTag(ctx, id, userId)
+}
+
+func (s *Service) attachTags(ctx context.Context, notes []entity.Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(notes))
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+
+	noteTags, err := s.repo.GetNotesTags(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range notes {
+		notes[i].Tags = noteTags[notes[i].ID]
+	}
+
+	return nil
+}
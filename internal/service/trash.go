@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/logger"
+)
+
+func (s *Service) GetTrash(ctx context.Context, userId int) ([]entity.Note, error) {
+	notes, err := s.repo.GetTrashedNotes(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return notes, s.attachTags(ctx, notes)
+}
+
+// RestoreNote moves the note back from the trash unless a note with the same title was created meanwhile.
+func (s *Service) RestoreNote(ctx context.Context, id, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	note, err := s.getTrashedNote(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	if s.isNoteExists(ctx, note.Title, userId) {
+		return entity.ErrNoteExists
+	}
+
+	return s.repo.RestoreNote(ctx, id, userId)
+}
+
+// DeleteNotePermanently removes the note from the trash along with blobs of its attachments.
+func (s *Service) DeleteNotePermanently(ctx context.Context, id, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	if _, err := s.getTrashedNote(ctx, id, userId); err != nil {
+		return err
+	}
+
+	keys, err := s.repo.PurgeNote(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	s.deleteBlobs(ctx, keys)
+
+	return nil
+}
+
+func (s *Service) EmptyTrash(ctx context.Context, userId int) error {
+	if err := checkWorkspaceRole(ctx, entity.WorkspaceRoleMember); err != nil {
+		return err
+	}
+
+	keys, err := s.repo.PurgeNotes(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	s.deleteBlobs(ctx, keys)
+
+	return nil
+}
+
+// PurgeTrash permanently deletes notes staying in the trash longer than the retention.
+func (s *Service) PurgeTrash(ctx context.Context) (int64, error) {
+	purged, keys, err := s.repo.PurgeTrashedBefore(ctx, time.Now().Add(-s.trashRetention))
+	if err != nil {
+		return 0, err
+	}
+
+	s.deleteBlobs(ctx, keys)
+
+	return purged, nil
+}
+
+// RunPurger purges the trash and expired sessions every purge interval until ctx is done.
+func (s *Service) RunPurger(ctx context.Context) {
+	if s.purgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if purged, err := s.PurgeTrash(ctx); err != nil {
+				logger.ErrorKV(ctx, "Failed purge trash", "err", err)
+			} else if purged > 0 {
+				logger.InfoKV(ctx, "Trash purged", "notes", purged)
+			}
+
+			if purged, err := s.repo.DeleteExpiredSessions(ctx); err != nil {
+				logger.ErrorKV(ctx, "Failed purge expired sessions", "err", err)
+			} else if purged > 0 {
+				logger.InfoKV(ctx, "Expired sessions purged", "sessions", purged)
+			}
+		}
+	}
+}
+
+func (s *Service) getTrashedNote(ctx context.Context, id, userId int) (entity.Note, error) {
+	note, err := s.repo.GetTrashedNoteById(ctx, id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Note{}, entity.ErrNoteNotExists
+		}
+		return entity.Note{}, err
+	}
+
+	return note, nil
+}
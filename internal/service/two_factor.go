@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/logger"
+	"github.com/pintoter/todo-list/pkg/totp"
+)
+
+const (
+	recoveryCodesCount = 10
+	// maxChallengeAttempts is the number of wrong codes after which the challenge is dropped
+	// and the sign in has to start over with the password.
+	maxChallengeAttempts = 5
+	// twoFactorLockout is how long disabling 2FA is refused after maxChallengeAttempts wrong codes in a row,
+	// so a stolen access token can't be used to guess a code.
+	twoFactorLockout = 15 * time.Minute
+)
+
+// EnrollTwoFactor generates a new TOTP secret for the user, it's pending until confirmed
+// with a code by ConfirmTwoFactor. The otpauth:// URI of the secret is returned for a QR code.
+func (s *Service) EnrollTwoFactor(ctx context.Context, userId int) (string, string, error) {
+	user, err := s.repo.GetUserByID(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", entity.ErrUserNotExist
+		}
+		return "", "", err
+	}
+
+	tf, err := s.repo.GetTwoFactor(ctx, userId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", "", err
+	}
+
+	if err == nil && tf.EnabledAt != nil {
+		return "", "", entity.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err = s.repo.SetTwoFactorSecret(ctx, userId, secret); err != nil {
+		return "", "", err
+	}
+
+	return secret, totp.URI(s.twoFactorIssuer, user.Login, secret), nil
+}
+
+// ConfirmTwoFactor enables 2FA with the first code of the pending secret and returns
+// one-time recovery codes, they're shown once since only their hashes are stored.
+func (s *Service) ConfirmTwoFactor(ctx context.Context, userId int, code string) ([]string, error) {
+	tf, err := s.repo.GetTwoFactor(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+
+	if tf.EnabledAt != nil {
+		return nil, entity.ErrTwoFactorEnabled
+	}
+
+	step, ok := totp.Validate(tf.Secret, code, time.Now())
+	if !ok {
+		return nil, entity.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.EnableTwoFactor(ctx, userId, step, hashes); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// enabled or re-enrolled by a concurrent request
+			return nil, entity.ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+
+	logger.InfoKV(ctx, "Two-factor authentication enabled", "user_id", userId)
+
+	return codes, nil
+}
+
+// DisableTwoFactor turns 2FA off, a current code is required so a stolen access token isn't enough.
+// Codes are locked for a while after too many wrong ones.
+func (s *Service) DisableTwoFactor(ctx context.Context, userId int, code string) error {
+	tf, err := s.getEnabledTwoFactor(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if tf.LockedUntil != nil && time.Now().Before(*tf.LockedUntil) {
+		return entity.ErrTwoFactorLocked
+	}
+
+	if err = s.verifyTOTP(ctx, tf, code); err != nil {
+		if !errors.Is(err, entity.ErrInvalidTwoFactorCode) {
+			return err
+		}
+
+		if err = s.repo.FailTwoFactor(ctx, userId, maxChallengeAttempts, time.Now().Add(twoFactorLockout)); err != nil {
+			return err
+		}
+		return entity.ErrInvalidTwoFactorCode
+	}
+
+	if err = s.repo.DeleteTwoFactor(ctx, userId); err != nil {
+		return err
+	}
+
+	logger.InfoKV(ctx, "Two-factor authentication disabled", "user_id", userId)
+
+	return nil
+}
+
+// SignInTwoFactor completes the sign in started by the password, the challenge token is exchanged
+// with a TOTP or a recovery code for tokens. The challenge is dropped after too many wrong codes.
+func (s *Service) SignInTwoFactor(ctx context.Context, challengeToken, code string, client entity.Client) (Tokens, error) {
+	challenge, err := s.repo.GetChallenge(ctx, auth.HashToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Tokens{}, entity.ErrInvalidChallenge
+		}
+		return Tokens{}, err
+	}
+
+	tf, err := s.getEnabledTwoFactor(ctx, challenge.UserId)
+	if err != nil {
+		if errors.Is(err, entity.ErrTwoFactorNotEnabled) {
+			return Tokens{}, entity.ErrInvalidChallenge
+		}
+		return Tokens{}, err
+	}
+
+	if isRecoveryCode(code) {
+		err = s.useRecoveryCode(ctx, tf.UserId, code)
+	} else {
+		err = s.verifyTOTP(ctx, tf, code)
+	}
+
+	if err != nil {
+		if !errors.Is(err, entity.ErrInvalidTwoFactorCode) {
+			return Tokens{}, err
+		}
+
+		if err = s.repo.FailChallenge(ctx, challenge.ID, maxChallengeAttempts); err != nil {
+			return Tokens{}, err
+		}
+		return Tokens{}, entity.ErrInvalidTwoFactorCode
+	}
+
+	if err = s.repo.DeleteChallenge(ctx, challenge.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// exchanged by a concurrent request
+			return Tokens{}, entity.ErrInvalidChallenge
+		}
+		return Tokens{}, err
+	}
+
+	client.Device = challenge.Device
+
+	return s.createSession(ctx, challenge.UserId, client)
+}
+
+// createChallenge starts the second step of the sign in, the device of the first step is kept
+// for the session created by the second one.
+func (s *Service) createChallenge(ctx context.Context, userId int, client entity.Client) (Tokens, error) {
+	token, err := auth.NewToken()
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	challenge := entity.TwoFactorChallenge{
+		UserId:    userId,
+		TokenHash: auth.HashToken(token),
+		Device:    client.Device,
+		ExpiresAt: time.Now().Add(s.twoFactorChallengeTTL),
+	}
+
+	if _, err = s.repo.CreateChallenge(ctx, challenge); err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{Challenge: token}, nil
+}
+
+// twoFactorEnabled reports whether signing in of the user requires a code.
+func (s *Service) twoFactorEnabled(ctx context.Context, userId int) (bool, error) {
+	if _, err := s.getEnabledTwoFactor(ctx, userId); err != nil {
+		if errors.Is(err, entity.ErrTwoFactorNotEnabled) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Service) getEnabledTwoFactor(ctx context.Context, userId int) (entity.TwoFactor, error) {
+	tf, err := s.repo.GetTwoFactor(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.TwoFactor{}, entity.ErrTwoFactorNotEnabled
+		}
+		return entity.TwoFactor{}, err
+	}
+
+	if tf.EnabledAt == nil {
+		return entity.TwoFactor{}, entity.ErrTwoFactorNotEnabled
+	}
+
+	return tf, nil
+}
+
+// verifyTOTP accepts a code once, the step of the code is recorded so it can't be replayed
+// within its validity window.
+func (s *Service) verifyTOTP(ctx context.Context, tf entity.TwoFactor, code string) error {
+	step, ok := totp.Validate(tf.Secret, code, time.Now())
+	if !ok || step <= tf.LastStep {
+		return entity.ErrInvalidTwoFactorCode
+	}
+
+	if err := s.repo.SetTwoFactorStep(ctx, tf.UserId, step); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) useRecoveryCode(ctx context.Context, userId int, code string) error {
+	err := s.repo.UseRecoveryCode(ctx, userId, auth.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	logger.InfoKV(ctx, "Recovery code used", "user_id", userId)
+
+	return nil
+}
+
+// newRecoveryCodes returns recovery codes formatted as xxxxx-xxxxx along with their hashes.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+
+	b := make([]byte, 5)
+	for i := 0; i < recoveryCodesCount; i++ {
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := hex.EncodeToString(b)
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, auth.HashToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode allows recovery codes typed without the dash, with spaces or in upper case.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func isRecoveryCode(code string) bool {
+	return len(normalizeRecoveryCode(code)) == 10
+}
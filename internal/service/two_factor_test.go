@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type twoFactorRepo struct {
+	repository.Repository
+
+	twoFactor     *entity.TwoFactor
+	failed        int
+	recoveryCodes map[string]bool
+	challenges    map[string]entity.TwoFactorChallenge
+	sessions      []entity.Session
+}
+
+func (r *twoFactorRepo) GetUserByID(_ context.Context, id int) (entity.User, error) {
+	return entity.User{ID: id, Login: "user", Password: "password"}, nil
+}
+
+func (r *twoFactorRepo) GetUserByLogin(_ context.Context, login string) (entity.User, error) {
+	return entity.User{ID: 1, Login: login, Password: "password"}, nil
+}
+
+func (r *twoFactorRepo) CreateSession(_ context.Context, session entity.Session) (int, error) {
+	r.sessions = append(r.sessions, session)
+	return len(r.sessions), nil
+}
+
+func (r *twoFactorRepo) GetTwoFactor(context.Context, int) (entity.TwoFactor, error) {
+	if r.twoFactor == nil {
+		return entity.TwoFactor{}, sql.ErrNoRows
+	}
+	return *r.twoFactor, nil
+}
+
+func (r *twoFactorRepo) SetTwoFactorSecret(_ context.Context, userId int, secret string) error {
+	r.twoFactor = &entity.TwoFactor{UserId: userId, Secret: secret}
+	return nil
+}
+
+func (r *twoFactorRepo) EnableTwoFactor(_ context.Context, _ int, step int64, codeHashes []string) error {
+	now := time.Now()
+	r.twoFactor.EnabledAt = &now
+	r.twoFactor.LastStep = step
+	r.recoveryCodes = make(map[string]bool)
+	for _, hash := range codeHashes {
+		r.recoveryCodes[hash] = false
+	}
+	return nil
+}
+
+func (r *twoFactorRepo) SetTwoFactorStep(_ context.Context, _ int, step int64) error {
+	if step <= r.twoFactor.LastStep {
+		return sql.ErrNoRows
+	}
+	r.twoFactor.LastStep = step
+	r.failed = 0
+	return nil
+}
+
+func (r *twoFactorRepo) FailTwoFactor(_ context.Context, _, maxAttempts int, lockedUntil time.Time) error {
+	r.failed++
+	if r.failed >= maxAttempts {
+		r.failed = 0
+		r.twoFactor.LockedUntil = &lockedUntil
+	}
+	return nil
+}
+
+func (r *twoFactorRepo) DeleteTwoFactor(context.Context, int) error {
+	r.twoFactor = nil
+	r.recoveryCodes = nil
+	return nil
+}
+
+func (r *twoFactorRepo) UseRecoveryCode(_ context.Context, _ int, codeHash string) error {
+	used, ok := r.recoveryCodes[codeHash]
+	if !ok || used {
+		return sql.ErrNoRows
+	}
+	r.recoveryCodes[codeHash] = true
+	return nil
+}
+
+func (r *twoFactorRepo) CreateChallenge(_ context.Context, challenge entity.TwoFactorChallenge) (int, error) {
+	challenge.ID = len(r.challenges) + 1
+	r.challenges[challenge.TokenHash] = challenge
+	return challenge.ID, nil
+}
+
+func (r *twoFactorRepo) GetChallenge(_ context.Context, tokenHash string) (entity.TwoFactorChallenge, error) {
+	challenge, ok := r.challenges[tokenHash]
+	if !ok {
+		return entity.TwoFactorChallenge{}, sql.ErrNoRows
+	}
+	return challenge, nil
+}
+
+func (r *twoFactorRepo) FailChallenge(_ context.Context, id, maxAttempts int) error {
+	for hash, challenge := range r.challenges {
+		if challenge.ID == id {
+			challenge.Attempts++
+			r.challenges[hash] = challenge
+			if challenge.Attempts >= maxAttempts {
+				delete(r.challenges, hash)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *twoFactorRepo) DeleteChallenge(_ context.Context, id int) error {
+	for hash, challenge := range r.challenges {
+		if challenge.ID == id {
+			delete(r.challenges, hash)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func TestTwoFactor(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &twoFactorRepo{challenges: make(map[string]entity.TwoFactorChallenge)}
+	s := &Service{
+		repo:                  repo,
+		hasher:                plainHasher{},
+		tokenManager:          auth.NewManager(authConfig{}, nil),
+		accessTokenTTL:        time.Minute,
+		twoFactorIssuer:       "todo-list",
+		twoFactorChallengeTTL: time.Minute,
+	}
+
+	secret, uri, err := s.EnrollTwoFactor(ctx, 1)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, secret, parsed.Query().Get("secret"))
+
+	tokens, err := s.SignIn(ctx, "user", "password", entity.Client{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken, "pending 2FA must not be required on sign in")
+
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step-1)
+
+	_, err = s.ConfirmTwoFactor(ctx, 1, "000000")
+	assert.ErrorIs(t, err, entity.ErrInvalidTwoFactorCode)
+
+	recoveryCodes, err := s.ConfirmTwoFactor(ctx, 1, code)
+	require.NoError(t, err)
+	assert.Len(t, recoveryCodes, recoveryCodesCount)
+
+	_, _, err = s.EnrollTwoFactor(ctx, 1)
+	assert.ErrorIs(t, err, entity.ErrTwoFactorEnabled)
+
+	tokens, err = s.SignIn(ctx, "user", "password", entity.Client{Device: "laptop"})
+	require.NoError(t, err)
+	assert.Empty(t, tokens.AccessToken)
+	require.NotEmpty(t, tokens.Challenge)
+
+	_, err = s.SignInTwoFactor(ctx, tokens.Challenge, code, entity.Client{})
+	assert.ErrorIs(t, err, entity.ErrInvalidTwoFactorCode, "code used for confirmation must not be replayed")
+
+	code, _ = totp.Code(secret, step)
+	tokens, err = s.SignInTwoFactor(ctx, tokens.Challenge, code, entity.Client{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.Equal(t, "laptop", repo.sessions[len(repo.sessions)-1].Client.Device)
+
+	_, err = s.SignInTwoFactor(ctx, tokens.Challenge, code, entity.Client{})
+	assert.ErrorIs(t, err, entity.ErrInvalidChallenge, "challenge must be single use")
+
+	tokens, err = s.SignIn(ctx, "user", "password", entity.Client{})
+	require.NoError(t, err)
+
+	recoveryCode := recoveryCodes[0]
+	_, err = s.SignInTwoFactor(ctx, tokens.Challenge, recoveryCode, entity.Client{})
+	require.NoError(t, err)
+
+	tokens, err = s.SignIn(ctx, "user", "password", entity.Client{})
+	require.NoError(t, err)
+
+	for i := 0; i < maxChallengeAttempts; i++ {
+		_, err = s.SignInTwoFactor(ctx, tokens.Challenge, recoveryCode, entity.Client{})
+		assert.ErrorIs(t, err, entity.ErrInvalidTwoFactorCode, "recovery code must be single use")
+	}
+
+	_, err = s.SignInTwoFactor(ctx, tokens.Challenge, recoveryCodes[1], entity.Client{})
+	assert.ErrorIs(t, err, entity.ErrInvalidChallenge, "challenge must be dropped after too many attempts")
+
+	for i := 0; i < maxChallengeAttempts; i++ {
+		assert.ErrorIs(t, s.DisableTwoFactor(ctx, 1, "000000"), entity.ErrInvalidTwoFactorCode)
+	}
+
+	code, _ = totp.Code(secret, step+1)
+	assert.ErrorIs(t, s.DisableTwoFactor(ctx, 1, code), entity.ErrTwoFactorLocked, "codes must be locked after too many attempts")
+	assert.NotNil(t, repo.twoFactor)
+
+	past := time.Now().Add(-time.Second)
+	repo.twoFactor.LockedUntil = &past
+	require.NoError(t, s.DisableTwoFactor(ctx, 1, code))
+	assert.Nil(t, repo.twoFactor)
+}
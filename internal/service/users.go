@@ -7,9 +7,10 @@ import (
 	"time"
 
 	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/logger"
 )
 
-func (s *Service) SignUp(ctx context.Context, email, login, password string) (int, error) {
+func (s *Service) SignUp(ctx context.Context, email, login, password, timeZone string) (int, error) {
 	if s.isLoginExists(ctx, login) || s.isEmailExists(ctx, email) {
 		return 0, entity.ErrUserExists
 	}
@@ -19,67 +20,107 @@ func (s *Service) SignUp(ctx context.Context, email, login, password string) (in
 		return 0, err
 	}
 
+	if timeZone == "" {
+		timeZone = entity.DefaultTimeZone
+	}
+
 	user := entity.User{
 		Email:    email,
 		Login:    login,
 		Password: hashedPassword,
+		TimeZone: timeZone,
 	}
 
-	return s.repo.CreateUser(ctx, user)
-}
-
-func (s *Service) SignIn(ctx context.Context, login, password string) (Tokens, error) {
-	hashedPassword, err := s.hasher.Hash(password)
+	user.ID, err = s.repo.CreateUser(ctx, user)
 	if err != nil {
-		return Tokens{}, err
+		return 0, err
+	}
+
+	// the account is created anyway, the link can be sent again
+	if err = s.sendVerification(ctx, user); err != nil {
+		logger.ErrorKV(ctx, "Failed send verification email", "user_id", user.ID, "err", err)
 	}
 
-	user, err := s.repo.GetUserByCredentials(ctx, login, hashedPassword)
+	return user.ID, nil
+}
+
+func (s *Service) SignIn(ctx context.Context, login, password string, client entity.Client) (Tokens, error) {
+	user, err := s.repo.GetUserByLogin(ctx, login)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			// hash anyway, so unknown logins can't be told apart by the response time
+			_, _ = s.hasher.Hash(password)
 			return Tokens{}, entity.ErrUserNotExist
 		}
 
 		return Tokens{}, err
 	}
 
-	return s.createSession(ctx, user.ID)
-}
+	ok, err := s.hasher.Verify(password, user.Password)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if !ok {
+		return Tokens{}, entity.ErrUserNotExist
+	}
+
+	if s.hasher.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user.ID, password)
+	}
+
+	if s.requireVerifiedEmail == RequireVerifiedEmailForSignIn && user.EmailVerifiedAt == nil {
+		return Tokens{}, entity.ErrEmailNotVerified
+	}
 
-func (s *Service) RefreshTokens(ctx context.Context, refreshToken string) (Tokens, error) {
-	user, err := s.repo.GetUserByRefreshToken(ctx, refreshToken)
+	twoFactor, err := s.twoFactorEnabled(ctx, user.ID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return Tokens{}, entity.ErrSessionDoesntExist
-		}
 		return Tokens{}, err
 	}
 
-	return s.createSession(ctx, user.ID)
+	if twoFactor {
+		return s.createChallenge(ctx, user.ID, client)
+	}
+
+	return s.createSession(ctx, user.ID, client)
 }
 
-func (s *Service) createSession(ctx context.Context, id int) (Tokens, error) {
-	var (
-		res Tokens
-		err error
-	)
+// rehashPassword replaces a legacy or outdated hash of the password, failure doesn't prevent signing in
+// since the old hash stays valid.
+func (s *Service) rehashPassword(ctx context.Context, userId int, password string) {
+	hashedPassword, err := s.hasher.Hash(password)
+	if err == nil {
+		err = s.repo.UpdateUserPassword(ctx, userId, hashedPassword)
+	}
 
-	res.AccessToken, err = s.tokenManager.NewJWT(id, s.accessTokenTTL)
 	if err != nil {
-		return Tokens{}, err
+		logger.ErrorKV(ctx, "Failed rehash password", "user_id", userId, "err", err)
 	}
+}
 
-	res.RefreshToken, err = s.tokenManager.NewRefreshToken()
+// GetUserLocation returns the user's default time zone used for due dates without an offset.
+func (s *Service) GetUserLocation(ctx context.Context, userId int) (*time.Location, error) {
+	user, err := s.repo.GetUserByID(ctx, userId)
 	if err != nil {
-		return Tokens{}, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.ErrUserNotExist
+		}
+		return nil, err
 	}
 
-	token := entity.Session{
-		RefreshToken: res.RefreshToken,
-		ExpiresAt:    time.Now().Add(s.refreshTokenTTL),
+	if user.TimeZone == "" {
+		return time.UTC, nil
+	}
+
+	return time.LoadLocation(user.TimeZone)
+}
+
+func (s *Service) UpdateTimeZone(ctx context.Context, userId int, timeZone string) error {
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return entity.ErrInvalidTimeZone
 	}
 
-	return res, s.repo.SetSession(ctx, id, token)
+	return s.repo.UpdateUserTimeZone(ctx, userId, timeZone)
 }
 
 func (s *Service) isLoginExists(ctx context.Context, login string) bool {
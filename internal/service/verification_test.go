@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type verificationsRepo struct {
+	repository.Repository
+
+	users         []entity.User
+	verifications []entity.EmailVerification
+}
+
+func (r *verificationsRepo) CreateUser(_ context.Context, user entity.User) (int, error) {
+	user.ID = len(r.users) + 1
+	r.users = append(r.users, user)
+	return user.ID, nil
+}
+
+func (r *verificationsRepo) findUser(match func(entity.User) bool) (entity.User, error) {
+	for _, user := range r.users {
+		if match(user) {
+			return user, nil
+		}
+	}
+	return entity.User{}, sql.ErrNoRows
+}
+
+func (r *verificationsRepo) GetUserByID(_ context.Context, id int) (entity.User, error) {
+	return r.findUser(func(u entity.User) bool { return u.ID == id })
+}
+
+func (r *verificationsRepo) GetUserByLogin(_ context.Context, login string) (entity.User, error) {
+	return r.findUser(func(u entity.User) bool { return u.Login == login })
+}
+
+func (r *verificationsRepo) GetUserByEmail(_ context.Context, email string) (entity.User, error) {
+	return r.findUser(func(u entity.User) bool { return u.Email == email })
+}
+
+func (r *verificationsRepo) GetTwoFactor(context.Context, int) (entity.TwoFactor, error) {
+	return entity.TwoFactor{}, sql.ErrNoRows
+}
+
+func (r *verificationsRepo) CreateSession(context.Context, entity.Session) (int, error) {
+	return 1, nil
+}
+
+func (r *verificationsRepo) CreateEmailVerification(_ context.Context, v entity.EmailVerification) (int, error) {
+	v.ID = len(r.verifications) + 1
+	v.CreatedAt = time.Now()
+	r.verifications = append(r.verifications, v)
+	return v.ID, nil
+}
+
+func (r *verificationsRepo) GetLatestEmailVerification(_ context.Context, userId int) (entity.EmailVerification, error) {
+	for i := len(r.verifications) - 1; i >= 0; i-- {
+		if r.verifications[i].UserId == userId {
+			return r.verifications[i], nil
+		}
+	}
+	return entity.EmailVerification{}, sql.ErrNoRows
+}
+
+func (r *verificationsRepo) VerifyEmail(_ context.Context, tokenHash string) (int, error) {
+	for _, v := range r.verifications {
+		if v.TokenHash == tokenHash {
+			now := time.Now()
+			r.users[v.UserId-1].EmailVerifiedAt = &now
+			r.verifications = nil
+			return v.UserId, nil
+		}
+	}
+	return 0, sql.ErrNoRows
+}
+
+var verificationLinkRegexp = regexp.MustCompile(`http://localhost:8080/auth/verify\?token=[0-9a-f]{64}`)
+
+func TestEmailVerification(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	files, err := mailer.NewFile(dir, "todo@example.com")
+	require.NoError(t, err)
+
+	repo := &verificationsRepo{}
+	s := &Service{
+		repo:                       repo,
+		hasher:                     plainHasher{},
+		tokenManager:               auth.NewManager(authConfig{}, nil),
+		mailer:                     files,
+		verificationURL:            "http://localhost:8080/auth/verify",
+		verificationTTL:            time.Hour,
+		verificationResendInterval: time.Minute,
+		requireVerifiedEmail:       RequireVerifiedEmailForSignIn,
+	}
+
+	_, err = s.SignUp(ctx, "user@example.com", "user", "password", "")
+	require.NoError(t, err)
+
+	mails, _ := os.ReadDir(dir)
+	require.Len(t, mails, 1, "verification link must be sent on sign up")
+	mail, err := os.ReadFile(filepath.Join(dir, mails[0].Name()))
+	require.NoError(t, err)
+
+	link, err := url.Parse(verificationLinkRegexp.FindString(string(mail)))
+	require.NoError(t, err)
+
+	_, err = s.SignIn(ctx, "user", "password", entity.Client{})
+	assert.ErrorIs(t, err, entity.ErrEmailNotVerified)
+
+	// throttled resend looks the same as for an unknown email
+	assert.NoError(t, s.ResendVerification(ctx, "user@example.com"))
+	assert.NoError(t, s.ResendVerification(ctx, "unknown@example.com"))
+	mails, _ = os.ReadDir(dir)
+	assert.Len(t, mails, 1)
+
+	repo.verifications[0].CreatedAt = time.Now().Add(-2 * time.Minute)
+	assert.NoError(t, s.ResendVerification(ctx, "user@example.com"))
+	mails, _ = os.ReadDir(dir)
+	assert.Len(t, mails, 2)
+
+	assert.ErrorIs(t, s.VerifyEmail(ctx, "wrong"), entity.ErrInvalidVerificationToken)
+	require.NoError(t, s.VerifyEmail(ctx, link.Query().Get("token")))
+	assert.NotNil(t, repo.users[0].EmailVerifiedAt)
+
+	_, err = s.SignIn(ctx, "user", "password", entity.Client{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.ResendVerification(ctx, "user@example.com"))
+	mails, _ = os.ReadDir(dir)
+	assert.Len(t, mails, 2, "verified email must not get a link")
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/auth"
+	"github.com/pintoter/todo-list/pkg/logger"
+	"github.com/pintoter/todo-list/pkg/mailer"
+)
+
+// CreateWorkspace creates a workspace owned by the user.
+func (s *Service) CreateWorkspace(ctx context.Context, userId int, name string) (entity.Workspace, error) {
+	return s.repo.CreateWorkspace(ctx, name, userId)
+}
+
+// GetWorkspaces returns workspaces the user is a member of.
+func (s *Service) GetWorkspaces(ctx context.Context, userId int) ([]entity.Workspace, error) {
+	return s.repo.GetWorkspaces(ctx, userId)
+}
+
+// GetWorkspaceMember returns the membership of the user in the workspace, workspaces the user isn't
+// a member of don't exist for them.
+func (s *Service) GetWorkspaceMember(ctx context.Context, workspaceId, userId int) (entity.WorkspaceMember, error) {
+	member, err := s.repo.GetWorkspaceMember(ctx, workspaceId, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WorkspaceMember{}, entity.ErrWorkspaceNotExists
+		}
+		return entity.WorkspaceMember{}, err
+	}
+
+	return member, nil
+}
+
+// UpdateWorkspace renames the workspace, admins and the owner can do it.
+func (s *Service) UpdateWorkspace(ctx context.Context, workspaceId, userId int, name string) error {
+	if _, err := s.requireWorkspaceRole(ctx, workspaceId, userId, entity.WorkspaceRoleAdmin); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateWorkspace(ctx, workspaceId, name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrWorkspaceNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWorkspace deletes the workspace with its notes and lists, only the owner can do it.
+func (s *Service) DeleteWorkspace(ctx context.Context, workspaceId, userId int) error {
+	if _, err := s.requireWorkspaceRole(ctx, workspaceId, userId, entity.WorkspaceRoleOwner); err != nil {
+		return err
+	}
+
+	keys, err := s.repo.DeleteWorkspace(ctx, workspaceId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrWorkspaceNotExists
+		}
+		return err
+	}
+
+	s.deleteBlobs(ctx, keys)
+
+	logger.InfoKV(ctx, "Workspace deleted", "workspace_id", workspaceId, "user_id", userId)
+
+	return nil
+}
+
+// GetWorkspaceMembers returns members of the workspace, any member can see them.
+func (s *Service) GetWorkspaceMembers(ctx context.Context, workspaceId, userId int) ([]entity.WorkspaceMember, error) {
+	if _, err := s.requireWorkspaceRole(ctx, workspaceId, userId, entity.WorkspaceRoleGuest); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetWorkspaceMembers(ctx, workspaceId)
+}
+
+// UpdateWorkspaceMember changes the role of the member. Admins manage members and guests,
+// only the owner can grant or revoke admin and nobody can change the role of the owner.
+func (s *Service) UpdateWorkspaceMember(ctx context.Context, workspaceId, userId, memberId int, role string) error {
+	if !isInvitableRole(role) {
+		return entity.ErrInvalidWorkspaceRole
+	}
+
+	member, err := s.requireWorkspaceRole(ctx, workspaceId, userId, entity.WorkspaceRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.getWorkspaceMember(ctx, workspaceId, memberId)
+	if err != nil {
+		return err
+	}
+
+	if target.Role == entity.WorkspaceRoleOwner {
+		return entity.ErrWorkspaceOwner
+	}
+
+	if (target.Role == entity.WorkspaceRoleAdmin || role == entity.WorkspaceRoleAdmin) && member.Role != entity.WorkspaceRoleOwner {
+		return entity.ErrWorkspaceForbidden
+	}
+
+	if err = s.repo.UpdateWorkspaceMember(ctx, workspaceId, memberId, role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrWorkspaceMemberNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RemoveWorkspaceMember removes the member from the workspace. Members can leave the workspace themselves
+// except for the owner, admins remove members and guests while admins are removed only by the owner.
+func (s *Service) RemoveWorkspaceMember(ctx context.Context, workspaceId, userId, memberId int) error {
+	required := entity.WorkspaceRoleAdmin
+	if memberId == userId {
+		required = entity.WorkspaceRoleGuest
+	}
+
+	member, err := s.requireWorkspaceRole(ctx, workspaceId, userId, required)
+	if err != nil {
+		return err
+	}
+
+	target := member
+	if memberId != userId {
+		if target, err = s.getWorkspaceMember(ctx, workspaceId, memberId); err != nil {
+			return err
+		}
+	}
+
+	if target.Role == entity.WorkspaceRoleOwner {
+		return entity.ErrWorkspaceOwner
+	}
+
+	if target.Role == entity.WorkspaceRoleAdmin && memberId != userId && member.Role != entity.WorkspaceRoleOwner {
+		return entity.ErrWorkspaceForbidden
+	}
+
+	if err = s.repo.DeleteWorkspaceMember(ctx, workspaceId, memberId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrWorkspaceMemberNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// InviteToWorkspace emails an invitation to join the workspace with the role. Admins invite members
+// and guests, only the owner can invite admins.
+func (s *Service) InviteToWorkspace(ctx context.Context, workspaceId, userId int, email, role string) (entity.WorkspaceInvitation, error) {
+	if !isInvitableRole(role) {
+		return entity.WorkspaceInvitation{}, entity.ErrInvalidWorkspaceRole
+	}
+
+	member, err := s.requireWorkspaceRole(ctx, workspaceId, userId, entity.WorkspaceRoleAdmin)
+	if err != nil {
+		return entity.WorkspaceInvitation{}, err
+	}
+
+	if role == entity.WorkspaceRoleAdmin && member.Role != entity.WorkspaceRoleOwner {
+		return entity.WorkspaceInvitation{}, entity.ErrWorkspaceForbidden
+	}
+
+	token, err := auth.NewToken()
+	if err != nil {
+		return entity.WorkspaceInvitation{}, err
+	}
+
+	invitation := entity.WorkspaceInvitation{
+		WorkspaceId: workspaceId,
+		Email:       strings.ToLower(email),
+		Role:        role,
+		TokenHash:   auth.HashToken(token),
+		InvitedBy:   userId,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Now().Add(s.invitationTTL),
+	}
+
+	invitation.ID, err = s.repo.CreateWorkspaceInvitation(ctx, invitation)
+	if err != nil {
+		return entity.WorkspaceInvitation{}, err
+	}
+
+	msg := mailer.Message{
+		To:      invitation.Email,
+		Subject: "You are invited to a workspace",
+		Body: fmt.Sprintf("Hi,\n\n%s invited you to their workspace as %s. Sign in with this email and accept "+
+			"the invitation with the token below, it expires in %s:\n\n%s\n\n"+
+			"If you don't know the sender, ignore this email.\n", member.Login, role, s.invitationTTL, token),
+	}
+
+	// the invitation can be sent again, so failed delivery isn't reported to the client
+	if err = s.mailer.Send(ctx, msg); err != nil {
+		logger.ErrorKV(ctx, "Failed send workspace invitation", "workspace_id", workspaceId, "invitation_id", invitation.ID, "err", err)
+	}
+
+	return invitation, nil
+}
+
+// GetWorkspaceInvitations returns pending invitations of the workspace to admins.
+func (s *Service) GetWorkspaceInvitations(ctx context.Context, workspaceId, userId int) ([]entity.WorkspaceInvitation, error) {
+	if _, err := s.requireWorkspaceRole(ctx, workspaceId, userId, entity.WorkspaceRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetWorkspaceInvitations(ctx, workspaceId)
+}
+
+// DeleteWorkspaceInvitation revokes the pending invitation.
+func (s *Service) DeleteWorkspaceInvitation(ctx context.Context, id, workspaceId, userId int) error {
+	if _, err := s.requireWorkspaceRole(ctx, workspaceId, userId, entity.WorkspaceRoleAdmin); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteWorkspaceInvitation(ctx, id, workspaceId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ErrInvitationNotExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AcceptWorkspaceInvitation joins the user to the workspace by the invitation token, the invitation
+// must have been sent to the email of the user.
+func (s *Service) AcceptWorkspaceInvitation(ctx context.Context, userId int, token string) (entity.WorkspaceMember, error) {
+	user, err := s.repo.GetUserByID(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WorkspaceMember{}, entity.ErrUserNotExist
+		}
+		return entity.WorkspaceMember{}, err
+	}
+
+	invitation, err := s.repo.AcceptWorkspaceInvitation(ctx, auth.HashToken(token), strings.ToLower(user.Email), userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WorkspaceMember{}, entity.ErrInvitationNotExists
+		}
+		return entity.WorkspaceMember{}, err
+	}
+
+	logger.InfoKV(ctx, "Workspace invitation accepted", "workspace_id", invitation.WorkspaceId, "user_id", userId)
+
+	return s.GetWorkspaceMember(ctx, invitation.WorkspaceId, userId)
+}
+
+// requireWorkspaceRole returns the membership of the user in the workspace when their role is enough.
+func (s *Service) requireWorkspaceRole(ctx context.Context, workspaceId, userId int, role string) (entity.WorkspaceMember, error) {
+	member, err := s.GetWorkspaceMember(ctx, workspaceId, userId)
+	if err != nil {
+		return entity.WorkspaceMember{}, err
+	}
+
+	if !entity.WorkspaceRoleAllows(member.Role, role) {
+		return entity.WorkspaceMember{}, entity.ErrWorkspaceForbidden
+	}
+
+	return member, nil
+}
+
+func (s *Service) getWorkspaceMember(ctx context.Context, workspaceId, memberId int) (entity.WorkspaceMember, error) {
+	member, err := s.repo.GetWorkspaceMember(ctx, workspaceId, memberId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WorkspaceMember{}, entity.ErrWorkspaceMemberNotExists
+		}
+		return entity.WorkspaceMember{}, err
+	}
+
+	return member, nil
+}
+
+// checkWorkspaceRole rejects changes in the active workspace of the request when the role of the user
+// isn't enough, personal notes and lists are always allowed.
+func checkWorkspaceRole(ctx context.Context, role string) error {
+	member, ok := entity.WorkspaceFromContext(ctx)
+	if ok && !entity.WorkspaceRoleAllows(member.Role, role) {
+		return entity.ErrWorkspaceForbidden
+	}
+
+	return nil
+}
+
+// isInvitableRole reports whether the role can be granted, there is only one owner of a workspace.
+func isInvitableRole(role string) bool {
+	return role == entity.WorkspaceRoleAdmin || role == entity.WorkspaceRoleMember || role == entity.WorkspaceRoleGuest
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/repository"
+	"github.com/pintoter/todo-list/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type workspacesRepo struct {
+	repository.Repository
+
+	members     map[[2]int]string
+	invitations map[string]entity.WorkspaceInvitation
+}
+
+func (r *workspacesRepo) GetUserByID(_ context.Context, id int) (entity.User, error) {
+	return entity.User{ID: id, Login: "user", Email: "Bob@Example.com"}, nil
+}
+
+func (r *workspacesRepo) GetWorkspaceMember(_ context.Context, workspaceId, userId int) (entity.WorkspaceMember, error) {
+	role, ok := r.members[[2]int{workspaceId, userId}]
+	if !ok {
+		return entity.WorkspaceMember{}, sql.ErrNoRows
+	}
+	return entity.WorkspaceMember{WorkspaceId: workspaceId, UserId: userId, Role: role}, nil
+}
+
+func (r *workspacesRepo) UpdateWorkspaceMember(_ context.Context, workspaceId, userId int, role string) error {
+	r.members[[2]int{workspaceId, userId}] = role
+	return nil
+}
+
+func (r *workspacesRepo) DeleteWorkspaceMember(_ context.Context, workspaceId, userId int) error {
+	delete(r.members, [2]int{workspaceId, userId})
+	return nil
+}
+
+func (r *workspacesRepo) CreateWorkspaceInvitation(_ context.Context, invitation entity.WorkspaceInvitation) (int, error) {
+	r.invitations[invitation.TokenHash] = invitation
+	return len(r.invitations), nil
+}
+
+func (r *workspacesRepo) AcceptWorkspaceInvitation(_ context.Context, tokenHash, email string, userId int) (entity.WorkspaceInvitation, error) {
+	invitation, ok := r.invitations[tokenHash]
+	if !ok || invitation.Email != email {
+		return entity.WorkspaceInvitation{}, sql.ErrNoRows
+	}
+	delete(r.invitations, tokenHash)
+	r.members[[2]int{invitation.WorkspaceId, userId}] = invitation.Role
+	return invitation, nil
+}
+
+func TestWorkspaceRoles(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		workspace = 7
+		owner     = 1
+		admin     = 2
+		member    = 3
+		guest     = 4
+	)
+
+	repo := &workspacesRepo{
+		members: map[[2]int]string{
+			{workspace, owner}:  entity.WorkspaceRoleOwner,
+			{workspace, admin}:  entity.WorkspaceRoleAdmin,
+			{workspace, member}: entity.WorkspaceRoleMember,
+			{workspace, guest}:  entity.WorkspaceRoleGuest,
+		},
+	}
+	s := &Service{repo: repo}
+
+	_, err := s.GetWorkspaceMember(ctx, workspace, 9)
+	assert.ErrorIs(t, err, entity.ErrWorkspaceNotExists, "workspaces of others must be hidden")
+
+	guestCtx := entity.ContextWithWorkspace(ctx, entity.WorkspaceMember{WorkspaceId: workspace, UserId: guest, Role: entity.WorkspaceRoleGuest})
+	assert.ErrorIs(t, s.CreateNote(guestCtx, entity.Note{UserId: guest, Title: "Plan"}), entity.ErrWorkspaceForbidden)
+	assert.ErrorIs(t, s.CreateList(guestCtx, entity.List{UserId: guest, Name: "Team"}), entity.ErrWorkspaceForbidden)
+
+	assert.ErrorIs(t, s.UpdateWorkspaceMember(ctx, workspace, member, guest, entity.WorkspaceRoleMember), entity.ErrWorkspaceForbidden)
+	assert.ErrorIs(t, s.UpdateWorkspaceMember(ctx, workspace, admin, guest, entity.WorkspaceRoleOwner), entity.ErrInvalidWorkspaceRole)
+	assert.ErrorIs(t, s.UpdateWorkspaceMember(ctx, workspace, admin, member, entity.WorkspaceRoleAdmin), entity.ErrWorkspaceForbidden,
+		"only the owner grants admin")
+	assert.ErrorIs(t, s.UpdateWorkspaceMember(ctx, workspace, admin, owner, entity.WorkspaceRoleGuest), entity.ErrWorkspaceOwner)
+
+	require.NoError(t, s.UpdateWorkspaceMember(ctx, workspace, admin, guest, entity.WorkspaceRoleMember))
+	require.NoError(t, s.UpdateWorkspaceMember(ctx, workspace, owner, member, entity.WorkspaceRoleAdmin))
+	assert.Equal(t, entity.WorkspaceRoleAdmin, repo.members[[2]int{workspace, member}])
+
+	assert.ErrorIs(t, s.RemoveWorkspaceMember(ctx, workspace, admin, member), entity.ErrWorkspaceForbidden,
+		"only the owner removes admins")
+	assert.ErrorIs(t, s.RemoveWorkspaceMember(ctx, workspace, owner, owner), entity.ErrWorkspaceOwner)
+	assert.ErrorIs(t, s.RemoveWorkspaceMember(ctx, workspace, guest, 9), entity.ErrWorkspaceForbidden)
+
+	require.NoError(t, s.RemoveWorkspaceMember(ctx, workspace, guest, guest), "members can leave the workspace")
+	require.NoError(t, s.RemoveWorkspaceMember(ctx, workspace, owner, member))
+	assert.Len(t, repo.members, 2)
+}
+
+func TestWorkspaceInvitation(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	files, err := mailer.NewFile(dir, "todo@example.com")
+	require.NoError(t, err)
+
+	repo := &workspacesRepo{
+		members: map[[2]int]string{
+			{7, 1}: entity.WorkspaceRoleOwner,
+			{7, 2}: entity.WorkspaceRoleAdmin,
+		},
+		invitations: make(map[string]entity.WorkspaceInvitation),
+	}
+	s := &Service{repo: repo, mailer: files, invitationTTL: time.Hour}
+
+	_, err = s.InviteToWorkspace(ctx, 7, 2, "bob@example.com", entity.WorkspaceRoleAdmin)
+	assert.ErrorIs(t, err, entity.ErrWorkspaceForbidden, "only the owner invites admins")
+
+	invitation, err := s.InviteToWorkspace(ctx, 7, 2, "BOB@example.com", entity.WorkspaceRoleMember)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", invitation.Email)
+
+	mails, _ := os.ReadDir(dir)
+	require.Len(t, mails, 1)
+
+	mail, err := os.ReadFile(filepath.Join(dir, mails[0].Name()))
+	require.NoError(t, err)
+	token := string(resetTokenRegexp.Find(mail))[:64]
+
+	_, err = s.AcceptWorkspaceInvitation(ctx, 3, "wrong")
+	assert.ErrorIs(t, err, entity.ErrInvitationNotExists)
+
+	member, err := s.AcceptWorkspaceInvitation(ctx, 3, token)
+	require.NoError(t, err)
+	assert.Equal(t, entity.WorkspaceRoleMember, member.Role)
+
+	_, err = s.AcceptWorkspaceInvitation(ctx, 3, token)
+	assert.ErrorIs(t, err, entity.ErrInvitationNotExists, "invitation must be single use")
+}
@@ -0,0 +1,41 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Revoke user tokens
+// @Description Log the user out everywhere: delete all sessions and revoke access tokens issued so far
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Key header string true "admin key"
+// @Param id path int true "user id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /admin/users/{id}/revoke-tokens [post]
+func (h *Handler) revokeUserTokens(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	if err := h.service.RevokeUserTokens(r.Context(), id); err != nil {
+		if errors.Is(err, entity.ErrUserNotExist) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "user tokens revoked successfully"})
+}
@@ -0,0 +1,50 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Assign note
+// @Description Assign the note to the owner, a collaborator or a member of the workspace, zero or null assignee_id unassigns the note
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path int true "note id"
+// @Param input body assignNoteInput true "input"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/assignee [put]
+func (h *Handler) assignNote(w http.ResponseWriter, r *http.Request) {
+	var input assignNoteInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.AssignNote(r.Context(), input.NoteId, userId, input.AssigneeId); err != nil {
+		if errors.Is(err, entity.ErrAssigneeNoAccess) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "note assigned successfully"})
+}
@@ -0,0 +1,228 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Upload attachment
+// @Description Attach a file to the note with a multipart form, editors of the note can attach files. The type is sniffed from the content and must be one of the allowed types
+// @Tags attachments
+// @Accept mpfd
+// @Produce json
+// @Param id path int true "note id"
+// @Param file formData file true "screenshot or PDF"
+// @Success 201 {object} entity.Attachment
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/attachments [post]
+func (h *Handler) uploadAttachment(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.service.MaxAttachmentSize()+multipartOverhead)
+
+	// the form may be spilled to disk even when the input turns out invalid
+	defer func() {
+		if r.MultipartForm != nil {
+			_ = r.MultipartForm.RemoveAll()
+		}
+	}()
+
+	var input uploadAttachmentInput
+	if err := input.Set(r); err != nil {
+		if errors.Is(err, entity.ErrAttachmentTooLarge) {
+			renderJSON(w, r, http.StatusRequestEntityTooLarge, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		}
+		return
+	}
+	defer input.File.Close()
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	attachment, err := h.service.UploadAttachment(r.Context(), input.NoteId, userId, input.Name, input.Size, input.File)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidAttachment) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrAttachmentTooLarge) {
+			renderJSON(w, r, http.StatusRequestEntityTooLarge, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrAttachmentType) {
+			renderJSON(w, r, http.StatusUnsupportedMediaType, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, attachment)
+}
+
+// @Summary Get attachments
+// @Description Get attachments of the note oldest first, anyone who can see the note can see them
+// @Tags attachments
+// @Produce json
+// @Param id path int true "note id"
+// @Success 200 {object} getAttachmentsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/attachments [get]
+func (h *Handler) getAttachments(w http.ResponseWriter, r *http.Request) {
+	var input attachmentInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	attachments, err := h.service.GetAttachments(r.Context(), input.NoteId, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getAttachmentsResponse{Attachments: attachments})
+}
+
+// @Summary Download attachment
+// @Description Stream the file attached to the note, a single range of bytes can be requested with the Range header
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "note id"
+// @Param attachment_id path int true "attachment id"
+// @Param Range header string false "bytes=start-end"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 416 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/attachments/{attachment_id} [get]
+func (h *Handler) downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	var input attachmentInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	attachment, err := h.service.GetAttachment(r.Context(), input.NoteId, input.AttachmentId, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrAttachmentNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	rng, err := parseByteRange(r.Header.Get("Range"), attachment.Size)
+	if err != nil {
+		w.Header().Set("Content-Range", "bytes */"+strconv.FormatInt(attachment.Size, 10))
+		renderJSON(w, r, http.StatusRequestedRangeNotSatisfiable, errorResponse{err.Error()})
+		return
+	}
+
+	part := byteRange{Start: 0, Length: attachment.Size}
+	if rng != nil {
+		part = *rng
+	}
+
+	body, err := h.service.OpenAttachment(r.Context(), attachment, part.Start, part.Length)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+	defer body.Close()
+
+	code := http.StatusOK
+	if rng != nil {
+		code = http.StatusPartialContent
+		w.Header().Set("Content-Range", part.ContentRange(attachment.Size))
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(part.Length, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+
+	log.Printf("[Response] [%s] %s - Status code: [%d]", r.Method, r.URL.Path, code)
+
+	// the status is already sent, a failed copy can only be logged
+	if _, err = io.Copy(w, body); err != nil {
+		log.Printf("[Response] [%s] %s - Failed stream attachment: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+// @Summary Delete attachment
+// @Description Delete the attachment with its file. Uploaders can delete their attachments, the owner of a personal note and admins of a workspace can delete any
+// @Tags attachments
+// @Produce json
+// @Param id path int true "note id"
+// @Param attachment_id path int true "attachment id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/attachments/{attachment_id} [delete]
+func (h *Handler) deleteAttachment(w http.ResponseWriter, r *http.Request) {
+	var input attachmentInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteAttachment(r.Context(), input.NoteId, input.AttachmentId, userId); err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrAttachmentNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrAttachmentForbidden) || errors.Is(err, entity.ErrNoteForbidden) ||
+			errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "attachment deleted successfully"})
+}
@@ -0,0 +1,172 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Create comment
+// @Description Comment the note, anyone who can see the note can comment it. Users mentioned as @login who can see the note are notified
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "note id"
+// @Param input body commentInput true "input"
+// @Success 201 {object} entity.Comment
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/comments [post]
+func (h *Handler) createComment(w http.ResponseWriter, r *http.Request) {
+	var input commentInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	comment, err := h.service.CreateComment(r.Context(), input.NoteId, userId, input.Body)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, comment)
+}
+
+// @Summary Get comments
+// @Description Get comments of the note oldest first, pass next_cursor of the previous response to get the next page
+// @Tags comments
+// @Produce json
+// @Param id path int true "note id"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "number of comments, 20 by default and 100 at most"
+// @Success 200 {object} getCommentsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/comments [get]
+func (h *Handler) getComments(w http.ResponseWriter, r *http.Request) {
+	var input getCommentsRequest
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	page, err := h.service.GetComments(r.Context(), input.NoteId, userId, input.Cursor, input.Limit)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getCommentsResponse{
+		Comments:   page.Comments,
+		HasMore:    page.HasMore,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// @Summary Update comment
+// @Description Edit the comment, only the author can edit it. Users mentioned for the first time are notified
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "note id"
+// @Param comment_id path int true "comment id"
+// @Param input body commentInput true "input"
+// @Success 202 {object} entity.Comment
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/comments/{comment_id} [patch]
+func (h *Handler) updateComment(w http.ResponseWriter, r *http.Request) {
+	var input commentInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	comment, err := h.service.UpdateComment(r.Context(), input.NoteId, input.CommentId, userId, input.Body)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrCommentNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrCommentForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, comment)
+}
+
+// @Summary Delete comment
+// @Description Delete the comment, authors delete their comments while the owner of the note or admins of the workspace can delete any
+// @Tags comments
+// @Produce json
+// @Param id path int true "note id"
+// @Param comment_id path int true "comment id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/comments/{comment_id} [delete]
+func (h *Handler) deleteComment(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	commentId, _ := strconv.Atoi(mux.Vars(r)["comment_id"])
+	if id == 0 || commentId == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteComment(r.Context(), id, commentId, userId); err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrCommentNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrCommentForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "comment deleted successfully"})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pintoter/todo-list/internal/config"
+	"github.com/pintoter/todo-list/internal/entity"
 	"github.com/pintoter/todo-list/internal/service"
 	"github.com/pintoter/todo-list/pkg/auth"
 	"github.com/pintoter/todo-list/pkg/logger"
@@ -21,10 +22,11 @@ type Handler struct {
 	tokenManager auth.TokenManager
 }
 
-func NewHandler(service *service.Service, cfg Config) *Handler {
+func NewHandler(service *service.Service, tokenManager auth.TokenManager, cfg Config) *Handler {
 	handler := &Handler{
-		router:  mux.NewRouter(),
-		service: service,
+		router:       mux.NewRouter(),
+		service:      service,
+		tokenManager: tokenManager,
 	}
 
 	if cfg.GetMode() != config.Production {
@@ -42,23 +44,102 @@ func NewHandler(service *service.Service, cfg Config) *Handler {
 }
 
 func (h *Handler) InitRoutes() {
+	h.router.HandleFunc("/.well-known/jwks.json", h.getJWKS).Methods(http.MethodGet)
+
 	auth := h.router.PathPrefix("/auth").Subrouter()
 	{
 		auth.HandleFunc("/sign-up", h.signUp).Methods(http.MethodPost)
 		auth.HandleFunc("/sign-in", h.signIn).Methods(http.MethodPost)
+		auth.HandleFunc("/sign-in/2fa", h.signInTwoFactor).Methods(http.MethodPost)
 		auth.HandleFunc("/refresh", h.refresh).Methods(http.MethodPost)
+		auth.HandleFunc("/verify", h.verifyEmail).Methods(http.MethodGet)
+		auth.HandleFunc("/verify/resend", h.resendVerification).Methods(http.MethodPost)
+		auth.HandleFunc("/password/forgot", h.forgotPassword).Methods(http.MethodPost)
+		auth.HandleFunc("/password/reset", h.resetPassword).Methods(http.MethodPost)
+		auth.Handle("/logout", h.authMiddleware(h.requireSession(h.logout))).Methods(http.MethodPost)
+	}
+
+	sessions := auth.PathPrefix("/sessions").Subrouter()
+	{
+		sessions.Use(h.authMiddleware)
+		sessions.HandleFunc("", h.requireSession(h.getSessions)).Methods(http.MethodGet)
+		sessions.HandleFunc("", h.requireSession(h.deleteSessions)).Methods(http.MethodDelete)
+		sessions.HandleFunc("/{id:[0-9]+}", h.requireSession(h.deleteSession)).Methods(http.MethodDelete)
+	}
+
+	admin := h.router.PathPrefix("/admin").Subrouter()
+	{
+		admin.Use(h.adminMiddleware)
+		admin.HandleFunc("/users/{id:[0-9]+}/revoke-tokens", h.revokeUserTokens).Methods(http.MethodPost)
 	}
 
 	v1 := h.router.PathPrefix("/api/v1").Subrouter()
 	{
 		v1.Use(h.authMiddleware)
-		v1.HandleFunc("/note", h.createNote).Methods(http.MethodPost)
-		v1.HandleFunc("/note/{id:[0-9]+}", h.getNote).Methods(http.MethodGet)
-		v1.HandleFunc("/note/{id:[0-9]+}", h.updateNote).Methods(http.MethodPatch)
-		v1.HandleFunc("/note/{id:[0-9]+}", h.deleteNote).Methods(http.MethodDelete)
-		v1.HandleFunc("/notes", h.getNotes).Methods(http.MethodGet)
-		v1.HandleFunc("/notes", h.deleteNotes).Methods(http.MethodDelete)
-		v1.HandleFunc("/notes/{page:[0-9]+}", h.getNotesExtended).Methods(http.MethodPost)
+		v1.HandleFunc("/user", h.requireSession(h.updateUser)).Methods(http.MethodPatch)
+		v1.HandleFunc("/2fa/enroll", h.requireSession(h.enrollTwoFactor)).Methods(http.MethodPost)
+		v1.HandleFunc("/2fa/confirm", h.requireSession(h.confirmTwoFactor)).Methods(http.MethodPost)
+		v1.HandleFunc("/2fa/disable", h.requireSession(h.disableTwoFactor)).Methods(http.MethodPost)
+		v1.HandleFunc("/tokens", h.requireSession(h.getPersonalAccessTokens)).Methods(http.MethodGet)
+		v1.HandleFunc("/tokens", h.requireSession(h.createPersonalAccessToken)).Methods(http.MethodPost)
+		v1.HandleFunc("/tokens/{id:[0-9]+}", h.requireSession(h.deletePersonalAccessToken)).Methods(http.MethodDelete)
+		v1.HandleFunc("/workspaces", h.requireSession(h.getWorkspaces)).Methods(http.MethodGet)
+		v1.HandleFunc("/workspaces", h.requireSession(h.createWorkspace)).Methods(http.MethodPost)
+		v1.HandleFunc("/workspaces/invitations/accept", h.requireSession(h.acceptWorkspaceInvitation)).Methods(http.MethodPost)
+		v1.HandleFunc("/workspaces/{id:[0-9]+}", h.requireSession(h.updateWorkspace)).Methods(http.MethodPatch)
+		v1.HandleFunc("/workspaces/{id:[0-9]+}", h.requireSession(h.deleteWorkspace)).Methods(http.MethodDelete)
+		v1.HandleFunc("/workspaces/{id:[0-9]+}/members", h.requireSession(h.getWorkspaceMembers)).Methods(http.MethodGet)
+		v1.HandleFunc("/workspaces/{id:[0-9]+}/members/{user_id:[0-9]+}", h.requireSession(h.updateWorkspaceMember)).Methods(http.MethodPatch)
+		v1.HandleFunc("/workspaces/{id:[0-9]+}/members/{user_id:[0-9]+}", h.requireSession(h.removeWorkspaceMember)).Methods(http.MethodDelete)
+		v1.HandleFunc("/workspaces/{id:[0-9]+}/invitations", h.requireSession(h.getWorkspaceInvitations)).Methods(http.MethodGet)
+		v1.HandleFunc("/workspaces/{id:[0-9]+}/invitations", h.requireSession(h.inviteToWorkspace)).Methods(http.MethodPost)
+		v1.HandleFunc("/workspaces/{id:[0-9]+}/invitations/{invitation_id:[0-9]+}", h.requireSession(h.deleteWorkspaceInvitation)).Methods(http.MethodDelete)
+		v1.HandleFunc("/note", h.requireScope(entity.ScopeNotesWrite, h.createNote)).Methods(http.MethodPost)
+		v1.HandleFunc("/note/{id:[0-9]+}", h.requireScope(entity.ScopeNotesRead, h.getNote)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.updateNote)).Methods(http.MethodPatch)
+		v1.HandleFunc("/note/{id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.deleteNote)).Methods(http.MethodDelete)
+		v1.HandleFunc("/note/{id:[0-9]+}/assignee", h.requireScope(entity.ScopeNotesWrite, h.assignNote)).Methods(http.MethodPut)
+		v1.HandleFunc("/note/{id:[0-9]+}/comments", h.requireScope(entity.ScopeNotesRead, h.getComments)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}/comments", h.requireScope(entity.ScopeNotesWrite, h.createComment)).Methods(http.MethodPost)
+		v1.HandleFunc("/note/{id:[0-9]+}/comments/{comment_id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.updateComment)).Methods(http.MethodPatch)
+		v1.HandleFunc("/note/{id:[0-9]+}/comments/{comment_id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.deleteComment)).Methods(http.MethodDelete)
+		v1.HandleFunc("/note/{id:[0-9]+}/attachments", h.requireScope(entity.ScopeNotesRead, h.getAttachments)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}/attachments", h.requireScope(entity.ScopeNotesWrite, h.uploadAttachment)).Methods(http.MethodPost)
+		v1.HandleFunc("/note/{id:[0-9]+}/attachments/{attachment_id:[0-9]+}", h.requireScope(entity.ScopeNotesRead, h.downloadAttachment)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}/attachments/{attachment_id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.deleteAttachment)).Methods(http.MethodDelete)
+		v1.HandleFunc("/note/{id:[0-9]+}/occurrences", h.requireScope(entity.ScopeNotesRead, h.getOccurrences)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}/items", h.requireScope(entity.ScopeNotesRead, h.getItems)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}/items", h.requireScope(entity.ScopeNotesWrite, h.createItem)).Methods(http.MethodPost)
+		v1.HandleFunc("/note/{id:[0-9]+}/items/order", h.requireScope(entity.ScopeNotesWrite, h.reorderItems)).Methods(http.MethodPut)
+		v1.HandleFunc("/note/{id:[0-9]+}/items/{item_id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.updateItem)).Methods(http.MethodPatch)
+		v1.HandleFunc("/note/{id:[0-9]+}/items/{item_id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.deleteItem)).Methods(http.MethodDelete)
+		v1.HandleFunc("/note/{id:[0-9]+}/restore", h.requireScope(entity.ScopeNotesWrite, h.restoreNote)).Methods(http.MethodPost)
+		v1.HandleFunc("/note/{id:[0-9]+}/history", h.requireScope(entity.ScopeNotesRead, h.getHistory)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}/history/{revision_id:[0-9]+}/revert", h.requireScope(entity.ScopeNotesWrite, h.revertNote)).Methods(http.MethodPost)
+		v1.HandleFunc("/note/{id:[0-9]+}/diff", h.requireScope(entity.ScopeNotesRead, h.getDiff)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}/shares", h.requireScope(entity.ScopeNotesRead, h.getNoteShares)).Methods(http.MethodGet)
+		v1.HandleFunc("/note/{id:[0-9]+}/shares", h.requireScope(entity.ScopeNotesWrite, h.shareNote)).Methods(http.MethodPost)
+		v1.HandleFunc("/note/{id:[0-9]+}/shares/{user_id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.unshareNote)).Methods(http.MethodDelete)
+		v1.HandleFunc("/notes", h.requireScope(entity.ScopeNotesRead, h.getNotes)).Methods(http.MethodGet)
+		v1.HandleFunc("/notes", h.requireScope(entity.ScopeNotesWrite, h.deleteNotes)).Methods(http.MethodDelete)
+		v1.HandleFunc("/notes", h.requireScope(entity.ScopeNotesRead, h.getNotesExtended)).Methods(http.MethodPost)
+		v1.HandleFunc("/notes/{page:[0-9]+}", h.requireScope(entity.ScopeNotesRead, h.getNotesExtendedByPage)).Methods(http.MethodPost)
+		v1.HandleFunc("/notes/move", h.requireScope(entity.ScopeNotesWrite, h.moveNotes)).Methods(http.MethodPost)
+		v1.HandleFunc("/notes/shared", h.requireScope(entity.ScopeNotesRead, h.getSharedNotes)).Methods(http.MethodGet)
+		v1.HandleFunc("/notifications", h.requireScope(entity.ScopeNotesRead, h.getNotifications)).Methods(http.MethodGet)
+		v1.HandleFunc("/notifications/read", h.requireScope(entity.ScopeNotesWrite, h.readNotifications)).Methods(http.MethodPost)
+		v1.HandleFunc("/notifications/{id:[0-9]+}/read", h.requireScope(entity.ScopeNotesWrite, h.readNotification)).Methods(http.MethodPost)
+		v1.HandleFunc("/trash", h.requireScope(entity.ScopeNotesRead, h.getTrash)).Methods(http.MethodGet)
+		v1.HandleFunc("/trash", h.requireScope(entity.ScopeNotesWrite, h.emptyTrash)).Methods(http.MethodDelete)
+		v1.HandleFunc("/trash/{id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.deleteNotePermanently)).Methods(http.MethodDelete)
+		v1.HandleFunc("/lists", h.requireScope(entity.ScopeNotesRead, h.getLists)).Methods(http.MethodGet)
+		v1.HandleFunc("/lists", h.requireScope(entity.ScopeNotesWrite, h.createList)).Methods(http.MethodPost)
+		v1.HandleFunc("/lists/{id:[0-9]+}", h.requireScope(entity.ScopeNotesRead, h.getList)).Methods(http.MethodGet)
+		v1.HandleFunc("/lists/{id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.updateList)).Methods(http.MethodPatch)
+		v1.HandleFunc("/lists/{id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.deleteList)).Methods(http.MethodDelete)
+		v1.HandleFunc("/tags", h.requireScope(entity.ScopeNotesRead, h.getTags)).Methods(http.MethodGet)
+		v1.HandleFunc("/tag/{id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.updateTag)).Methods(http.MethodPatch)
+		v1.HandleFunc("/tag/{id:[0-9]+}", h.requireScope(entity.ScopeNotesWrite, h.deleteTag)).Methods(http.MethodDelete)
 	}
 }
 
@@ -0,0 +1,216 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Get note items
+// @Description Get checklist items of the note in their order with completion progress in percent
+// @Tags items
+// @Produce json
+// @Param id path int true "note id"
+// @Success 200 {object} getItemsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/items [get]
+func (h *Handler) getItems(w http.ResponseWriter, r *http.Request) {
+	noteId, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if noteId == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	items, err := h.service.GetItems(r.Context(), noteId, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getItemsResponse{Items: items, Progress: entity.ItemsProgress(items)})
+}
+
+// @Summary Create note item
+// @Description Add checklist item to the end of the note's items
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path int true "note id"
+// @Param input body createItemInput true "item info"
+// @Success 201 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/items [post]
+func (h *Handler) createItem(w http.ResponseWriter, r *http.Request) {
+	var input createItemInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	_, err := h.service.CreateItem(r.Context(), entity.Item{
+		NoteId:  input.NoteId,
+		Title:   input.Title,
+		Checked: input.Checked,
+	}, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, successCUDResponse{Message: "item created successfully"})
+}
+
+// @Summary Update note item
+// @Description Rename, check or uncheck item by id
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path int true "note id"
+// @Param item_id path int true "item id"
+// @Param input body updateItemInput true "updating params"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/items/{item_id} [patch]
+func (h *Handler) updateItem(w http.ResponseWriter, r *http.Request) {
+	var input updateItemInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	err := h.service.UpdateItem(r.Context(), input.ID, input.NoteId, input.Update(), userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrItemNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "item updated successfully"})
+}
+
+// @Summary Reorder note items
+// @Description Set order of the note's items, ids must contain every item of the note exactly once
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path int true "note id"
+// @Param input body reorderItemsInput true "item ids in the new order"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/items/order [put]
+func (h *Handler) reorderItems(w http.ResponseWriter, r *http.Request) {
+	var input reorderItemsInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	err := h.service.ReorderItems(r.Context(), input.NoteId, input.IDs, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrInvalidItemsOrder) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "items reordered successfully"})
+}
+
+// @Summary Delete note item
+// @Description Delete item by id
+// @Tags items
+// @Produce json
+// @Param id path int true "note id"
+// @Param item_id path int true "item id"
+// @Success 200 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/items/{item_id} [delete]
+func (h *Handler) deleteItem(w http.ResponseWriter, r *http.Request) {
+	noteId, _ := strconv.Atoi(mux.Vars(r)["id"])
+	id, _ := strconv.Atoi(mux.Vars(r)["item_id"])
+	if noteId == 0 || id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteItem(r.Context(), id, noteId, userId); err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrItemNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, successCUDResponse{Message: "item deleted successfully"})
+}
@@ -0,0 +1,20 @@
+package transport
+
+import (
+	"net/http"
+)
+
+// jwksMaxAge lets verifiers cache keys, upcoming keys are published ahead, so it only delays revocation of a key.
+const jwksMaxAge = "public, max-age=300"
+
+// @Summary Get token signing keys
+// @Description Get public keys access tokens can be verified with, the kid header of a token points at its key.
+// @Description The set is empty while tokens are signed with the shared secret.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) getJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", jwksMaxAge)
+	renderJSON(w, r, http.StatusOK, h.tokenManager.JWKS())
+}
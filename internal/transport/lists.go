@@ -0,0 +1,231 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Create list
+// @Description Create list to group notes
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param input body listInput true "list info"
+// @Success 201 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/lists [post]
+func (h *Handler) createList(w http.ResponseWriter, r *http.Request) {
+	var input listInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	err := h.service.CreateList(r.Context(), entity.List{UserId: userId, Name: input.Name})
+	if err != nil {
+		if errors.Is(err, entity.ErrListExists) {
+			renderJSON(w, r, http.StatusConflict, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, successCUDResponse{Message: "list created successfully"})
+}
+
+// @Summary Get all lists
+// @Description Get all user's lists with notes count
+// @Tags lists
+// @Produce json
+// @Success 200 {object} getListsResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/lists [get]
+func (h *Handler) getLists(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	lists, err := h.service.GetLists(r.Context(), userId)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getListsResponse{Lists: lists})
+}
+
+// @Summary Get list by id
+// @Description Get list by id with notes count
+// @Tags lists
+// @Produce json
+// @Param id path int true "id"
+// @Success 200 {object} getListResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/lists/{id} [get]
+func (h *Handler) getList(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	list, err := h.service.GetListById(r.Context(), id, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrListNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getListResponse{List: list})
+}
+
+// @Summary Rename list
+// @Description Rename list by id
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path int true "id"
+// @Param input body listInput true "new list name"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/lists/{id} [patch]
+func (h *Handler) updateList(w http.ResponseWriter, r *http.Request) {
+	var input listInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	err := h.service.UpdateList(r.Context(), input.ID, input.Name, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrListNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrListExists) {
+			renderJSON(w, r, http.StatusConflict, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "list updated successfully"})
+}
+
+// @Summary Delete list
+// @Description Delete list by id, its notes are moved to the inbox or to the trash with cascade=true
+// @Tags lists
+// @Produce json
+// @Param id path int true "id"
+// @Param cascade query bool false "move notes of the list to the trash"
+// @Success 200 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/lists/{id} [delete]
+func (h *Handler) deleteList(w http.ResponseWriter, r *http.Request) {
+	var input deleteListRequest
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteList(r.Context(), input.ID, userId, input.Cascade); err != nil {
+		if errors.Is(err, entity.ErrListNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, successCUDResponse{Message: "list deleted successfully"})
+}
+
+// @Summary Move notes
+// @Description Move notes into the list, list_id 0 moves them to the inbox
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param input body moveNotesInput true "notes and target list"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/notes/move [post]
+func (h *Handler) moveNotes(w http.ResponseWriter, r *http.Request) {
+	var input moveNotesInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.MoveNotes(r.Context(), input.NoteIds, input.ListId, userId); err != nil {
+		if errors.Is(err, entity.ErrListNotExists) || errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "notes moved successfully"})
+}
@@ -2,8 +2,14 @@ package transport
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/internal/service"
+	"github.com/pintoter/todo-list/pkg/logger"
 )
 
 func (h *Handler) authMiddleware(next http.Handler) http.Handler {
@@ -25,14 +31,108 @@ func (h *Handler) authMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		userId, err := h.tokenManager.ParseToken(headerParts[1])
+		if service.IsPersonalAccessToken(headerParts[1]) {
+			token, err := h.service.CheckPersonalAccessToken(r.Context(), headerParts[1])
+			if err != nil {
+				if !errors.Is(err, entity.ErrTokenNotExists) {
+					logger.ErrorKV(r.Context(), "Failed check personal access token", "err", err)
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "user_id", token.UserId)
+			ctx = context.WithValue(ctx, "access_token", token)
+
+			h.serveInWorkspace(w, r.WithContext(ctx), token.UserId, next)
+			return
+		}
+
+		claims, err := h.tokenManager.ParseToken(headerParts[1])
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), "user_id", userId)
+		if err = h.service.CheckAccessToken(r.Context(), claims); err != nil {
+			if !errors.Is(err, entity.ErrTokenRevoked) {
+				logger.ErrorKV(r.Context(), "Failed check access token", "err", err)
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
 
-		r = r.WithContext(ctx)
+		ctx := context.WithValue(r.Context(), "user_id", claims.UserId)
+		ctx = context.WithValue(ctx, "session_id", claims.SessionId)
+		ctx = context.WithValue(ctx, "claims", claims)
+
+		h.serveInWorkspace(w, r.WithContext(ctx), claims.UserId, next)
 	})
 }
+
+// serveInWorkspace activates the workspace of the X-Workspace-Id header when the user is its member,
+// requests without the header work with personal notes and lists.
+func (h *Handler) serveInWorkspace(w http.ResponseWriter, r *http.Request, userId int, next http.Handler) {
+	header := r.Header.Get("X-Workspace-Id")
+	if header == "" {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	workspaceId, err := strconv.Atoi(header)
+	if err != nil || workspaceId <= 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidWorkspaceId.Error()})
+		return
+	}
+
+	member, err := h.service.GetWorkspaceMember(r.Context(), workspaceId, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrWorkspaceNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(entity.ContextWithWorkspace(r.Context(), member)))
+}
+
+// adminMiddleware authorizes admin requests by the key of the X-Admin-Key header.
+func (h *Handler) adminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h.service.CheckAdminKey(r.Header.Get("X-Admin-Key")); err != nil {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireScope limits requests authorized by a personal access token to tokens with the scope,
+// requests with a JWT of a session are allowed everything.
+func (h *Handler) requireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := r.Context().Value("access_token").(entity.PersonalAccessToken)
+		if ok && !token.HasScope(scope) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{entity.ErrInsufficientScope.Error()})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// requireSession rejects requests authorized by a personal access token, so tokens can't manage
+// the account, sessions or other tokens.
+func (h *Handler) requireSession(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value("access_token").(entity.PersonalAccessToken); ok {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{entity.ErrSessionRequired.Error()})
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -17,6 +17,8 @@ import (
 // @Param input body createNoteInput true "note info"
 // @Success 201 {object} successCUDResponse
 // @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
 // @Failure 409 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/note [post]
@@ -33,17 +35,41 @@ func (h *Handler) createNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if input.DueAt != "" {
+		loc, err := h.service.GetUserLocation(r.Context(), userId)
+		if err != nil {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+			return
+		}
+
+		if err = input.ResolveDueAt(loc); err != nil {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+			return
+		}
+	}
+
 	err := h.service.CreateNote(r.Context(), entity.Note{
 		UserId:      userId,
 		Title:       input.Title,
 		Description: input.Description,
 		Date:        input.DateFormatted,
 		Status:      input.Status,
+		Priority:    input.Priority,
+		DueAt:       input.DueAtFormatted,
+		Recurrence:  input.Recurrence,
+		ListId:      input.ListId,
+		Tags:        input.Tags,
 	})
 
 	if err != nil {
 		if errors.Is(err, entity.ErrNoteExists) {
 			renderJSON(w, r, http.StatusConflict, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrListNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrEmailNotVerified) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
 		} else {
 			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
 		}
@@ -86,7 +112,19 @@ func (h *Handler) getNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderJSON(w, r, http.StatusOK, getNoteResponse{Note: note})
+	items, err := h.service.GetItems(r.Context(), id, userId)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	response := getNoteResponse{Note: note, Items: items}
+	if len(items) > 0 {
+		progress := entity.ItemsProgress(items)
+		response.Progress = &progress
+	}
+
+	renderJSON(w, r, http.StatusOK, response)
 }
 
 // @Summary Get all notes
@@ -103,11 +141,11 @@ func (h *Handler) getNotes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	renderJSON(w, r, http.StatusOK, getNotesResponse{Notes: notes})
+	renderJSON(w, r, http.StatusOK, getNotesResponse{Notes: notes, Total: len(notes)})
 }
 
 // @Summary Get notes with filter
-// @Description Get notes with filter
+// @Description Get notes with filter by page number
 // @Tags notes
 // @Accept json
 // @Produce json
@@ -117,6 +155,20 @@ func (h *Handler) getNotes(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/notes/{page} [post]
+func (h *Handler) getNotesExtendedByPage(w http.ResponseWriter, r *http.Request) {
+	h.getNotesExtended(w, r)
+}
+
+// @Summary Get notes with filter
+// @Description Get notes with filter by cursor, pass next_cursor or prev_cursor of the previous response to get the adjacent page
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param input body getNotesRequest true "searching params"
+// @Success 200 {object} getNotesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/notes [post]
 func (h *Handler) getNotesExtended(w http.ResponseWriter, r *http.Request) {
 	var input getNotesRequest
 	if err := input.Set(r); err != nil {
@@ -130,17 +182,23 @@ func (h *Handler) getNotesExtended(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	notes, err := h.service.GetNotesExtended(r.Context(), input.Limit, (input.Page-1)*input.Limit, input.Status, input.DateFormatted, userId)
+	page, err := h.service.GetNotesExtended(r.Context(), input.Limit, input.Offset(), input.Filter(userId), userId)
 	if err != nil {
-		if errors.Is(err, entity.ErrInvalidStatus) {
-			renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidStatus.Error()})
+		if errors.Is(err, entity.ErrInvalidStatus) || errors.Is(err, entity.ErrInvalidCursor) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
 		} else {
 			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
 		}
 		return
 	}
 
-	renderJSON(w, r, http.StatusOK, getNotesResponse{Notes: notes})
+	renderJSON(w, r, http.StatusOK, getNotesResponse{
+		Notes:      page.Notes,
+		Total:      page.Total,
+		HasMore:    page.HasMore,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	})
 }
 
 // @Summary Update note
@@ -152,6 +210,7 @@ func (h *Handler) getNotesExtended(w http.ResponseWriter, r *http.Request) {
 // @Param input body updateNoteInput true "updating params"
 // @Success 202 {object} successCUDResponse
 // @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/note/{id} [patch]
 func (h *Handler) updateNote(w http.ResponseWriter, r *http.Request) {
@@ -168,12 +227,29 @@ func (h *Handler) updateNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.UpdateNote(r.Context(), input.ID, input.Title, input.Description, input.Status, userId)
+	if input.DueAt != "" {
+		loc, err := h.service.GetUserLocation(r.Context(), userId)
+		if err != nil {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+			return
+		}
+
+		if err = input.ResolveDueAt(loc); err != nil {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+			return
+		}
+	}
+
+	err = h.service.UpdateNote(r.Context(), input.ID, input.Update(), userId)
 	if err != nil {
 		if errors.Is(err, entity.ErrNoteNotExists) {
 			renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrNoteNotExists.Error()})
 		} else if errors.Is(err, entity.ErrNoteExists) {
 			renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrNoteExists.Error() + " with title: " + input.Title})
+		} else if errors.Is(err, entity.ErrListNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
 		} else {
 			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
 		}
@@ -184,12 +260,13 @@ func (h *Handler) updateNote(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Delete note
-// @Description Delete note by id
+// @Description Move note to the trash by id
 // @Tags notes
 // @Produce json
 // @Param id path int true "id"
 // @Success 200 {object} successCUDResponse
 // @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/note/{id} [delete]
 func (h *Handler) deleteNote(w http.ResponseWriter, r *http.Request) {
@@ -209,6 +286,9 @@ func (h *Handler) deleteNote(w http.ResponseWriter, r *http.Request) {
 		if errors.Is(err, entity.ErrNoteExists) {
 			renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrNoteExists.Error()})
 			return
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+			return
 		} else {
 			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
 			return
@@ -219,8 +299,10 @@ func (h *Handler) deleteNote(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Delete notes
+// @Description Move all notes to the trash
 // @Tags notes
 // @Success 200 {object} successCUDResponse
+// @Failure 403 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /api/v1/notes [delete]
 func (h *Handler) deleteNotes(w http.ResponseWriter, r *http.Request) {
@@ -231,9 +313,52 @@ func (h *Handler) deleteNotes(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.DeleteNotes(r.Context(), userId); err != nil {
-		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
 		return
 	}
 
 	renderJSON(w, r, http.StatusOK, successCUDResponse{Message: "notes deleted succesfully"})
 }
+
+// @Summary Preview note occurrences
+// @Description Get next occurrences of the recurring note
+// @Tags notes
+// @Produce json
+// @Param id path int true "id"
+// @Param count query int false "number of occurrences, 5 by default, up to 100"
+// @Success 200 {object} getOccurrencesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/occurrences [get]
+func (h *Handler) getOccurrences(w http.ResponseWriter, r *http.Request) {
+	var input getOccurrencesRequest
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	occurrences, err := h.service.GetNextOccurrences(r.Context(), input.ID, input.Count, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoRecurrence) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getOccurrencesResponse{Occurrences: occurrences})
+}
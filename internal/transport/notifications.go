@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Get notifications
+// @Description Get the latest notifications of the user, the newest first
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "only unread notifications"
+// @Param limit query int false "number of notifications, 20 by default and 100 at most"
+// @Success 200 {object} getNotificationsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/notifications [get]
+func (h *Handler) getNotifications(w http.ResponseWriter, r *http.Request) {
+	var input getNotificationsRequest
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	notifications, err := h.service.GetNotifications(r.Context(), userId, input.Unread, input.Limit)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getNotificationsResponse{Notifications: notifications})
+}
+
+// @Summary Read notification
+// @Description Mark the notification as read
+// @Tags notifications
+// @Produce json
+// @Param id path int true "notification id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/notifications/{id}/read [post]
+func (h *Handler) readNotification(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.ReadNotification(r.Context(), id, userId); err != nil {
+		if errors.Is(err, entity.ErrNotificationNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "notification read successfully"})
+}
+
+// @Summary Read all notifications
+// @Description Mark all notifications of the user as read
+// @Tags notifications
+// @Produce json
+// @Success 202 {object} successCUDResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/notifications/read [post]
+func (h *Handler) readNotifications(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.ReadNotifications(r.Context(), userId); err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "notifications read successfully"})
+}
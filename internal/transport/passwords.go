@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Forgot password
+// @Description Email a password reset token, the response is the same whether the email is registered or not
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body forgotPasswordInput true "input"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/password/forgot [post]
+func (h *Handler) forgotPassword(w http.ResponseWriter, r *http.Request) {
+	var input forgotPasswordInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	if err := h.service.ForgotPassword(r.Context(), input.Email); err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "if the email is registered, a reset token has been sent to it"})
+}
+
+// @Summary Reset password
+// @Description Set a new password by the reset token, all sessions of the user are ended
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body resetPasswordInput true "input"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/password/reset [post]
+func (h *Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var input resetPasswordInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), input.Token, input.Password); err != nil {
+		if errors.Is(err, entity.ErrInvalidResetToken) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "password reset successfully"})
+}
@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Create personal access token
+// @Description Create a long-lived token for scripts limited to the scopes notes:read and notes:write, it's shown only once
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Param input body createTokenInput true "input"
+// @Success 201 {object} createTokenResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tokens [post]
+func (h *Handler) createPersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input createTokenInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	token, pat, err := h.service.CreatePersonalAccessToken(r.Context(), userId, input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidScope) || errors.Is(err, entity.ErrInvalidExpiresAt) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, createTokenResponse{Token: token, PersonalAccessToken: pat})
+}
+
+// @Summary Get personal access tokens
+// @Description Get personal access tokens of the user with their scopes and last use
+// @Tags tokens
+// @Produce json
+// @Success 200 {object} getTokensResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tokens [get]
+func (h *Handler) getPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	tokens, err := h.service.GetPersonalAccessTokens(r.Context(), userId)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getTokensResponse{Tokens: tokens})
+}
+
+// @Summary Delete personal access token
+// @Description Revoke the personal access token by id
+// @Tags tokens
+// @Produce json
+// @Param id path int true "id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tokens/{id} [delete]
+func (h *Handler) deletePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeletePersonalAccessToken(r.Context(), id, userId); err != nil {
+		if errors.Is(err, entity.ErrTokenNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "token deleted successfully"})
+}
@@ -2,27 +2,120 @@ package transport
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/mail"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gorilla/mux"
 	"github.com/pintoter/todo-list/internal/entity"
 )
 
 const (
-	dateFormat = "2006-01-02"
+	dateFormat          = "2006-01-02"
+	localDateTimeFormat = "2006-01-02T15:04:05"
+	localDateTimeShort  = "2006-01-02T15:04"
+	maxTagLength        = 32
+	maxItemTitleLength  = 255
+	maxListNameLength   = 64
+	maxQueryLength      = 256
+	maxDeviceLength     = 64
+	maxUserAgentLength  = 255
 )
 
+/* ------------- DUE DATES ------------- */
+
+// dueAtInput accepts due date-time in RFC 3339 or as a wall clock time without offset,
+// the latter is interpreted in TimeZone or in the user's default time zone.
+type dueAtInput struct {
+	DueAt          string     `json:"due_at,omitempty" example:"2024-01-05T17:00:00+01:00"`
+	TimeZone       string     `json:"time_zone,omitempty" example:"Europe/Berlin"`
+	DueAtFormatted *time.Time `json:"-"`
+}
+
+func (d *dueAtInput) validate() error {
+	if d.TimeZone != "" {
+		if _, err := time.LoadLocation(d.TimeZone); err != nil {
+			return entity.ErrInvalidTimeZone
+		}
+	}
+
+	if d.DueAt == "" {
+		return nil
+	}
+
+	if _, err := parseDueAt(d.DueAt, time.UTC); err != nil {
+		return entity.ErrInvalidDueAt
+	}
+
+	return nil
+}
+
+// resolve parses DueAt using defaultLoc when neither offset nor TimeZone are given
+// and returns the calendar date of the due date-time in that zone.
+func (d *dueAtInput) resolve(defaultLoc *time.Location) (time.Time, error) {
+	loc := defaultLoc
+	if d.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(d.TimeZone); err != nil {
+			return time.Time{}, entity.ErrInvalidTimeZone
+		}
+	}
+
+	dueAt, err := parseDueAt(d.DueAt, loc)
+	if err != nil {
+		return time.Time{}, entity.ErrInvalidDueAt
+	}
+	d.DueAtFormatted = &dueAt
+
+	local := dueAt.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+func parseDueAt(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation(localDateTimeFormat, value, loc); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation(localDateTimeShort, value, loc)
+}
+
+func parseTimeFilter(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, entity.ErrInvalidDueAt
+	}
+
+	return t, nil
+}
+
 /* ------------- NOTES ------------- */
 
 type createNoteInput struct {
-	Title         string    `json:"title" binding:"required,min=1,max=80"`
-	Description   string    `json:"description,omitempty"`
-	Date          string    `json:"date,omitempty" binding:"min=9,max=10"`
-	DateFormatted time.Time `json:"-"`
-	Status        string    `json:"status,omitempty"`
+	Title         string             `json:"title" binding:"required,min=1,max=80"`
+	Description   string             `json:"description,omitempty"`
+	Date          string             `json:"date,omitempty" binding:"min=9,max=10"`
+	DateFormatted time.Time          `json:"-"`
+	Status        string             `json:"status,omitempty"`
+	Priority      string             `json:"priority,omitempty" enums:"low,medium,high,urgent"`
+	Recurrence    *entity.Recurrence `json:"recurrence,omitempty"`
+	ListId        *int               `json:"list_id,omitempty"`
+	Tags          []string           `json:"tags,omitempty"`
+	dueAtInput
 }
 
 func (n *createNoteInput) Set(r *http.Request) error {
@@ -31,6 +124,10 @@ func (n *createNoteInput) Set(r *http.Request) error {
 		return entity.ErrInvalidInput
 	}
 
+	if err = n.dueAtInput.validate(); err != nil {
+		return err
+	}
+
 	if n.Date != "" {
 		n.DateFormatted, err = time.Parse(dateFormat, n.Date)
 		if err != nil {
@@ -51,14 +148,62 @@ func (n *createNoteInput) Set(r *http.Request) error {
 		n.Status = entity.StatusNotDone
 	}
 
+	if n.Priority == "" {
+		n.Priority = entity.PriorityMedium
+	} else if !entity.IsValidPriority(n.Priority) {
+		return entity.ErrInvalidPriority
+	}
+
+	if n.Recurrence != nil {
+		if err = n.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if n.ListId != nil {
+		if *n.ListId < 0 {
+			return entity.ErrInvalidList
+		}
+		if *n.ListId == 0 {
+			n.ListId = nil
+		}
+	}
+
+	n.Tags, err = normalizeTags(n.Tags)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResolveDueAt resolves due date-time in loc, when date is omitted it's taken from due_at.
+func (n *createNoteInput) ResolveDueAt(loc *time.Location) error {
+	date, err := n.dueAtInput.resolve(loc)
+	if err != nil {
+		return err
+	}
+
+	if n.Date == "" {
+		n.DateFormatted = date
+	}
+
 	return nil
 }
 
 type updateNoteInput struct {
-	ID          int    `json:"-"`
-	Title       string `json:"title,omitempty"`
-	Description string `json:"description,omitempty"`
-	Status      string `json:"status,omitempty"`
+	ID              int                `json:"-"`
+	Title           string             `json:"title,omitempty"`
+	Description     string             `json:"description,omitempty"`
+	Date            string             `json:"date,omitempty"`
+	DateFormatted   time.Time          `json:"-"`
+	Status          string             `json:"status,omitempty"`
+	Priority        string             `json:"priority,omitempty" enums:"low,medium,high,urgent"`
+	Recurrence      *entity.Recurrence `json:"recurrence,omitempty"`
+	ClearRecurrence bool               `json:"clear_recurrence,omitempty"`
+	ListId          *int               `json:"list_id,omitempty"`
+	Tags            []string           `json:"tags,omitempty"`
+	dueAtInput
 }
 
 func (n *updateNoteInput) Set(r *http.Request) error {
@@ -72,25 +217,164 @@ func (n *updateNoteInput) Set(r *http.Request) error {
 		return entity.ErrInvalidInput
 	}
 
-	if n.Title == "" && n.Description == "" && n.Status == "" {
+	if n.Title == "" && n.Description == "" && n.Date == "" && n.DueAt == "" && n.Status == "" && n.Priority == "" &&
+		n.Recurrence == nil && !n.ClearRecurrence && n.ListId == nil && n.Tags == nil {
+		return entity.ErrInvalidInput
+	}
+
+	if n.ListId != nil && *n.ListId < 0 {
+		return entity.ErrInvalidList
+	}
+
+	if n.Recurrence != nil {
+		if n.ClearRecurrence {
+			return entity.ErrInvalidRecurrence
+		}
+
+		if err = n.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err = n.dueAtInput.validate(); err != nil {
+		return err
+	}
+
+	if n.Date != "" {
+		n.DateFormatted, err = time.Parse(dateFormat, n.Date)
+		if err != nil {
+			return entity.ErrInvalidDate
+		}
+	}
+
+	if n.Priority != "" && !entity.IsValidPriority(n.Priority) {
+		return entity.ErrInvalidPriority
+	}
+
+	if n.Tags != nil {
+		n.Tags, err = normalizeTags(n.Tags)
+		if err != nil {
+			return err
+		}
+		if n.Tags == nil {
+			n.Tags = []string{}
+		}
+	}
+
+	return nil
+}
+
+// ResolveDueAt resolves due date-time in loc, when date is omitted it's taken from due_at.
+func (n *updateNoteInput) ResolveDueAt(loc *time.Location) error {
+	date, err := n.dueAtInput.resolve(loc)
+	if err != nil {
+		return err
+	}
+
+	if n.Date == "" {
+		n.DateFormatted = date
+	}
+
+	return nil
+}
+
+func (n *updateNoteInput) Update() entity.NoteUpdate {
+	return entity.NoteUpdate{
+		Title:           n.Title,
+		Description:     n.Description,
+		Status:          n.Status,
+		Priority:        n.Priority,
+		Date:            n.DateFormatted,
+		DueAt:           n.DueAtFormatted,
+		Recurrence:      n.Recurrence,
+		ClearRecurrence: n.ClearRecurrence,
+		ListId:          n.ListId,
+		Tags:            n.Tags,
+	}
+}
+
+const (
+	defaultOccurrencesCount = 5
+	maxOccurrencesCount     = 100
+)
+
+type getOccurrencesRequest struct {
+	ID    int
+	Count int
+}
+
+func (o *getOccurrencesRequest) Set(r *http.Request) error {
+	o.ID, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if o.ID == 0 {
+		return entity.ErrInvalidId
+	}
+
+	o.Count = defaultOccurrencesCount
+	if count := r.URL.Query().Get("count"); count != "" {
+		var err error
+		o.Count, err = strconv.Atoi(count)
+		if err != nil || o.Count <= 0 || o.Count > maxOccurrencesCount {
+			return entity.ErrInvalidInput
+		}
+	}
+
+	return nil
+}
+
+type getDiffRequest struct {
+	NoteId int
+	From   int
+	To     int
+}
+
+func (d *getDiffRequest) Set(r *http.Request) error {
+	d.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if d.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	d.From, _ = strconv.Atoi(r.URL.Query().Get("from"))
+	d.To, _ = strconv.Atoi(r.URL.Query().Get("to"))
+	if d.From <= 0 || d.To <= 0 {
 		return entity.ErrInvalidInput
 	}
+
 	return nil
 }
 
 type getNotesRequest struct {
-	Page          int       `json:"-"`
-	Status        string    `json:"status,omitempty"`
-	Date          string    `json:"date,omitempty"`
-	DateFormatted time.Time `json:"-"`
-	Limit         int       `json:"limit,omitempty"`
+	Page          int                `json:"-"`
+	Cursor        string             `json:"cursor,omitempty"`
+	CursorParsed  *entity.NoteCursor `json:"-"`
+	Query         string             `json:"q,omitempty" example:"buy mil"`
+	Status        string             `json:"status,omitempty"`
+	Date          string             `json:"date,omitempty"`
+	DateFormatted time.Time          `json:"-"`
+	Limit         int                `json:"limit,omitempty"`
+	Tags          []string           `json:"tags,omitempty"`
+	TagsMatch     string             `json:"tags_match,omitempty" enums:"any,all"`
+	DueBefore     string             `json:"due_before,omitempty" example:"2024-01-31T00:00:00Z"`
+	DueAfter      string             `json:"due_after,omitempty" example:"2024-01-01T00:00:00Z"`
+	Overdue       bool               `json:"overdue,omitempty"`
+	ListId        *int               `json:"list_id,omitempty"`
+	Assignee      string             `json:"assignee,omitempty" example:"me"`
+	AssigneeId    *int               `json:"-"`
+	AssignedToMe  bool               `json:"-"`
+	DueBeforeTime time.Time          `json:"-"`
+	DueAfterTime  time.Time          `json:"-"`
+	Sort          string             `json:"sort,omitempty" example:"priority desc, date asc, title asc"`
+	SortFormatted []entity.NoteSort  `json:"-"`
 }
 
+// Set reads the page number from the path, when the path has no page
+// notes are paginated by the cursor passed in the body.
 func (n *getNotesRequest) Set(r *http.Request) error {
 	var err error
-	n.Page, _ = strconv.Atoi(mux.Vars(r)["page"])
-	if n.Page == 0 {
-		return entity.ErrInvalidPage
+	if page, ok := mux.Vars(r)["page"]; ok {
+		n.Page, _ = strconv.Atoi(page)
+		if n.Page == 0 {
+			return entity.ErrInvalidPage
+		}
 	}
 
 	err = json.NewDecoder(r.Body).Decode(n)
@@ -98,6 +382,18 @@ func (n *getNotesRequest) Set(r *http.Request) error {
 		return entity.ErrInvalidInput
 	}
 
+	if n.Cursor != "" {
+		if n.Page != 0 {
+			return entity.ErrInvalidCursor
+		}
+
+		cursor, err := entity.ParseNoteCursor(n.Cursor)
+		if err != nil {
+			return err
+		}
+		n.CursorParsed = &cursor
+	}
+
 	if n.Limit < 0 {
 		return entity.ErrInvalidInput
 	}
@@ -117,38 +413,903 @@ func (n *getNotesRequest) Set(r *http.Request) error {
 		return entity.ErrInvalidStatus
 	}
 
+	n.Query = strings.TrimSpace(n.Query)
+	if utf8.RuneCountInString(n.Query) > maxQueryLength {
+		return entity.ErrInvalidQuery
+	}
+
+	n.Tags, err = normalizeTags(n.Tags)
+	if err != nil {
+		return err
+	}
+
+	switch n.TagsMatch {
+	case "":
+		n.TagsMatch = entity.TagsMatchAny
+	case entity.TagsMatchAny, entity.TagsMatchAll:
+	default:
+		return entity.ErrInvalidTagMatch
+	}
+
+	if n.ListId != nil && *n.ListId < 0 {
+		return entity.ErrInvalidList
+	}
+
+	if err = n.parseAssignee(); err != nil {
+		return err
+	}
+
+	n.DueBeforeTime, err = parseTimeFilter(n.DueBefore)
+	if err != nil {
+		return err
+	}
+
+	n.DueAfterTime, err = parseTimeFilter(n.DueAfter)
+	if err != nil {
+		return err
+	}
+
+	n.SortFormatted, err = parseNoteSort(n.Sort)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-/* ------------- USERS ------------- */
+// parseAssignee reads the assignee filter: me, unassigned or id of the user.
+func (n *getNotesRequest) parseAssignee() error {
+	switch n.Assignee {
+	case "":
+	case "me":
+		n.AssignedToMe = true
+	case "unassigned":
+		n.AssigneeId = new(int)
+	default:
+		id, err := strconv.Atoi(n.Assignee)
+		if err != nil || id <= 0 {
+			return entity.ErrInvalidAssignee
+		}
+		n.AssigneeId = &id
+	}
 
-type signUpInput struct {
-	Login    string `json:"login" binding:"required,min=2,max=64"`
-	Email    string `json:"email" binding:"required,min=6,max=64"`
-	Password string `json:"password" binding:"required,min=8,max=64"`
+	return nil
 }
 
-func (u *signUpInput) Set(r *http.Request) error {
-	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+// Filter returns the filter of the request, userId resolves the assignee filter "me".
+func (n *getNotesRequest) Filter(userId int) entity.NoteFilter {
+	assigneeId := n.AssigneeId
+	if n.AssignedToMe {
+		assigneeId = &userId
+	}
+
+	return entity.NoteFilter{
+		Query:      n.Query,
+		Status:     n.Status,
+		Date:       n.DateFormatted,
+		Tags:       n.Tags,
+		TagsMatch:  n.TagsMatch,
+		DueBefore:  n.DueBeforeTime,
+		DueAfter:   n.DueAfterTime,
+		Overdue:    n.Overdue,
+		ListId:     n.ListId,
+		AssigneeId: assigneeId,
+		Sort:       n.SortFormatted,
+		Cursor:     n.CursorParsed,
+	}
+}
+
+func (n *getNotesRequest) Offset() int {
+	if n.Page == 0 {
+		return 0
+	}
+	return (n.Page - 1) * n.Limit
+}
+
+// parseNoteSort parses sort spec like "priority desc, date asc, title" checking fields against the whitelist.
+func parseNoteSort(sort string) ([]entity.NoteSort, error) {
+	if strings.TrimSpace(sort) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(sort, ",")
+	result := make([]entity.NoteSort, 0, len(parts))
+	seen := make(map[string]struct{}, len(parts))
+	for _, part := range parts {
+		fields := strings.Fields(strings.ToLower(part))
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, entity.ErrInvalidSort
+		}
+
+		if _, ok := entity.NoteSortFields[fields[0]]; !ok {
+			return nil, entity.ErrInvalidSort
+		}
+
+		if _, ok := seen[fields[0]]; ok {
+			return nil, entity.ErrInvalidSort
+		}
+		seen[fields[0]] = struct{}{}
+
+		direction := entity.SortAsc
+		if len(fields) == 2 {
+			if fields[1] != entity.SortAsc && fields[1] != entity.SortDesc {
+				return nil, entity.ErrInvalidSort
+			}
+			direction = fields[1]
+		}
+
+		result = append(result, entity.NoteSort{Field: fields[0], Direction: direction})
+	}
+
+	return result, nil
+}
+
+/* ------------- TAGS ------------- */
+
+type updateTagInput struct {
+	ID   int    `json:"-"`
+	Name string `json:"name" binding:"required,min=1,max=32"`
+}
+
+func (t *updateTagInput) Set(r *http.Request) error {
+	t.ID, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if t.ID == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(t); err != nil {
 		return entity.ErrInvalidInput
 	}
 
-	if _, err := mail.ParseAddress(u.Email); err != nil {
-		return entity.ErrInvalidEmail
+	name, err := normalizeTag(t.Name)
+	if err != nil {
+		return err
 	}
+	t.Name = name
 
 	return nil
 }
 
-type signInInput struct {
-	Login    string `json:"login" binding:"required,min=2,max=64"`
-	Password string `json:"password" binding:"required,min=8,max=64"`
+func normalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || utf8.RuneCountInString(name) > maxTagLength {
+		return "", entity.ErrInvalidTag
+	}
+
+	return name, nil
 }
 
-func (u *signInInput) Set(r *http.Request) error {
-	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+// normalizeTags lowercases, trims and deduplicates tags keeping their order.
+func normalizeTags(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name, err := normalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		result = append(result, name)
+	}
+
+	return result, nil
+}
+
+/* ------------- LISTS ------------- */
+
+type listInput struct {
+	ID   int    `json:"-"`
+	Name string `json:"name" binding:"required,min=1,max=64"`
+}
+
+func (l *listInput) Set(r *http.Request) error {
+	if id, ok := mux.Vars(r)["id"]; ok {
+		l.ID, _ = strconv.Atoi(id)
+		if l.ID == 0 {
+			return entity.ErrInvalidId
+		}
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(l); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	l.Name = strings.TrimSpace(l.Name)
+	if l.Name == "" || utf8.RuneCountInString(l.Name) > maxListNameLength {
+		return entity.ErrInvalidList
+	}
+
+	return nil
+}
+
+type deleteListRequest struct {
+	ID      int
+	Cascade bool
+}
+
+func (l *deleteListRequest) Set(r *http.Request) error {
+	l.ID, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if l.ID == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if cascade := r.URL.Query().Get("cascade"); cascade != "" {
+		var err error
+		l.Cascade, err = strconv.ParseBool(cascade)
+		if err != nil {
+			return entity.ErrInvalidInput
+		}
+	}
+
+	return nil
+}
+
+type moveNotesInput struct {
+	NoteIds []int `json:"note_ids" example:"1,2"`
+	ListId  int   `json:"list_id"`
+}
+
+func (m *moveNotesInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if len(m.NoteIds) == 0 {
+		return entity.ErrInvalidInput
+	}
+
+	if m.ListId < 0 {
+		return entity.ErrInvalidList
+	}
+
+	return nil
+}
+
+/* ------------- ITEMS ------------- */
+
+type createItemInput struct {
+	NoteId  int    `json:"-"`
+	Title   string `json:"title" binding:"required,min=1,max=255"`
+	Checked bool   `json:"checked,omitempty"`
+}
+
+func (i *createItemInput) Set(r *http.Request) error {
+	i.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if i.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
 		return entity.ErrInvalidInput
 	}
 
+	i.Title = strings.TrimSpace(i.Title)
+	if i.Title == "" || utf8.RuneCountInString(i.Title) > maxItemTitleLength {
+		return entity.ErrInvalidItem
+	}
+
 	return nil
 }
+
+type updateItemInput struct {
+	ID      int    `json:"-"`
+	NoteId  int    `json:"-"`
+	Title   string `json:"title,omitempty"`
+	Checked *bool  `json:"checked,omitempty"`
+}
+
+func (i *updateItemInput) Set(r *http.Request) error {
+	i.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	i.ID, _ = strconv.Atoi(mux.Vars(r)["item_id"])
+	if i.NoteId == 0 || i.ID == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	i.Title = strings.TrimSpace(i.Title)
+	if i.Title == "" && i.Checked == nil {
+		return entity.ErrInvalidInput
+	}
+
+	if utf8.RuneCountInString(i.Title) > maxItemTitleLength {
+		return entity.ErrInvalidItem
+	}
+
+	return nil
+}
+
+func (i *updateItemInput) Update() entity.ItemUpdate {
+	return entity.ItemUpdate{
+		Title:   i.Title,
+		Checked: i.Checked,
+	}
+}
+
+type reorderItemsInput struct {
+	NoteId int   `json:"-"`
+	IDs    []int `json:"ids" example:"3,1,2"`
+}
+
+func (i *reorderItemsInput) Set(r *http.Request) error {
+	i.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if i.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if len(i.IDs) == 0 {
+		return entity.ErrInvalidItemsOrder
+	}
+
+	return nil
+}
+
+/* ------------- SHARES ------------- */
+
+type shareNoteInput struct {
+	NoteId     int    `json:"-"`
+	Login      string `json:"login" binding:"required" example:"colleague"`
+	Permission string `json:"permission" binding:"required" example:"viewer" enums:"viewer,editor"`
+}
+
+func (i *shareNoteInput) Set(r *http.Request) error {
+	i.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if i.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	i.Login = strings.TrimSpace(i.Login)
+	if i.Login == "" {
+		return entity.ErrInvalidInput
+	}
+
+	return nil
+}
+
+type assignNoteInput struct {
+	NoteId     int `json:"-"`
+	AssigneeId int `json:"assignee_id" example:"2"`
+}
+
+// Set reads the assignee, zero or null unassigns the note.
+func (i *assignNoteInput) Set(r *http.Request) error {
+	i.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if i.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if i.AssigneeId < 0 {
+		return entity.ErrInvalidAssignee
+	}
+
+	return nil
+}
+
+/* ------------- COMMENTS ------------- */
+
+const (
+	maxCommentLength     = 4096
+	defaultCommentsLimit = 20
+	maxCommentsLimit     = 100
+)
+
+type commentInput struct {
+	NoteId    int    `json:"-"`
+	CommentId int    `json:"-"`
+	Body      string `json:"body" binding:"required,min=1,max=4096" example:"@colleague take a look"`
+}
+
+// Set reads ids of the note and of the comment, the latter is in the path only when the comment is edited.
+func (i *commentInput) Set(r *http.Request) error {
+	i.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if i.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if commentId, ok := mux.Vars(r)["comment_id"]; ok {
+		i.CommentId, _ = strconv.Atoi(commentId)
+		if i.CommentId == 0 {
+			return entity.ErrInvalidId
+		}
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	i.Body = strings.TrimSpace(i.Body)
+	if i.Body == "" || utf8.RuneCountInString(i.Body) > maxCommentLength {
+		return entity.ErrInvalidComment
+	}
+
+	return nil
+}
+
+type getCommentsRequest struct {
+	NoteId int
+	Cursor *entity.CommentCursor
+	Limit  int
+}
+
+func (c *getCommentsRequest) Set(r *http.Request) error {
+	c.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if c.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		parsed, err := entity.ParseCommentCursor(cursor)
+		if err != nil {
+			return err
+		}
+		c.Cursor = &parsed
+	}
+
+	c.Limit = defaultCommentsLimit
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		var err error
+		c.Limit, err = strconv.Atoi(limit)
+		if err != nil || c.Limit <= 0 || c.Limit > maxCommentsLimit {
+			return entity.ErrInvalidInput
+		}
+	}
+
+	return nil
+}
+
+/* ------------- ATTACHMENTS ------------- */
+
+const (
+	// maxMultipartMemory is the part of an upload kept in memory, the rest is spooled to a temporary file
+	maxMultipartMemory = 1 << 20
+	// multipartOverhead leaves room for boundaries and headers of the form on top of the size limit of the file
+	multipartOverhead = 64 << 10
+	maxAttachmentName = 255
+)
+
+type attachmentInput struct {
+	NoteId       int
+	AttachmentId int
+}
+
+func (a *attachmentInput) Set(r *http.Request) error {
+	a.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if a.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if attachmentId, ok := mux.Vars(r)["attachment_id"]; ok {
+		a.AttachmentId, _ = strconv.Atoi(attachmentId)
+		if a.AttachmentId == 0 {
+			return entity.ErrInvalidId
+		}
+	}
+
+	return nil
+}
+
+type uploadAttachmentInput struct {
+	NoteId int
+	Name   string
+	Size   int64
+	File   multipart.File
+}
+
+// Set parses the multipart form reading the file from the file field, the body must be limited
+// by http.MaxBytesReader beforehand. The caller closes File and removes the form, even when Set fails.
+func (u *uploadAttachmentInput) Set(r *http.Request) error {
+	u.NoteId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if u.NoteId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return entity.ErrAttachmentTooLarge
+		}
+		return entity.ErrInvalidAttachment
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return entity.ErrInvalidAttachment
+	}
+
+	u.File, u.Size = file, header.Size
+
+	u.Name = strings.TrimSpace(header.Filename)
+	if u.Name == "" {
+		u.Name = "attachment"
+	}
+	if name := []rune(u.Name); len(name) > maxAttachmentName {
+		u.Name = string(name[:maxAttachmentName])
+	}
+
+	return nil
+}
+
+// byteRange is a part of a file requested with the Range header.
+type byteRange struct {
+	Start  int64
+	Length int64
+}
+
+// ContentRange is the Content-Range header of the partial response.
+func (b byteRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", b.Start, b.Start+b.Length-1, size)
+}
+
+// parseByteRange parses the Range header of a download of the file of size bytes. Only a single range is
+// supported, the whole file is served for other units and multiple ranges as the header is optional.
+func parseByteRange(header string, size int64) (*byteRange, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, entity.ErrInvalidRange
+	}
+
+	if first == "" {
+		// the suffix range requests the last bytes of the file
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, entity.ErrInvalidRange
+		}
+		n = min(n, size)
+		return &byteRange{Start: size - n, Length: n}, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return nil, entity.ErrInvalidRange
+	}
+
+	end := size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return nil, entity.ErrInvalidRange
+		}
+		end = min(end, size-1)
+	}
+
+	return &byteRange{Start: start, Length: end - start + 1}, nil
+}
+
+/* ------------- NOTIFICATIONS ------------- */
+
+const (
+	defaultNotificationsLimit = 20
+	maxNotificationsLimit     = 100
+)
+
+type getNotificationsRequest struct {
+	Unread bool
+	Limit  int
+}
+
+func (n *getNotificationsRequest) Set(r *http.Request) error {
+	if unread := r.URL.Query().Get("unread"); unread != "" {
+		var err error
+		n.Unread, err = strconv.ParseBool(unread)
+		if err != nil {
+			return entity.ErrInvalidInput
+		}
+	}
+
+	n.Limit = defaultNotificationsLimit
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		var err error
+		n.Limit, err = strconv.Atoi(limit)
+		if err != nil || n.Limit <= 0 || n.Limit > maxNotificationsLimit {
+			return entity.ErrInvalidInput
+		}
+	}
+
+	return nil
+}
+
+/* ------------- WORKSPACES ------------- */
+
+const maxWorkspaceNameLength = 64
+
+type workspaceInput struct {
+	Name string `json:"name" binding:"required,min=1,max=64" example:"Team"`
+}
+
+func (i *workspaceInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	i.Name = strings.TrimSpace(i.Name)
+	if length := utf8.RuneCountInString(i.Name); length == 0 || length > maxWorkspaceNameLength {
+		return entity.ErrInvalidWorkspaceName
+	}
+
+	return nil
+}
+
+type workspaceMemberInput struct {
+	WorkspaceId int    `json:"-"`
+	UserId      int    `json:"-"`
+	Role        string `json:"role" binding:"required" example:"member" enums:"admin,member,guest"`
+}
+
+func (i *workspaceMemberInput) Set(r *http.Request) error {
+	i.WorkspaceId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	i.UserId, _ = strconv.Atoi(mux.Vars(r)["user_id"])
+	if i.WorkspaceId == 0 || i.UserId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	return nil
+}
+
+type inviteInput struct {
+	WorkspaceId int    `json:"-"`
+	Email       string `json:"email" binding:"required,min=6,max=64"`
+	Role        string `json:"role" binding:"required" example:"member" enums:"admin,member,guest"`
+}
+
+func (i *inviteInput) Set(r *http.Request) error {
+	i.WorkspaceId, _ = strconv.Atoi(mux.Vars(r)["id"])
+	if i.WorkspaceId == 0 {
+		return entity.ErrInvalidId
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if _, err := mail.ParseAddress(i.Email); err != nil {
+		return entity.ErrInvalidEmail
+	}
+
+	return nil
+}
+
+type acceptInvitationInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func (i *acceptInvitationInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(i); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if i.Token == "" {
+		return entity.ErrInvitationNotExists
+	}
+
+	return nil
+}
+
+/* ------------- USERS ------------- */
+
+type signUpInput struct {
+	Login    string `json:"login" binding:"required,min=2,max=64"`
+	Email    string `json:"email" binding:"required,min=6,max=64"`
+	Password string `json:"password" binding:"required,min=8,max=64"`
+	TimeZone string `json:"time_zone,omitempty" example:"Europe/Berlin"`
+}
+
+func (u *signUpInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if _, err := mail.ParseAddress(u.Email); err != nil {
+		return entity.ErrInvalidEmail
+	}
+
+	if u.TimeZone != "" {
+		if _, err := time.LoadLocation(u.TimeZone); err != nil {
+			return entity.ErrInvalidTimeZone
+		}
+	}
+
+	return nil
+}
+
+type updateUserInput struct {
+	TimeZone string `json:"time_zone" example:"Europe/Berlin"`
+}
+
+func (u *updateUserInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if u.TimeZone == "" {
+		return entity.ErrInvalidInput
+	}
+
+	if _, err := time.LoadLocation(u.TimeZone); err != nil {
+		return entity.ErrInvalidTimeZone
+	}
+
+	return nil
+}
+
+type signInInput struct {
+	Login    string `json:"login" binding:"required,min=2,max=64"`
+	Password string `json:"password" binding:"required,min=8,max=64"`
+	Device   string `json:"device,omitempty" example:"Pixel 8"`
+}
+
+func (u *signInInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	return nil
+}
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 64
+)
+
+type forgotPasswordInput struct {
+	Email string `json:"email" binding:"required,min=6,max=64"`
+}
+
+func (u *forgotPasswordInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if _, err := mail.ParseAddress(u.Email); err != nil {
+		return entity.ErrInvalidEmail
+	}
+
+	return nil
+}
+
+type resendVerificationInput struct {
+	Email string `json:"email" binding:"required,min=6,max=64"`
+}
+
+func (u *resendVerificationInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if _, err := mail.ParseAddress(u.Email); err != nil {
+		return entity.ErrInvalidEmail
+	}
+
+	return nil
+}
+
+type resetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8,max=64"`
+}
+
+func (u *resetPasswordInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if u.Token == "" {
+		return entity.ErrInvalidResetToken
+	}
+
+	if length := utf8.RuneCountInString(u.Password); length < minPasswordLength || length > maxPasswordLength {
+		return entity.ErrInvalidPassword
+	}
+
+	return nil
+}
+
+type twoFactorCodeInput struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+func (u *twoFactorCodeInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if strings.TrimSpace(u.Code) == "" {
+		return entity.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+type signInTwoFactorInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// Code is a TOTP code or a recovery code
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+func (u *signInTwoFactorInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	if u.ChallengeToken == "" {
+		return entity.ErrInvalidChallenge
+	}
+
+	if strings.TrimSpace(u.Code) == "" {
+		return entity.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+const maxTokenNameLength = 64
+
+type createTokenInput struct {
+	Name   string   `json:"name" binding:"required,min=1,max=64" example:"ci"`
+	Scopes []string `json:"scopes" binding:"required" example:"notes:read,notes:write"`
+	// ExpiresAt is optional, the token never expires without it
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2025-01-01T00:00:00Z"`
+}
+
+func (u *createTokenInput) Set(r *http.Request) error {
+	if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+		return entity.ErrInvalidInput
+	}
+
+	u.Name = strings.TrimSpace(u.Name)
+	if length := utf8.RuneCountInString(u.Name); length == 0 || length > maxTokenNameLength {
+		return entity.ErrInvalidTokenName
+	}
+
+	return nil
+}
+
+// newClient describes the device of the request, device name is given by the client on sign in.
+func newClient(r *http.Request, device string) entity.Client {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return entity.Client{
+		Device:    truncate(strings.TrimSpace(device), maxDeviceLength),
+		UserAgent: truncate(r.UserAgent(), maxUserAgentLength),
+		IP:        ip,
+	}
+}
+
+func truncate(s string, length int) string {
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	return string([]rune(s)[:length])
+}
@@ -4,16 +4,54 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/pintoter/todo-list/internal/entity"
 )
 
 type getNoteResponse struct {
-	Note entity.Note `json:"note"`
+	Note     entity.Note   `json:"note"`
+	Items    []entity.Item `json:"items,omitempty"`
+	Progress *int          `json:"progress,omitempty"`
 }
 
 type getNotesResponse struct {
-	Notes []entity.Note `json:"notes"`
+	Notes      []entity.Note `json:"notes"`
+	Total      int           `json:"total"`
+	HasMore    bool          `json:"has_more"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	PrevCursor string        `json:"prev_cursor,omitempty"`
+}
+
+type getOccurrencesResponse struct {
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+type getHistoryResponse struct {
+	Revisions []entity.Revision `json:"revisions"`
+}
+
+type getDiffResponse struct {
+	From    int                      `json:"from"`
+	To      int                      `json:"to"`
+	Changes map[string]entity.Change `json:"changes"`
+}
+
+type getListResponse struct {
+	List entity.List `json:"list"`
+}
+
+type getListsResponse struct {
+	Lists []entity.List `json:"lists"`
+}
+
+type getItemsResponse struct {
+	Items    []entity.Item `json:"items"`
+	Progress int           `json:"progress"`
+}
+
+type getTagsResponse struct {
+	Tags []entity.Tag `json:"tags"`
 }
 
 type successCUDResponse struct {
@@ -24,11 +62,71 @@ type errorResponse struct {
 	Err string `json:"error"`
 }
 
+type getSessionsResponse struct {
+	Sessions []entity.Session `json:"sessions"`
+}
+
 type tokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
+type challengeResponse struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type enrollTwoFactorResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri" example:"otpauth://totp/todo-list:user?secret=..."`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type createTokenResponse struct {
+	Token               string                     `json:"token"`
+	PersonalAccessToken entity.PersonalAccessToken `json:"personal_access_token"`
+}
+
+type getTokensResponse struct {
+	Tokens []entity.PersonalAccessToken `json:"tokens"`
+}
+
+type getSharesResponse struct {
+	Shares []entity.NoteShare `json:"shares"`
+}
+
+type getSharedNotesResponse struct {
+	Notes []entity.SharedNote `json:"notes"`
+}
+
+type getCommentsResponse struct {
+	Comments   []entity.Comment `json:"comments"`
+	HasMore    bool             `json:"has_more"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type getAttachmentsResponse struct {
+	Attachments []entity.Attachment `json:"attachments"`
+}
+
+type getNotificationsResponse struct {
+	Notifications []entity.Notification `json:"notifications"`
+}
+
+type getWorkspacesResponse struct {
+	Workspaces []entity.Workspace `json:"workspaces"`
+}
+
+type getMembersResponse struct {
+	Members []entity.WorkspaceMember `json:"members"`
+}
+
+type getInvitationsResponse struct {
+	Invitations []entity.WorkspaceInvitation `json:"invitations"`
+}
+
 func renderJSON(w http.ResponseWriter, r *http.Request, code int, data any) {
 	log.Printf("[Response] [%s] %s - Status code: [%d]", r.Method, r.URL.Path, code)
 
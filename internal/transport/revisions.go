@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Get note history
+// @Description Get revisions of the note with changed fields, who and when changed them, the latest first
+// @Tags history
+// @Produce json
+// @Param id path int true "note id"
+// @Success 200 {object} getHistoryResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/history [get]
+func (h *Handler) getHistory(w http.ResponseWriter, r *http.Request) {
+	noteId, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if noteId == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	revisions, err := h.service.GetNoteHistory(r.Context(), noteId, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getHistoryResponse{Revisions: revisions})
+}
+
+// @Summary Revert note
+// @Description Revert note to the state after the revision
+// @Tags history
+// @Produce json
+// @Param id path int true "note id"
+// @Param revision_id path int true "revision id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/history/{revision_id}/revert [post]
+func (h *Handler) revertNote(w http.ResponseWriter, r *http.Request) {
+	noteId, _ := strconv.Atoi(mux.Vars(r)["id"])
+	revisionId, _ := strconv.Atoi(mux.Vars(r)["revision_id"])
+	if noteId == 0 || revisionId == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.RevertNote(r.Context(), noteId, revisionId, userId); err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrRevisionNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteExists) {
+			renderJSON(w, r, http.StatusConflict, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) || errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "note reverted successfully"})
+}
+
+// @Summary Diff note revisions
+// @Description Get field-level difference between states of the note after two revisions
+// @Tags history
+// @Produce json
+// @Param id path int true "note id"
+// @Param from query int true "revision id to compare from"
+// @Param to query int true "revision id to compare to"
+// @Success 200 {object} getDiffResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/diff [get]
+func (h *Handler) getDiff(w http.ResponseWriter, r *http.Request) {
+	var input getDiffRequest
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	changes, err := h.service.DiffRevisions(r.Context(), input.NoteId, input.From, input.To, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrRevisionNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getDiffResponse{From: input.From, To: input.To, Changes: changes})
+}
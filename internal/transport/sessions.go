@@ -0,0 +1,115 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+	"github.com/pintoter/todo-list/pkg/auth"
+)
+
+// @Summary Get sessions
+// @Description Get active sessions of the user, the session of the request is marked as current
+// @Tags auth
+// @Produce json
+// @Success 200 {object} getSessionsResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/sessions [get]
+func (h *Handler) getSessions(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	sessionId, _ := r.Context().Value("session_id").(int)
+
+	sessions, err := h.service.GetSessions(r.Context(), userId, sessionId)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getSessionsResponse{Sessions: sessions})
+}
+
+// @Summary Delete session
+// @Description Log out the device of the session by id, its access tokens are revoked right away
+// @Tags auth
+// @Produce json
+// @Param id path int true "id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/sessions/{id} [delete]
+func (h *Handler) deleteSession(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteSession(r.Context(), id, userId); err != nil {
+		if errors.Is(err, entity.ErrSessionDoesntExist) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "session deleted successfully"})
+}
+
+// @Summary Log out everywhere
+// @Description Delete all sessions of the user including the current one
+// @Tags auth
+// @Produce json
+// @Success 202 {object} successCUDResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/sessions [delete]
+func (h *Handler) deleteSessions(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteSessions(r.Context(), userId); err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "sessions deleted successfully"})
+}
+
+// @Summary Log out
+// @Description Delete the current session and revoke the access token of the request
+// @Tags auth
+// @Produce json
+// @Success 202 {object} successCUDResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/logout [post]
+func (h *Handler) logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(auth.Claims)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad claims"})
+		return
+	}
+
+	if err := h.service.Logout(r.Context(), claims); err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "logged out successfully"})
+}
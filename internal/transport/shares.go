@@ -0,0 +1,156 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Share note
+// @Description Share the note with the user by login as a viewer or an editor, sharing again replaces the permission
+// @Tags shares
+// @Accept json
+// @Produce json
+// @Param id path int true "note id"
+// @Param input body shareNoteInput true "input"
+// @Success 201 {object} entity.NoteShare
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/shares [post]
+func (h *Handler) shareNote(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input shareNoteInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	share, err := h.service.ShareNote(r.Context(), input.NoteId, userId, input.Login, input.Permission)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidPermission) || errors.Is(err, entity.ErrShareWithOwner) ||
+			errors.Is(err, entity.ErrWorkspaceSharing) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrUserNotExist) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, share)
+}
+
+// @Summary Get note shares
+// @Description Get users the note is shared with, only the owner can see them
+// @Tags shares
+// @Produce json
+// @Param id path int true "note id"
+// @Success 200 {object} getSharesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/shares [get]
+func (h *Handler) getNoteShares(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	shares, err := h.service.GetNoteShares(r.Context(), id, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getSharesResponse{Shares: shares})
+}
+
+// @Summary Unshare note
+// @Description Revoke access of the user to the note, the owner can revoke anyone while collaborators can leave the note
+// @Tags shares
+// @Produce json
+// @Param id path int true "note id"
+// @Param user_id path int true "collaborator id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/note/{id}/shares/{user_id} [delete]
+func (h *Handler) unshareNote(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	collaboratorId, _ := strconv.Atoi(mux.Vars(r)["user_id"])
+	if id == 0 || collaboratorId == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.UnshareNote(r.Context(), id, userId, collaboratorId); err != nil {
+		if errors.Is(err, entity.ErrNoteNotExists) || errors.Is(err, entity.ErrShareNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrNoteForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "note unshared successfully"})
+}
+
+// @Summary Get shared notes
+// @Description Get notes other users shared with the user along with the permission granted
+// @Tags shares
+// @Produce json
+// @Success 200 {object} getSharedNotesResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/notes/shared [get]
+func (h *Handler) getSharedNotes(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	notes, err := h.service.GetSharedNotes(r.Context(), userId)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getSharedNotesResponse{Notes: notes})
+}
@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Get all tags
+// @Description Get all user's tags with notes count
+// @Tags tags
+// @Produce json
+// @Success 200 {object} getTagsResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tags [get]
+func (h *Handler) getTags(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	tags, err := h.service.GetTags(r.Context(), userId)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getTagsResponse{Tags: tags})
+}
+
+// @Summary Rename tag
+// @Description Rename tag by id
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "id"
+// @Param input body updateTagInput true "new tag name"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tag/{id} [patch]
+func (h *Handler) updateTag(w http.ResponseWriter, r *http.Request) {
+	var input updateTagInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	err := h.service.UpdateTag(r.Context(), input.ID, input.Name, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrTagNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrTagExists) {
+			renderJSON(w, r, http.StatusConflict, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "tag updated successfully"})
+}
+
+// @Summary Delete tag
+// @Description Delete tag by id and detach it from all notes
+// @Tags tags
+// @Produce json
+// @Param id path int true "id"
+// @Success 200 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/tag/{id} [delete]
+func (h *Handler) deleteTag(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteTag(r.Context(), id, userId); err != nil {
+		if errors.Is(err, entity.ErrTagNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, successCUDResponse{Message: "tag deleted successfully"})
+}
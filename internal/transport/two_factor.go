@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Enroll two-factor authentication
+// @Description Generate a TOTP secret, the otpauth:// URI is meant to be shown as a QR code. 2FA is enabled once confirmed with a code
+// @Tags 2fa
+// @Produce json
+// @Success 200 {object} enrollTwoFactorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/2fa/enroll [post]
+func (h *Handler) enrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	secret, uri, err := h.service.EnrollTwoFactor(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrTwoFactorEnabled) {
+			renderJSON(w, r, http.StatusConflict, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, enrollTwoFactorResponse{Secret: secret, URI: uri})
+}
+
+// @Summary Confirm two-factor authentication
+// @Description Enable 2FA with the first code of the enrolled secret, recovery codes are returned only once
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param input body twoFactorCodeInput true "input"
+// @Success 200 {object} recoveryCodesResponse
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/2fa/confirm [post]
+func (h *Handler) confirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input twoFactorCodeInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	codes, err := h.service.ConfirmTwoFactor(r.Context(), userId, input.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidTwoFactorCode), errors.Is(err, entity.ErrTwoFactorNotEnrolled):
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		case errors.Is(err, entity.ErrTwoFactorEnabled):
+			renderJSON(w, r, http.StatusConflict, errorResponse{err.Error()})
+		default:
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// @Summary Disable two-factor authentication
+// @Description Disable 2FA by a current code, recovery codes are deleted. Too many wrong codes lock disabling for a while
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Param input body twoFactorCodeInput true "input"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/2fa/disable [post]
+func (h *Handler) disableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input twoFactorCodeInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	if err := h.service.DisableTwoFactor(r.Context(), userId, input.Code); err != nil {
+		if errors.Is(err, entity.ErrInvalidTwoFactorCode) || errors.Is(err, entity.ErrTwoFactorNotEnabled) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrTwoFactorLocked) {
+			renderJSON(w, r, http.StatusTooManyRequests, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "two-factor authentication disabled"})
+}
+
+// @Summary Sign In with two-factor code
+// @Description Exchange the challenge token returned by sign in with a TOTP or a recovery code for tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body signInTwoFactorInput true "input"
+// @Success 200 {object} tokenResponse
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/sign-in/2fa [post]
+func (h *Handler) signInTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var input signInTwoFactorInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	tokens, err := h.service.SignInTwoFactor(r.Context(), input.ChallengeToken, input.Code, newClient(r, ""))
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidChallenge) || errors.Is(err, entity.ErrInvalidTwoFactorCode) {
+			renderJSON(w, r, http.StatusUnauthorized, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	r.Header.Set("Set-Cookie", fmt.Sprintf("refresh-token=%s; HttpOnly", tokens.RefreshToken))
+	renderJSON(w, r, http.StatusOK, tokenResponse{AccessToken: tokens.AccessToken})
+}
@@ -25,7 +25,7 @@ func (h *Handler) signUp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := h.service.SignUp(r.Context(), input.Email, input.Login, input.Password)
+	_, err := h.service.SignUp(r.Context(), input.Email, input.Login, input.Password, input.TimeZone)
 	if err != nil {
 		renderJSON(w, r, http.StatusInternalServerError, errorResponse{
 			Err: err.Error(),
@@ -37,13 +37,16 @@ func (h *Handler) signUp(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Sign In
-// @Description Sign In
+// @Description Sign In, when two-factor authentication is enabled a challenge token is returned instead of tokens,
+// @Description it's exchanged with a code at /auth/sign-in/2fa
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param input body signInInput true "input"
 // @Success 200 {object} tokenResponse
+// @Success 202 {object} challengeResponse
 // @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /auth/sign-in [post]
 func (h *Handler) signIn(w http.ResponseWriter, r *http.Request) {
@@ -53,9 +56,18 @@ func (h *Handler) signIn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.service.SignIn(r.Context(), input.Login, input.Password)
+	tokens, err := h.service.SignIn(r.Context(), input.Login, input.Password, newClient(r, input.Device))
 	if err != nil {
-		renderJSON(w, r, http.StatusInternalServerError, errorResponse{Err: err.Error()})
+		if errors.Is(err, entity.ErrEmailNotVerified) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{Err: err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{Err: err.Error()})
+		}
+		return
+	}
+
+	if tokens.Challenge != "" {
+		renderJSON(w, r, http.StatusAccepted, challengeResponse{ChallengeToken: tokens.Challenge})
 		return
 	}
 
@@ -71,6 +83,7 @@ func (h *Handler) signIn(w http.ResponseWriter, r *http.Request) {
 // @Param input body signInInput true "input"
 // @Success 200 {object} tokenResponse
 // @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /auth/refresh [post]
 func (h *Handler) refresh(w http.ResponseWriter, r *http.Request) {
@@ -81,10 +94,12 @@ func (h *Handler) refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.service.RefreshTokens(r.Context(), refreshToken)
+	tokens, err := h.service.RefreshTokens(r.Context(), refreshToken, newClient(r, ""))
 	if err != nil {
 		if errors.Is(err, entity.ErrSessionDoesntExist) {
 			renderJSON(w, r, http.StatusBadRequest, errorResponse{Err: err.Error()})
+		} else if errors.Is(err, entity.ErrRefreshTokenReused) {
+			renderJSON(w, r, http.StatusUnauthorized, errorResponse{Err: err.Error()})
 		} else {
 			renderJSON(w, r, http.StatusInternalServerError, errorResponse{Err: err.Error()})
 		}
@@ -94,3 +109,38 @@ func (h *Handler) refresh(w http.ResponseWriter, r *http.Request) {
 	r.Header.Set("Set-Cookie", fmt.Sprintf("refresh-token=%s; HttpOnly", tokens.RefreshToken))
 	renderJSON(w, r, http.StatusOK, tokenResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
 }
+
+// @Summary Update user settings
+// @Description Update user's default time zone used for due dates without an offset
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body updateUserInput true "settings"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/user [patch]
+func (h *Handler) updateUser(w http.ResponseWriter, r *http.Request) {
+	var input updateUserInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.UpdateTimeZone(r.Context(), userId, input.TimeZone); err != nil {
+		if errors.Is(err, entity.ErrInvalidTimeZone) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "user updated successfully"})
+}
@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Verify email
+// @Description Confirm the email by the token of the link sent on sign up
+// @Tags auth
+// @Produce json
+// @Param token query string true "verification token"
+// @Success 200 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/verify [get]
+func (h *Handler) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidVerificationToken.Error()})
+		return
+	}
+
+	if err := h.service.VerifyEmail(r.Context(), token); err != nil {
+		if errors.Is(err, entity.ErrInvalidVerificationToken) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, successCUDResponse{Message: "email verified successfully"})
+}
+
+// @Summary Resend verification email
+// @Description Send a new verification link, the response is the same whether the email is registered or not
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body resendVerificationInput true "input"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /auth/verify/resend [post]
+func (h *Handler) resendVerification(w http.ResponseWriter, r *http.Request) {
+	var input resendVerificationInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	if err := h.service.ResendVerification(r.Context(), input.Email); err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "if the email is registered and unverified, a verification link has been sent to it"})
+}
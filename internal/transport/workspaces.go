@@ -0,0 +1,432 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pintoter/todo-list/internal/entity"
+)
+
+// @Summary Create workspace
+// @Description Create a workspace owned by the user, its notes and lists are used with the X-Workspace-Id header
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param input body workspaceInput true "input"
+// @Success 201 {object} entity.Workspace
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces [post]
+func (h *Handler) createWorkspace(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input workspaceInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	workspace, err := h.service.CreateWorkspace(r.Context(), userId, input.Name)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, workspace)
+}
+
+// @Summary Get workspaces
+// @Description Get workspaces the user is a member of with the role of the user
+// @Tags workspaces
+// @Produce json
+// @Success 200 {object} getWorkspacesResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces [get]
+func (h *Handler) getWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	workspaces, err := h.service.GetWorkspaces(r.Context(), userId)
+	if err != nil {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getWorkspacesResponse{Workspaces: workspaces})
+}
+
+// @Summary Update workspace
+// @Description Rename the workspace, admins and the owner can do it
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path int true "workspace id"
+// @Param input body workspaceInput true "input"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/{id} [patch]
+func (h *Handler) updateWorkspace(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input workspaceInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateWorkspace(r.Context(), id, userId, input.Name); err != nil {
+		if errors.Is(err, entity.ErrWorkspaceNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "workspace updated successfully"})
+}
+
+// @Summary Delete workspace
+// @Description Delete the workspace with its notes and lists, only the owner can do it
+// @Tags workspaces
+// @Produce json
+// @Param id path int true "workspace id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/{id} [delete]
+func (h *Handler) deleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteWorkspace(r.Context(), id, userId); err != nil {
+		if errors.Is(err, entity.ErrWorkspaceNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "workspace deleted successfully"})
+}
+
+// @Summary Get workspace members
+// @Description Get members of the workspace with their roles
+// @Tags workspaces
+// @Produce json
+// @Param id path int true "workspace id"
+// @Success 200 {object} getMembersResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/{id}/members [get]
+func (h *Handler) getWorkspaceMembers(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	members, err := h.service.GetWorkspaceMembers(r.Context(), id, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrWorkspaceNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getMembersResponse{Members: members})
+}
+
+// @Summary Update workspace member
+// @Description Change the role of the member, only the owner can grant or revoke admin
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path int true "workspace id"
+// @Param user_id path int true "member id"
+// @Param input body workspaceMemberInput true "input"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/{id}/members/{user_id} [patch]
+func (h *Handler) updateWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input workspaceMemberInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateWorkspaceMember(r.Context(), input.WorkspaceId, userId, input.UserId, input.Role); err != nil {
+		if errors.Is(err, entity.ErrInvalidWorkspaceRole) || errors.Is(err, entity.ErrWorkspaceOwner) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceNotExists) || errors.Is(err, entity.ErrWorkspaceMemberNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "member updated successfully"})
+}
+
+// @Summary Remove workspace member
+// @Description Remove the member from the workspace, members can leave the workspace themselves except for the owner
+// @Tags workspaces
+// @Produce json
+// @Param id path int true "workspace id"
+// @Param user_id path int true "member id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/{id}/members/{user_id} [delete]
+func (h *Handler) removeWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	memberId, _ := strconv.Atoi(mux.Vars(r)["user_id"])
+	if id == 0 || memberId == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.RemoveWorkspaceMember(r.Context(), id, userId, memberId); err != nil {
+		if errors.Is(err, entity.ErrWorkspaceOwner) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceNotExists) || errors.Is(err, entity.ErrWorkspaceMemberNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "member removed successfully"})
+}
+
+// @Summary Invite to workspace
+// @Description Email an invitation to join the workspace with the role, only the owner can invite admins
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path int true "workspace id"
+// @Param input body inviteInput true "input"
+// @Success 201 {object} entity.WorkspaceInvitation
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/{id}/invitations [post]
+func (h *Handler) inviteToWorkspace(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input inviteInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	invitation, err := h.service.InviteToWorkspace(r.Context(), input.WorkspaceId, userId, input.Email, input.Role)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidWorkspaceRole) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, invitation)
+}
+
+// @Summary Get workspace invitations
+// @Description Get pending invitations of the workspace, admins and the owner can see them
+// @Tags workspaces
+// @Produce json
+// @Param id path int true "workspace id"
+// @Success 200 {object} getInvitationsResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/{id}/invitations [get]
+func (h *Handler) getWorkspaceInvitations(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	if id == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	invitations, err := h.service.GetWorkspaceInvitations(r.Context(), id, userId)
+	if err != nil {
+		if errors.Is(err, entity.ErrWorkspaceNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, getInvitationsResponse{Invitations: invitations})
+}
+
+// @Summary Delete workspace invitation
+// @Description Revoke the pending invitation
+// @Tags workspaces
+// @Produce json
+// @Param id path int true "workspace id"
+// @Param invitation_id path int true "invitation id"
+// @Success 202 {object} successCUDResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/{id}/invitations/{invitation_id} [delete]
+func (h *Handler) deleteWorkspaceInvitation(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	invitationId, _ := strconv.Atoi(mux.Vars(r)["invitation_id"])
+	if id == 0 || invitationId == 0 {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{entity.ErrInvalidId.Error()})
+		return
+	}
+
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	if err := h.service.DeleteWorkspaceInvitation(r.Context(), invitationId, id, userId); err != nil {
+		if errors.Is(err, entity.ErrWorkspaceNotExists) || errors.Is(err, entity.ErrInvitationNotExists) {
+			renderJSON(w, r, http.StatusNotFound, errorResponse{err.Error()})
+		} else if errors.Is(err, entity.ErrWorkspaceForbidden) {
+			renderJSON(w, r, http.StatusForbidden, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, successCUDResponse{Message: "invitation deleted successfully"})
+}
+
+// @Summary Accept workspace invitation
+// @Description Join the workspace by the token of the invitation sent to the email of the user
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param input body acceptInvitationInput true "input"
+// @Success 200 {object} entity.WorkspaceMember
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /api/v1/workspaces/invitations/accept [post]
+func (h *Handler) acceptWorkspaceInvitation(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("user_id").(int)
+	if !ok {
+		renderJSON(w, r, http.StatusInternalServerError, errorResponse{"bad userID"})
+		return
+	}
+
+	var input acceptInvitationInput
+	if err := input.Set(r); err != nil {
+		renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	member, err := h.service.AcceptWorkspaceInvitation(r.Context(), userId, input.Token)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvitationNotExists) {
+			renderJSON(w, r, http.StatusBadRequest, errorResponse{err.Error()})
+		} else {
+			renderJSON(w, r, http.StatusInternalServerError, errorResponse{err.Error()})
+		}
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, member)
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notes_user_id_priority;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS priority;
+
+DROP TYPE IF EXISTS note_priority;
@@ -0,0 +1,5 @@
+CREATE TYPE note_priority AS ENUM ('low', 'medium', 'high', 'urgent');
+
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS priority note_priority NOT NULL DEFAULT 'medium';
+
+CREATE INDEX IF NOT EXISTS idx_notes_user_id_priority ON notes(user_id, priority);
@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN IF EXISTS time_zone;
+
+DROP INDEX IF EXISTS idx_notes_user_id_due_at;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS due_at;
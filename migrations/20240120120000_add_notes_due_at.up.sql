@@ -0,0 +1,5 @@
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_notes_user_id_due_at ON notes(user_id, due_at);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1 @@
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS recurrence JSONB;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS note_items (
+    id SERIAL PRIMARY KEY,
+    note_id INT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    checked BOOLEAN NOT NULL DEFAULT false,
+    position INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_items_note_id_position ON note_items(note_id, position);
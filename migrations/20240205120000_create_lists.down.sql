@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notes_list_id;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS list_id;
+
+DROP TABLE IF EXISTS lists;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS lists (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS list_id INT REFERENCES lists(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notes_list_id ON notes(list_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS note_revisions (
+    id SERIAL PRIMARY KEY,
+    note_id INT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(16) NOT NULL,
+    changes JSONB NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_revisions_note_id ON note_revisions(note_id, id);
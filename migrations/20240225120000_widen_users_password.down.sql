@@ -0,0 +1 @@
+ALTER TABLE users ALTER COLUMN password TYPE VARCHAR(80);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token VARCHAR(255) UNIQUE NOT NULL,
+    device VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+INSERT INTO sessions (user_id, refresh_token, expires_at)
+SELECT id, refresh_token, expires_at FROM users
+WHERE refresh_token IS NOT NULL AND expires_at IS NOT NULL;
+
+ALTER TABLE users DROP COLUMN IF EXISTS refresh_token;
+ALTER TABLE users DROP COLUMN IF EXISTS expires_at;
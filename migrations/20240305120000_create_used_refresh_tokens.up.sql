@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS used_refresh_tokens (
+    refresh_token VARCHAR(255) PRIMARY KEY,
+    session_id INT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    used_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_used_refresh_tokens_session_id ON used_refresh_tokens(session_id);
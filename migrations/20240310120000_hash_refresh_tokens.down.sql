@@ -0,0 +1,2 @@
+-- hashed refresh tokens can't be restored, so the sessions are ended
+DELETE FROM sessions;
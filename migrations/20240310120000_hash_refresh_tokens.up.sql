@@ -0,0 +1,2 @@
+UPDATE sessions SET refresh_token = encode(sha256(convert_to(refresh_token, 'UTF8')), 'hex');
+UPDATE used_refresh_tokens SET refresh_token = encode(sha256(convert_to(refresh_token, 'UTF8')), 'hex');
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS note_shares (
+    note_id INT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (note_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_shares_user_id ON note_shares(user_id);
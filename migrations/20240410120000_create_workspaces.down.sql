@@ -0,0 +1,13 @@
+DELETE FROM notes WHERE workspace_id IS NOT NULL;
+DELETE FROM lists WHERE workspace_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_lists_workspace_id_name;
+DROP INDEX IF EXISTS idx_lists_user_id_name;
+ALTER TABLE lists ADD CONSTRAINT lists_user_id_name_key UNIQUE (user_id, name);
+
+ALTER TABLE lists DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE notes DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
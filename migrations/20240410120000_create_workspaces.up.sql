@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS workspaces (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id INT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user_id ON workspace_members(user_id);
+
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id SERIAL PRIMARY KEY,
+    workspace_id INT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    invited_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_invitations_workspace_id ON workspace_invitations(workspace_id);
+
+-- notes and lists without a workspace are personal, user_id stays their creator
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS workspace_id INT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE lists ADD COLUMN IF NOT EXISTS workspace_id INT REFERENCES workspaces(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_notes_workspace_id ON notes(workspace_id);
+
+ALTER TABLE lists DROP CONSTRAINT IF EXISTS lists_user_id_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lists_user_id_name ON lists(user_id, name) WHERE workspace_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lists_workspace_id_name ON lists(workspace_id, name) WHERE workspace_id IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notes_assignee_id;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS assignee_id;
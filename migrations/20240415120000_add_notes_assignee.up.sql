@@ -0,0 +1,3 @@
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS assignee_id INT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notes_assignee_id ON notes(assignee_id) WHERE assignee_id IS NOT NULL;
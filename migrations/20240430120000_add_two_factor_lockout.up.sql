@@ -0,0 +1,2 @@
+ALTER TABLE two_factor ADD COLUMN IF NOT EXISTS failed_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE two_factor ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
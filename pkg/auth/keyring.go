@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoSigningKey   = errors.New("no signing key is valid now")
+	ErrUnknownKey     = errors.New("unknown or expired signing key")
+	ErrUnsupportedKey = errors.New("unsupported key, expected RSA or Ed25519")
+)
+
+// KeyFile describes a PEM file of a signing key. The key signs tokens from NotBefore until a key with
+// a later NotBefore takes over and verifies them until NotAfter, zero NotAfter means the key
+// never expires. Windows of successive keys should overlap at least by the access token TTL,
+// so tokens signed by the previous key stay valid until they expire. A file holding only
+// a public key verifies tokens but never signs them.
+type KeyFile struct {
+	ID        string
+	Path      string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// Key is a signing key loaded from a KeyFile, private is nil for verification only keys.
+type Key struct {
+	ID        string
+	NotBefore time.Time
+	NotAfter  time.Time
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+}
+
+func (k Key) validAt(t time.Time) bool {
+	return !t.Before(k.NotBefore) && (k.NotAfter.IsZero() || t.Before(k.NotAfter))
+}
+
+// KeyRing holds keys used to sign and verify access tokens, keys are told apart by the kid header.
+type KeyRing struct {
+	keys []Key
+}
+
+// LoadKeyRing reads RSA (RS256) and Ed25519 (EdDSA) keys from PEM files. Private keys are accepted
+// in PKCS #8 or PKCS #1 form, public keys in PKIX form.
+func LoadKeyRing(files []KeyFile) (*KeyRing, error) {
+	ring := &KeyRing{keys: make([]Key, 0, len(files))}
+	ids := make(map[string]struct{}, len(files))
+
+	for _, file := range files {
+		if file.ID == "" {
+			return nil, fmt.Errorf("key %s: missing id", file.Path)
+		}
+
+		if _, ok := ids[file.ID]; ok {
+			return nil, fmt.Errorf("key %s: duplicate id", file.ID)
+		}
+		ids[file.ID] = struct{}{}
+
+		if !file.NotAfter.IsZero() && !file.NotAfter.After(file.NotBefore) {
+			return nil, fmt.Errorf("key %s: notAfter must be after notBefore", file.ID)
+		}
+
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", file.ID, err)
+		}
+
+		key, err := parseKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", file.ID, err)
+		}
+
+		key.ID, key.NotBefore, key.NotAfter = file.ID, file.NotBefore, file.NotAfter
+		ring.keys = append(ring.keys, key)
+	}
+
+	return ring, nil
+}
+
+func parseKey(data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("no PEM data found")
+	}
+
+	var (
+		parsed any
+		err    error
+	)
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return Key{method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return Key{method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case *rsa.PublicKey:
+		return Key{method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PublicKey:
+		return Key{method: jwt.SigningMethodEdDSA, public: k}, nil
+	}
+
+	return Key{}, ErrUnsupportedKey
+}
+
+// signingKey returns the private key valid at the time which has taken over last.
+func (r *KeyRing) signingKey(t time.Time) (Key, error) {
+	var (
+		signing Key
+		found   bool
+	)
+
+	for _, key := range r.keys {
+		if key.private == nil || !key.validAt(t) {
+			continue
+		}
+
+		if !found || key.NotBefore.After(signing.NotBefore) {
+			signing, found = key, true
+		}
+	}
+
+	if !found {
+		return Key{}, ErrNoSigningKey
+	}
+
+	return signing, nil
+}
+
+// verificationKey returns the key with the id valid at the time.
+func (r *KeyRing) verificationKey(id string, t time.Time) (Key, error) {
+	for _, key := range r.keys {
+		if key.ID == id && key.validAt(t) {
+			return key, nil
+		}
+	}
+
+	return Key{}, ErrUnknownKey
+}
+
+// JWK is a public key in the JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a set of public keys verifiers can check access tokens with.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns public keys which haven't expired by the time, upcoming keys are published
+// ahead, so verifiers know them before they start signing.
+func (r *KeyRing) JWKS(t time.Time) JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(r.keys))}
+
+	for _, key := range r.keys {
+		if !key.NotAfter.IsZero() && !t.Before(key.NotAfter) {
+			continue
+		}
+
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.method.Alg()}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func rsaKeyFile(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+func ed25519KeyFiles(t *testing.T) (private, public string) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDer, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	return writePEM(t, "ed25519.pem", "PRIVATE KEY", der), writePEM(t, "ed25519.pub", "PUBLIC KEY", pubDer)
+}
+
+func tokenKid(t *testing.T, token string) string {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &tokenClaims{})
+	require.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	now := time.Now()
+	edPrivate, _ := ed25519KeyFiles(t)
+
+	ring, err := LoadKeyRing([]KeyFile{
+		{ID: "old", Path: rsaKeyFile(t), NotBefore: now.Add(-48 * time.Hour), NotAfter: now.Add(time.Hour)},
+		{ID: "current", Path: edPrivate, NotBefore: now.Add(-time.Hour)},
+		{ID: "next", Path: rsaKeyFile(t), NotBefore: now.Add(24 * time.Hour)},
+	})
+	require.NoError(t, err)
+
+	m := NewManager(config{}, ring)
+
+	token, err := m.NewJWT(1, 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "current", tokenKid(t, token), "the key which has taken over last must sign")
+
+	claims, err := m.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claims.UserId)
+
+	old, err := ring.signingKey(now.Add(-2 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "old", old.ID)
+
+	oldToken, err := jwt.NewWithClaims(old.method, newTestClaims(now)).SignedString(old.private)
+	require.NoError(t, err)
+	_, err = m.ParseToken(withKid(t, old, newTestClaims(now)))
+	assert.NoError(t, err, "tokens of the previous key must be valid within the overlap")
+	_, err = m.ParseToken(oldToken)
+	assert.Error(t, err, "tokens without kid must be rejected")
+
+	next, err := ring.signingKey(now.Add(25 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "next", next.ID)
+	_, err = m.ParseToken(withKid(t, next, newTestClaims(now)))
+	assert.ErrorIs(t, err, ErrUnknownKey, "upcoming key must not verify tokens yet")
+
+	set := m.JWKS()
+	require.Len(t, set.Keys, 3)
+	assert.Equal(t, JWK{Kty: "RSA", Kid: "old", Use: "sig", Alg: "RS256", N: set.Keys[0].N, E: "AQAB"}, set.Keys[0])
+	assert.Equal(t, "OKP", set.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", set.Keys[1].Crv)
+	assert.Equal(t, "EdDSA", set.Keys[1].Alg)
+	assert.Len(t, set.Keys[1].X, 43)
+
+	assert.Len(t, ring.JWKS(now.Add(2*time.Hour)).Keys, 2, "expired keys must not be published")
+}
+
+func TestKeyRing_RejectsForeignTokens(t *testing.T) {
+	edPrivate, edPublic := ed25519KeyFiles(t)
+
+	ring, err := LoadKeyRing([]KeyFile{{ID: "key", Path: edPrivate}})
+	require.NoError(t, err)
+	m := NewManager(config{}, ring)
+
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, newTestClaims(time.Now()))
+	hmac.Header["kid"] = "key"
+	token, err := hmac.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = m.ParseToken(token)
+	assert.Error(t, err, "HS256 tokens must be rejected once keys are configured")
+
+	other, err := LoadKeyRing([]KeyFile{{ID: "key", Path: rsaKeyFile(t)}})
+	require.NoError(t, err)
+	token, err = NewManager(config{}, other).NewJWT(1, 2, time.Minute)
+	require.NoError(t, err)
+
+	_, err = m.ParseToken(token)
+	assert.Error(t, err, "tokens signed by another key with the same kid must be rejected")
+
+	verifier, err := LoadKeyRing([]KeyFile{{ID: "key", Path: edPublic}})
+	require.NoError(t, err)
+	token, err = m.NewJWT(1, 2, time.Minute)
+	require.NoError(t, err)
+
+	_, err = NewManager(config{}, verifier).ParseToken(token)
+	assert.NoError(t, err, "public key must verify tokens")
+	_, err = NewManager(config{}, verifier).NewJWT(1, 2, time.Minute)
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestLoadKeyRing_Invalid(t *testing.T) {
+	now := time.Now()
+	path := rsaKeyFile(t)
+
+	_, err := LoadKeyRing([]KeyFile{{ID: "a", Path: path}, {ID: "a", Path: path}})
+	assert.ErrorContains(t, err, "duplicate id")
+
+	_, err = LoadKeyRing([]KeyFile{{ID: "a", Path: path, NotBefore: now, NotAfter: now}})
+	assert.ErrorContains(t, err, "notAfter")
+
+	_, err = LoadKeyRing([]KeyFile{{ID: "a", Path: writePEM(t, "cert.pem", "CERTIFICATE", []byte("x"))}})
+	assert.Error(t, err)
+}
+
+func newTestClaims(now time.Time) *tokenClaims {
+	return &tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "id",
+			Subject:   "1",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+func withKid(t *testing.T, key Key, claims *tokenClaims) string {
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.private)
+	require.NoError(t, err)
+	return signed
+}
@@ -1,9 +1,12 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"math/rand"
 	"strconv"
 	"time"
 
@@ -15,67 +18,162 @@ type Config interface {
 }
 
 type TokenManager interface {
-	NewJWT(userId int, ttl time.Duration) (string, error)
-	ParseToken(accessToken string) (string, error)
+	NewJWT(userId, sessionId int, ttl time.Duration) (string, error)
+	ParseToken(accessToken string) (Claims, error)
 	NewRefreshToken() (string, error)
+	JWKS() JWKS
 }
 
+// Claims identify the user and the session an access token was issued for,
+// ID is the unique token id (jti) used to revoke the token before it expires.
+type Claims struct {
+	ID        string
+	UserId    int
+	SessionId int
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	SessionId int `json:"sid"`
+}
+
+// Manager signs access tokens with keys of the key ring, HS256 with the shared secret is used
+// while no key ring is given.
 type Manager struct {
 	secret string
+	keys   *KeyRing
 }
 
-func NewManager(cfg Config) *Manager {
+func NewManager(cfg Config, keys *KeyRing) *Manager {
 	return &Manager{
 		secret: cfg.GetSecret(),
+		keys:   keys,
 	}
 }
 
-func (m *Manager) NewJWT(userId int, ttl time.Duration) (string, error) {
-	claims := &jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
-		Subject:   strconv.Itoa(userId),
+func (m *Manager) NewJWT(userId, sessionId int, ttl time.Duration) (string, error) {
+	id, err := newTokenId()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := &tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			Subject:   strconv.Itoa(userId),
+		},
+		SessionId: sessionId,
+	}
+
+	if m.keys == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(m.secret))
+	}
+
+	key, err := m.keys.signingKey(now)
+	if err != nil {
+		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.ID
 
-	return token.SignedString([]byte(m.secret))
+	return token.SignedString(key.private)
 }
 
-func (m *Manager) ParseToken(accessToken string) (int, error) {
-	token, err := jwt.Parse(accessToken, func(t *jwt.Token) (interface{}, error) {
+func (m *Manager) ParseToken(accessToken string) (Claims, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(accessToken, &claims, m.verificationKey)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return Claims{}, errors.New("invalid subject")
+	}
+
+	if claims.ID == "" || claims.IssuedAt == nil || claims.ExpiresAt == nil {
+		return Claims{}, errors.New("missing token id")
+	}
+
+	return Claims{
+		ID:        claims.ID,
+		UserId:    id,
+		SessionId: claims.SessionId,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// verificationKey picks the key the token is checked with by its kid header.
+func (m *Manager) verificationKey(t *jwt.Token) (interface{}, error) {
+	if m.keys == nil {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
 
-		return m.secret, nil
-	})
+		return []byte(m.secret), nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key, err := m.keys.verificationKey(kid, time.Now())
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return 0, errors.New("invalid claims")
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 	}
 
-	id, err := strconv.Atoi(claims["sub"].(string))
-	if err != nil {
-		return 0, errors.New("invalid subject")
+	return key.public, nil
+}
+
+// JWKS returns public keys access tokens can be verified with, the set is empty for HS256.
+func (m *Manager) JWKS() JWKS {
+	if m.keys == nil {
+		return JWKS{Keys: []JWK{}}
 	}
 
-	return id, nil
+	return m.keys.JWKS(time.Now())
 }
 
-func (m *Manager) NewRefreshToken() (string, error) {
-	b := make([]byte, 32)
+func newTokenId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
 
-	s := rand.NewSource(time.Now().Unix())
-	r := rand.New(s)
+	return hex.EncodeToString(b), nil
+}
 
-	_, err := r.Read(b)
-	if err != nil {
+// NewRefreshToken returns a random opaque token, only its hash should be stored.
+func (m *Manager) NewRefreshToken() (string, error) {
+	return NewToken()
+}
+
+// NewToken returns a random opaque token for refresh tokens and links sent by email.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("%x", b), nil
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken returns SHA-256 of an opaque token, the tokens are random
+// enough to be stored this way without a salt.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CompareToken reports whether the hash is of the token in constant time.
+func CompareToken(token, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(hash)) == 1
 }
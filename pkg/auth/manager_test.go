@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type config struct{}
+
+func (config) GetSecret() string { return "secret" }
+
+func TestManager_NewRefreshToken_Concurrent(t *testing.T) {
+	m := NewManager(config{}, nil)
+
+	const n = 200
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		tokens = make(map[string]struct{}, n)
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			token, err := m.NewRefreshToken()
+			assert.NoError(t, err)
+			assert.Len(t, token, 64)
+
+			mu.Lock()
+			tokens[token] = struct{}{}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, tokens, n, "refresh tokens must be unique")
+}
+
+func TestHashToken(t *testing.T) {
+	hash := HashToken("token")
+	assert.Equal(t, "3c469e9d6c5875d37a43f353d4f88e61fcf812c66eee3457465a40b0da4153e0", hash)
+
+	assert.True(t, CompareToken("token", hash))
+	assert.False(t, CompareToken("token2", hash))
+	assert.False(t, CompareToken("token", "token"))
+}
+
+func TestManager_ParseToken(t *testing.T) {
+	m := NewManager(config{}, nil)
+
+	token, err := m.NewJWT(1, 2, time.Minute)
+	assert.NoError(t, err)
+
+	claims, err := m.ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, claims.UserId)
+	assert.Equal(t, 2, claims.SessionId)
+	assert.NotEmpty(t, claims.ID)
+
+	other, err := m.NewJWT(1, 2, time.Minute)
+	assert.NoError(t, err)
+
+	otherClaims, err := m.ParseToken(other)
+	assert.NoError(t, err)
+	assert.NotEqual(t, claims.ID, otherClaims.ID)
+
+	_, err = NewManager(otherConfig{}, nil).ParseToken(token)
+	assert.Error(t, err)
+}
+
+type otherConfig struct{}
+
+func (otherConfig) GetSecret() string { return "other" }
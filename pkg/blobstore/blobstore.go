@@ -0,0 +1,26 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps blobs by slash separated keys.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get reads length bytes of the blob starting at offset, length <= 0 reads until the end.
+	Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Delete removes the blob, deleting a missing blob isn't an error.
+	Delete(ctx context.Context, key string) error
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,147 @@
+package denylist
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Storage keeps revoked access tokens until they expire. Tokens are revoked one by one by id (jti),
+// all tokens of a session by its id or all at once for a user by the time they were issued before.
+// Entries are needed only while revoked tokens are still valid, so each one is stored with a TTL.
+type Storage interface {
+	RevokeToken(ctx context.Context, id string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+	RevokeSessionTokens(ctx context.Context, sessionId int, ttl time.Duration) error
+	IsSessionRevoked(ctx context.Context, sessionId int) (bool, error)
+	RevokeUserTokens(ctx context.Context, userId int, issuedBefore time.Time, ttl time.Duration) error
+	UserTokensRevokedBefore(ctx context.Context, userId int) (time.Time, error)
+}
+
+type userRevocation struct {
+	issuedBefore time.Time
+	expiresAt    time.Time
+}
+
+// Memory is a Storage kept in the process memory, revocations are lost on restart
+// and aren't shared between instances of the app.
+type Memory struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	tokens   map[string]time.Time
+	sessions map[int]time.Time
+	users    map[int]userRevocation
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		now:      time.Now,
+		tokens:   make(map[string]time.Time),
+		sessions: make(map[int]time.Time),
+		users:    make(map[int]userRevocation),
+	}
+}
+
+func (m *Memory) RevokeToken(_ context.Context, id string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if ttl > 0 {
+		m.tokens[id] = now.Add(ttl)
+	}
+
+	return nil
+}
+
+func (m *Memory) IsTokenRevoked(_ context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt, ok := m.tokens[id]
+
+	return ok && m.now().Before(expiresAt), nil
+}
+
+// RevokeSessionTokens revokes all tokens issued for the session.
+func (m *Memory) RevokeSessionTokens(_ context.Context, sessionId int, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if ttl > 0 {
+		m.sessions[sessionId] = now.Add(ttl)
+	}
+
+	return nil
+}
+
+func (m *Memory) IsSessionRevoked(_ context.Context, sessionId int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt, ok := m.sessions[sessionId]
+
+	return ok && m.now().Before(expiresAt), nil
+}
+
+// RevokeUserTokens revokes tokens of the user issued before the time, an earlier revocation
+// is kept if it covers more tokens.
+func (m *Memory) RevokeUserTokens(_ context.Context, userId int, issuedBefore time.Time, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if ttl <= 0 {
+		return nil
+	}
+
+	if prev, ok := m.users[userId]; ok && prev.issuedBefore.After(issuedBefore) {
+		issuedBefore = prev.issuedBefore
+	}
+
+	m.users[userId] = userRevocation{issuedBefore: issuedBefore, expiresAt: now.Add(ttl)}
+
+	return nil
+}
+
+// UserTokensRevokedBefore returns the time tokens of the user issued before are revoked,
+// zero time means there is no revocation.
+func (m *Memory) UserTokensRevokedBefore(_ context.Context, userId int) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	revocation, ok := m.users[userId]
+	if !ok || !m.now().Before(revocation.expiresAt) {
+		return time.Time{}, nil
+	}
+
+	return revocation.issuedBefore, nil
+}
+
+// sweep drops expired entries, it's called on writes so the storage doesn't grow unbounded.
+func (m *Memory) sweep(now time.Time) {
+	for id, expiresAt := range m.tokens {
+		if !now.Before(expiresAt) {
+			delete(m.tokens, id)
+		}
+	}
+
+	for sessionId, expiresAt := range m.sessions {
+		if !now.Before(expiresAt) {
+			delete(m.sessions, sessionId)
+		}
+	}
+
+	for userId, revocation := range m.users {
+		if !now.Before(revocation.expiresAt) {
+			delete(m.users, userId)
+		}
+	}
+}
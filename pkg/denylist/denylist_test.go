@@ -0,0 +1,88 @@
+package denylist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemory_Tokens(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	assert.NoError(t, m.RevokeToken(ctx, "a", time.Minute))
+
+	revoked, err := m.IsTokenRevoked(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, _ = m.IsTokenRevoked(ctx, "b")
+	assert.False(t, revoked)
+
+	now = now.Add(time.Minute)
+	revoked, _ = m.IsTokenRevoked(ctx, "a")
+	assert.False(t, revoked, "token must be forgotten once it expires")
+
+	assert.NoError(t, m.RevokeToken(ctx, "b", time.Minute))
+	assert.NotContains(t, m.tokens, "a", "expired entries must be swept")
+	assert.Contains(t, m.tokens, "b")
+
+	assert.NoError(t, m.RevokeToken(ctx, "c", 0))
+	assert.NotContains(t, m.tokens, "c", "expired token needn't be stored")
+}
+
+func TestMemory_Sessions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	assert.NoError(t, m.RevokeSessionTokens(ctx, 1, time.Minute))
+
+	revoked, err := m.IsSessionRevoked(ctx, 1)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, _ = m.IsSessionRevoked(ctx, 2)
+	assert.False(t, revoked)
+
+	now = now.Add(time.Minute)
+	revoked, _ = m.IsSessionRevoked(ctx, 1)
+	assert.False(t, revoked, "session must be forgotten once its tokens expire")
+
+	assert.NoError(t, m.RevokeSessionTokens(ctx, 2, time.Minute))
+	assert.NotContains(t, m.sessions, 1, "expired entries must be swept")
+}
+
+func TestMemory_UserTokens(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	before, err := m.UserTokensRevokedBefore(ctx, 1)
+	assert.NoError(t, err)
+	assert.True(t, before.IsZero())
+
+	assert.NoError(t, m.RevokeUserTokens(ctx, 1, now, time.Minute))
+	before, _ = m.UserTokensRevokedBefore(ctx, 1)
+	assert.Equal(t, now, before)
+
+	before, _ = m.UserTokensRevokedBefore(ctx, 2)
+	assert.True(t, before.IsZero())
+
+	assert.NoError(t, m.RevokeUserTokens(ctx, 1, now.Add(-time.Hour), time.Minute))
+	before, _ = m.UserTokensRevokedBefore(ctx, 1)
+	assert.Equal(t, now, before, "later revocation must be kept")
+
+	now = now.Add(2 * time.Minute)
+	before, _ = m.UserTokensRevokedBefore(ctx, 1)
+	assert.True(t, before.IsZero())
+}
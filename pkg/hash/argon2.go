@@ -0,0 +1,122 @@
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2Prefix = "$argon2id$"
+
+	defaultHashTime    = 1
+	defaultHashMemory  = 64 * 1024
+	defaultHashThreads = 4
+
+	saltLength = 16
+	keyLength  = 32
+)
+
+var ErrInvalidHash = errors.New("invalid password hash")
+
+type argon2Params struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// Argon2Hash hashes passwords with Argon2id and a random per-password salt, the result
+// is encoded in the PHC string format: $argon2id$v=19$m=65536,t=1,p=4$<salt>$<key>.
+type Argon2Hash struct {
+	params argon2Params
+}
+
+// NewArgon2 makes a hasher with cost parameters of the config, zero values are replaced with defaults.
+func NewArgon2(cfg Config) *Argon2Hash {
+	params := argon2Params{
+		time:    cfg.GetHashTime(),
+		memory:  cfg.GetHashMemory(),
+		threads: cfg.GetHashThreads(),
+	}
+
+	if params.time == 0 {
+		params.time = defaultHashTime
+	}
+
+	if params.memory == 0 {
+		params.memory = defaultHashMemory
+	}
+
+	if params.threads == 0 {
+		params.threads = defaultHashThreads
+	}
+
+	return &Argon2Hash{params: params}
+}
+
+func (h *Argon2Hash) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.time, h.params.memory, h.params.threads, keyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2Prefix, argon2.Version,
+		h.params.memory, h.params.time, h.params.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2Hash) Verify(password, hash string) (bool, error) {
+	params, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		return false, err
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
+
+// NeedsRehash reports whether the hash isn't an Argon2id one or its cost parameters differ from the current.
+func (h *Argon2Hash) NeedsRehash(hash string) bool {
+	params, _, _, err := decodeArgon2(hash)
+
+	return err != nil || params != h.params
+}
+
+func decodeArgon2(hash string) (argon2Params, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(hash, argon2Prefix), "$")
+	if !strings.HasPrefix(hash, argon2Prefix) || len(parts) != 4 {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	var params argon2Params
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	return params, salt, key, nil
+}
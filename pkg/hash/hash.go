@@ -1,34 +1,47 @@
 package hash
 
-import (
-	"crypto/sha1"
-	"fmt"
-)
+import "strings"
 
 type Hasher interface {
 	Hash(password string) (string, error)
+	Verify(password, hash string) (bool, error)
+	NeedsRehash(hash string) bool
 }
 
 type Config interface {
 	GetSalt() string
+	GetHashTime() uint32
+	GetHashMemory() uint32
+	GetHashThreads() uint8
 }
 
-type SHA1Hash struct {
-	salt string
+// PasswordHash hashes passwords with Argon2id and verifies both Argon2id hashes
+// and legacy SHA1 ones, the latter have to be rehashed on the next sign in.
+type PasswordHash struct {
+	argon2 *Argon2Hash
+	legacy *SHA1Hash
 }
 
-func New(cfg Config) *SHA1Hash {
-	return &SHA1Hash{
-		salt: cfg.GetSalt(),
+func New(cfg Config) *PasswordHash {
+	return &PasswordHash{
+		argon2: NewArgon2(cfg),
+		legacy: NewSHA1(cfg),
 	}
 }
 
-func (h *SHA1Hash) Hash(password string) (string, error) {
-	hasher := sha1.New()
+func (h *PasswordHash) Hash(password string) (string, error) {
+	return h.argon2.Hash(password)
+}
 
-	if _, err := hasher.Write([]byte(password)); err != nil {
-		return "", err
+func (h *PasswordHash) Verify(password, hash string) (bool, error) {
+	if strings.HasPrefix(hash, argon2Prefix) {
+		return h.argon2.Verify(password, hash)
 	}
 
-	return fmt.Sprintf("%x", hasher.Sum([]byte(h.salt))), nil
+	return h.legacy.Verify(password, hash)
+}
+
+// NeedsRehash reports whether the hash is a legacy one or was made with outdated cost parameters.
+func (h *PasswordHash) NeedsRehash(hash string) bool {
+	return h.argon2.NeedsRehash(hash)
 }
@@ -0,0 +1,64 @@
+package hash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type config struct {
+	salt    string
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+func (c config) GetSalt() string       { return c.salt }
+func (c config) GetHashTime() uint32   { return c.time }
+func (c config) GetHashMemory() uint32 { return c.memory }
+func (c config) GetHashThreads() uint8 { return c.threads }
+
+func TestPasswordHash(t *testing.T) {
+	cfg := config{salt: "salt", time: 1, memory: 1024, threads: 1}
+	h := New(cfg)
+
+	hash, err := h.Hash("password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.False(t, h.NeedsRehash(hash))
+
+	other, err := h.Hash("password")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other, "salt must be random")
+
+	ok, err := h.Verify("password", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify("wrong", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.True(t, New(config{salt: "salt", time: 2, memory: 1024, threads: 1}).NeedsRehash(hash))
+
+	_, err = h.Verify("password", "$argon2id$v=19$m=1024,t=1,p=1$broken")
+	assert.ErrorIs(t, err, ErrInvalidHash)
+}
+
+func TestPasswordHash_Legacy(t *testing.T) {
+	cfg := config{salt: "salt", time: 1, memory: 1024, threads: 1}
+	h := New(cfg)
+
+	legacy, err := NewSHA1(cfg).Hash("password")
+	assert.NoError(t, err)
+	assert.True(t, h.NeedsRehash(legacy))
+
+	ok, err := h.Verify("password", legacy)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify("wrong", legacy)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
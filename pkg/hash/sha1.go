@@ -0,0 +1,42 @@
+package hash
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"fmt"
+)
+
+// SHA1Hash is the legacy unsalted hasher, it's kept only to verify hashes
+// stored before Argon2id was introduced.
+type SHA1Hash struct {
+	salt string
+}
+
+func NewSHA1(cfg Config) *SHA1Hash {
+	return &SHA1Hash{
+		salt: cfg.GetSalt(),
+	}
+}
+
+func (h *SHA1Hash) Hash(password string) (string, error) {
+	hasher := sha1.New()
+
+	if _, err := hasher.Write([]byte(password)); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", hasher.Sum([]byte(h.salt))), nil
+}
+
+func (h *SHA1Hash) Verify(password, hash string) (bool, error) {
+	expected, err := h.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(hash)) == 1, nil
+}
+
+func (h *SHA1Hash) NeedsRehash(hash string) bool {
+	return true
+}
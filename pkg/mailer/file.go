@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/pintoter/todo-list/pkg/logger"
+)
+
+// File writes every message to a separate .eml file of the directory instead of sending it,
+// it's meant for local development and tests.
+type File struct {
+	dir  string
+	from string
+	seq  atomic.Int64
+}
+
+func NewFile(dir, from string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &File{dir: dir, from: from}, nil
+}
+
+func (m *File) Send(_ context.Context, msg Message) error {
+	name := fmt.Sprintf("%d-%d.eml", time.Now().UnixNano(), m.seq.Add(1))
+
+	return os.WriteFile(filepath.Join(m.dir, name), format(m.from, msg), 0o600)
+}
+
+// Log writes recipients and subjects of messages to the log instead of sending them, it's meant for
+// local development. Bodies aren't logged since they carry reset tokens and verification links.
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (Log) Send(ctx context.Context, msg Message) error {
+	logger.InfoKV(ctx, "Mail", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
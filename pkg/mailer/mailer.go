@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/smtp"
+	"strings"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type Config interface {
+	GetHost() string
+	GetPort() string
+	GetUsername() string
+	GetPassword() string
+	GetFrom() string
+}
+
+// SMTP sends messages through an SMTP server, PLAIN auth is used when the username is set.
+type SMTP struct {
+	addr     string
+	auth     smtp.Auth
+	from     string
+	envelope string
+}
+
+func NewSMTP(cfg Config) *SMTP {
+	var auth smtp.Auth
+	if cfg.GetUsername() != "" {
+		auth = smtp.PlainAuth("", cfg.GetUsername(), cfg.GetPassword(), cfg.GetHost())
+	}
+
+	// the sender may have a display name which isn't allowed in the envelope
+	envelope := cfg.GetFrom()
+	if from, err := mail.ParseAddress(envelope); err == nil {
+		envelope = from.Address
+	}
+
+	return &SMTP{
+		addr:     cfg.GetHost() + ":" + cfg.GetPort(),
+		auth:     auth,
+		from:     cfg.GetFrom(),
+		envelope: envelope,
+	}
+}
+
+func (m *SMTP) Send(_ context.Context, msg Message) error {
+	return smtp.SendMail(m.addr, m.auth, m.envelope, []string{msg.To}, format(m.from, msg))
+}
+
+// format renders the message in RFC 5322 form, header values are stripped of line breaks.
+func format(from string, msg Message) []byte {
+	clean := strings.NewReplacer("\r", "", "\n", "")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", clean.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", clean.Replace(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", clean.Replace(msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+
+	m, err := NewFile(dir, "todo@example.com")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, m.Send(context.Background(), Message{To: "user@example.com", Subject: "Hi", Body: "Hello"}))
+		}()
+	}
+	wg.Wait()
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 10)
+}
+
+func TestFormat(t *testing.T) {
+	got := string(format("todo@example.com", Message{
+		To:      "user@example.com\r\nBcc: other@example.com",
+		Subject: "Reset\npassword",
+		Body:    "line 1\nline 2",
+	}))
+
+	assert.Equal(t, "From: todo@example.com\r\n"+
+		"To: user@example.comBcc: other@example.com\r\n"+
+		"Subject: Resetpassword\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n"+
+		"line 1\r\nline 2", got)
+}
@@ -0,0 +1,68 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 key of the test vectors of RFC 6238.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "time %d", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	step, ok := Validate(rfcSecret, "050471", now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	_, ok = Validate(rfcSecret, "050471", now.Add(Period))
+	assert.True(t, ok, "previous code must be accepted within the skew")
+
+	_, ok = Validate(rfcSecret, "050471", now.Add(3*Period))
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "000000", now)
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "50471", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	uri := URI("todo-list", "user@example.com", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/todo-list:user@example.com?"))
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=todo-list")
+}